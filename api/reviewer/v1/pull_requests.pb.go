@@ -0,0 +1,594 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: reviewer/v1/pull_requests.proto
+
+package reviewerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreatePullRequestRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId   string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId        string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	// repository and changed_files pick the code owners of the changed paths as reviewers.
+	Repository    string   `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
+	ChangedFiles  []string `protobuf:"bytes,5,rep,name=changed_files,json=changedFiles,proto3" json:"changed_files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePullRequestRequest) Reset() {
+	*x = CreatePullRequestRequest{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePullRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePullRequestRequest) ProtoMessage() {}
+
+func (x *CreatePullRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePullRequestRequest.ProtoReflect.Descriptor instead.
+func (*CreatePullRequestRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreatePullRequestRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *CreatePullRequestRequest) GetChangedFiles() []string {
+	if x != nil {
+		return x.ChangedFiles
+	}
+	return nil
+}
+
+type CreatePullRequestResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pr    *PullRequest           `protobuf:"bytes,1,opt,name=pr,proto3" json:"pr,omitempty"`
+	// owner_reviewers are the assigned code owners, fallback_reviewers the
+	// assigned reviewers from the fallback teams.
+	OwnerReviewers    []string `protobuf:"bytes,2,rep,name=owner_reviewers,json=ownerReviewers,proto3" json:"owner_reviewers,omitempty"`
+	FallbackReviewers []string `protobuf:"bytes,3,rep,name=fallback_reviewers,json=fallbackReviewers,proto3" json:"fallback_reviewers,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreatePullRequestResponse) Reset() {
+	*x = CreatePullRequestResponse{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePullRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePullRequestResponse) ProtoMessage() {}
+
+func (x *CreatePullRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePullRequestResponse.ProtoReflect.Descriptor instead.
+func (*CreatePullRequestResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePullRequestResponse) GetPr() *PullRequest {
+	if x != nil {
+		return x.Pr
+	}
+	return nil
+}
+
+func (x *CreatePullRequestResponse) GetOwnerReviewers() []string {
+	if x != nil {
+		return x.OwnerReviewers
+	}
+	return nil
+}
+
+func (x *CreatePullRequestResponse) GetFallbackReviewers() []string {
+	if x != nil {
+		return x.FallbackReviewers
+	}
+	return nil
+}
+
+type MergePullRequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	// repository of the PR, PR ids are unique within their repository.
+	Repository    string `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergePullRequestRequest) Reset() {
+	*x = MergePullRequestRequest{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergePullRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePullRequestRequest) ProtoMessage() {}
+
+func (x *MergePullRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePullRequestRequest.ProtoReflect.Descriptor instead.
+func (*MergePullRequestRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MergePullRequestRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *MergePullRequestRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+type MergePullRequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pr            *PullRequest           `protobuf:"bytes,1,opt,name=pr,proto3" json:"pr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergePullRequestResponse) Reset() {
+	*x = MergePullRequestResponse{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergePullRequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergePullRequestResponse) ProtoMessage() {}
+
+func (x *MergePullRequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergePullRequestResponse.ProtoReflect.Descriptor instead.
+func (*MergePullRequestResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *MergePullRequestResponse) GetPr() *PullRequest {
+	if x != nil {
+		return x.Pr
+	}
+	return nil
+}
+
+type ReassignReviewerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	OldUserId     string                 `protobuf:"bytes,2,opt,name=old_user_id,json=oldUserId,proto3" json:"old_user_id,omitempty"`
+	// repository of the PR, PR ids are unique within their repository.
+	Repository    string `protobuf:"bytes,3,opt,name=repository,proto3" json:"repository,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignReviewerRequest) Reset() {
+	*x = ReassignReviewerRequest{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignReviewerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignReviewerRequest) ProtoMessage() {}
+
+func (x *ReassignReviewerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignReviewerRequest.ProtoReflect.Descriptor instead.
+func (*ReassignReviewerRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReassignReviewerRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *ReassignReviewerRequest) GetOldUserId() string {
+	if x != nil {
+		return x.OldUserId
+	}
+	return ""
+}
+
+func (x *ReassignReviewerRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+type ReassignReviewerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pr            *PullRequest           `protobuf:"bytes,1,opt,name=pr,proto3" json:"pr,omitempty"`
+	ReplacedBy    string                 `protobuf:"bytes,2,opt,name=replaced_by,json=replacedBy,proto3" json:"replaced_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignReviewerResponse) Reset() {
+	*x = ReassignReviewerResponse{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignReviewerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignReviewerResponse) ProtoMessage() {}
+
+func (x *ReassignReviewerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignReviewerResponse.ProtoReflect.Descriptor instead.
+func (*ReassignReviewerResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReassignReviewerResponse) GetPr() *PullRequest {
+	if x != nil {
+		return x.Pr
+	}
+	return nil
+}
+
+func (x *ReassignReviewerResponse) GetReplacedBy() string {
+	if x != nil {
+		return x.ReplacedBy
+	}
+	return ""
+}
+
+type GetUserReviewsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// repository limits the PRs to one repository, all of them when empty.
+	Repository    string `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserReviewsRequest) Reset() {
+	*x = GetUserReviewsRequest{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserReviewsRequest) ProtoMessage() {}
+
+func (x *GetUserReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserReviewsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetUserReviewsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserReviewsRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+type GetUserReviewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PullRequests  []*PullRequestShort    `protobuf:"bytes,2,rep,name=pull_requests,json=pullRequests,proto3" json:"pull_requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserReviewsResponse) Reset() {
+	*x = GetUserReviewsResponse{}
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserReviewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserReviewsResponse) ProtoMessage() {}
+
+func (x *GetUserReviewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_pull_requests_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserReviewsResponse.ProtoReflect.Descriptor instead.
+func (*GetUserReviewsResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_pull_requests_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetUserReviewsResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUserReviewsResponse) GetPullRequests() []*PullRequestShort {
+	if x != nil {
+		return x.PullRequests
+	}
+	return nil
+}
+
+var File_reviewer_v1_pull_requests_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_pull_requests_proto_rawDesc = "" +
+	"\n" +
+	"\x1freviewer/v1/pull_requests.proto\x12\vreviewer.v1\x1a\x17reviewer/v1/types.proto\"\xd0\x01\n" +
+	"\x18CreatePullRequestRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x04 \x01(\tR\n" +
+	"repository\x12#\n" +
+	"\rchanged_files\x18\x05 \x03(\tR\fchangedFiles\"\x9d\x01\n" +
+	"\x19CreatePullRequestResponse\x12(\n" +
+	"\x02pr\x18\x01 \x01(\v2\x18.reviewer.v1.PullRequestR\x02pr\x12'\n" +
+	"\x0fowner_reviewers\x18\x02 \x03(\tR\x0eownerReviewers\x12-\n" +
+	"\x12fallback_reviewers\x18\x03 \x03(\tR\x11fallbackReviewers\"a\n" +
+	"\x17MergePullRequestRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x02 \x01(\tR\n" +
+	"repository\"D\n" +
+	"\x18MergePullRequestResponse\x12(\n" +
+	"\x02pr\x18\x01 \x01(\v2\x18.reviewer.v1.PullRequestR\x02pr\"\x81\x01\n" +
+	"\x17ReassignReviewerRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12\x1e\n" +
+	"\vold_user_id\x18\x02 \x01(\tR\toldUserId\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x03 \x01(\tR\n" +
+	"repository\"e\n" +
+	"\x18ReassignReviewerResponse\x12(\n" +
+	"\x02pr\x18\x01 \x01(\v2\x18.reviewer.v1.PullRequestR\x02pr\x12\x1f\n" +
+	"\vreplaced_by\x18\x02 \x01(\tR\n" +
+	"replacedBy\"P\n" +
+	"\x15GetUserReviewsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x02 \x01(\tR\n" +
+	"repository\"u\n" +
+	"\x16GetUserReviewsResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12B\n" +
+	"\rpull_requests\x18\x02 \x03(\v2\x1d.reviewer.v1.PullRequestShortR\fpullRequests2\x96\x03\n" +
+	"\x13PullRequestsService\x12b\n" +
+	"\x11CreatePullRequest\x12%.reviewer.v1.CreatePullRequestRequest\x1a&.reviewer.v1.CreatePullRequestResponse\x12_\n" +
+	"\x10MergePullRequest\x12$.reviewer.v1.MergePullRequestRequest\x1a%.reviewer.v1.MergePullRequestResponse\x12_\n" +
+	"\x10ReassignReviewer\x12$.reviewer.v1.ReassignReviewerRequest\x1a%.reviewer.v1.ReassignReviewerResponse\x12Y\n" +
+	"\x0eGetUserReviews\x12\".reviewer.v1.GetUserReviewsRequest\x1a#.reviewer.v1.GetUserReviewsResponseBKZIgithub.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1;reviewerv1b\x06proto3"
+
+var (
+	file_reviewer_v1_pull_requests_proto_rawDescOnce sync.Once
+	file_reviewer_v1_pull_requests_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_pull_requests_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_pull_requests_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_pull_requests_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_pull_requests_proto_rawDesc), len(file_reviewer_v1_pull_requests_proto_rawDesc)))
+	})
+	return file_reviewer_v1_pull_requests_proto_rawDescData
+}
+
+var file_reviewer_v1_pull_requests_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_reviewer_v1_pull_requests_proto_goTypes = []any{
+	(*CreatePullRequestRequest)(nil),  // 0: reviewer.v1.CreatePullRequestRequest
+	(*CreatePullRequestResponse)(nil), // 1: reviewer.v1.CreatePullRequestResponse
+	(*MergePullRequestRequest)(nil),   // 2: reviewer.v1.MergePullRequestRequest
+	(*MergePullRequestResponse)(nil),  // 3: reviewer.v1.MergePullRequestResponse
+	(*ReassignReviewerRequest)(nil),   // 4: reviewer.v1.ReassignReviewerRequest
+	(*ReassignReviewerResponse)(nil),  // 5: reviewer.v1.ReassignReviewerResponse
+	(*GetUserReviewsRequest)(nil),     // 6: reviewer.v1.GetUserReviewsRequest
+	(*GetUserReviewsResponse)(nil),    // 7: reviewer.v1.GetUserReviewsResponse
+	(*PullRequest)(nil),               // 8: reviewer.v1.PullRequest
+	(*PullRequestShort)(nil),          // 9: reviewer.v1.PullRequestShort
+}
+var file_reviewer_v1_pull_requests_proto_depIdxs = []int32{
+	8, // 0: reviewer.v1.CreatePullRequestResponse.pr:type_name -> reviewer.v1.PullRequest
+	8, // 1: reviewer.v1.MergePullRequestResponse.pr:type_name -> reviewer.v1.PullRequest
+	8, // 2: reviewer.v1.ReassignReviewerResponse.pr:type_name -> reviewer.v1.PullRequest
+	9, // 3: reviewer.v1.GetUserReviewsResponse.pull_requests:type_name -> reviewer.v1.PullRequestShort
+	0, // 4: reviewer.v1.PullRequestsService.CreatePullRequest:input_type -> reviewer.v1.CreatePullRequestRequest
+	2, // 5: reviewer.v1.PullRequestsService.MergePullRequest:input_type -> reviewer.v1.MergePullRequestRequest
+	4, // 6: reviewer.v1.PullRequestsService.ReassignReviewer:input_type -> reviewer.v1.ReassignReviewerRequest
+	6, // 7: reviewer.v1.PullRequestsService.GetUserReviews:input_type -> reviewer.v1.GetUserReviewsRequest
+	1, // 8: reviewer.v1.PullRequestsService.CreatePullRequest:output_type -> reviewer.v1.CreatePullRequestResponse
+	3, // 9: reviewer.v1.PullRequestsService.MergePullRequest:output_type -> reviewer.v1.MergePullRequestResponse
+	5, // 10: reviewer.v1.PullRequestsService.ReassignReviewer:output_type -> reviewer.v1.ReassignReviewerResponse
+	7, // 11: reviewer.v1.PullRequestsService.GetUserReviews:output_type -> reviewer.v1.GetUserReviewsResponse
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_pull_requests_proto_init() }
+func file_reviewer_v1_pull_requests_proto_init() {
+	if File_reviewer_v1_pull_requests_proto != nil {
+		return
+	}
+	file_reviewer_v1_types_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_pull_requests_proto_rawDesc), len(file_reviewer_v1_pull_requests_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewer_v1_pull_requests_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_pull_requests_proto_depIdxs,
+		MessageInfos:      file_reviewer_v1_pull_requests_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_pull_requests_proto = out.File
+	file_reviewer_v1_pull_requests_proto_goTypes = nil
+	file_reviewer_v1_pull_requests_proto_depIdxs = nil
+}
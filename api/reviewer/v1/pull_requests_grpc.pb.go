@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: reviewer/v1/pull_requests.proto
+
+package reviewerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PullRequestsService_CreatePullRequest_FullMethodName = "/reviewer.v1.PullRequestsService/CreatePullRequest"
+	PullRequestsService_MergePullRequest_FullMethodName  = "/reviewer.v1.PullRequestsService/MergePullRequest"
+	PullRequestsService_ReassignReviewer_FullMethodName  = "/reviewer.v1.PullRequestsService/ReassignReviewer"
+	PullRequestsService_GetUserReviews_FullMethodName    = "/reviewer.v1.PullRequestsService/GetUserReviews"
+)
+
+// PullRequestsServiceClient is the client API for PullRequestsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PullRequestsService manages pull requests, mirrors /pullRequest/* of the HTTP API.
+type PullRequestsServiceClient interface {
+	// CreatePullRequest creates a PR and assigns reviewers from the author's team.
+	CreatePullRequest(ctx context.Context, in *CreatePullRequestRequest, opts ...grpc.CallOption) (*CreatePullRequestResponse, error)
+	// MergePullRequest marks a PR as merged, it is idempotent.
+	MergePullRequest(ctx context.Context, in *MergePullRequestRequest, opts ...grpc.CallOption) (*MergePullRequestResponse, error)
+	// ReassignReviewer replaces a reviewer with another member of their team.
+	ReassignReviewer(ctx context.Context, in *ReassignReviewerRequest, opts ...grpc.CallOption) (*ReassignReviewerResponse, error)
+	// GetUserReviews lists PRs where the user is a reviewer.
+	GetUserReviews(ctx context.Context, in *GetUserReviewsRequest, opts ...grpc.CallOption) (*GetUserReviewsResponse, error)
+}
+
+type pullRequestsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPullRequestsServiceClient(cc grpc.ClientConnInterface) PullRequestsServiceClient {
+	return &pullRequestsServiceClient{cc}
+}
+
+func (c *pullRequestsServiceClient) CreatePullRequest(ctx context.Context, in *CreatePullRequestRequest, opts ...grpc.CallOption) (*CreatePullRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePullRequestResponse)
+	err := c.cc.Invoke(ctx, PullRequestsService_CreatePullRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestsServiceClient) MergePullRequest(ctx context.Context, in *MergePullRequestRequest, opts ...grpc.CallOption) (*MergePullRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergePullRequestResponse)
+	err := c.cc.Invoke(ctx, PullRequestsService_MergePullRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestsServiceClient) ReassignReviewer(ctx context.Context, in *ReassignReviewerRequest, opts ...grpc.CallOption) (*ReassignReviewerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReassignReviewerResponse)
+	err := c.cc.Invoke(ctx, PullRequestsService_ReassignReviewer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pullRequestsServiceClient) GetUserReviews(ctx context.Context, in *GetUserReviewsRequest, opts ...grpc.CallOption) (*GetUserReviewsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserReviewsResponse)
+	err := c.cc.Invoke(ctx, PullRequestsService_GetUserReviews_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PullRequestsServiceServer is the server API for PullRequestsService service.
+// All implementations must embed UnimplementedPullRequestsServiceServer
+// for forward compatibility.
+//
+// PullRequestsService manages pull requests, mirrors /pullRequest/* of the HTTP API.
+type PullRequestsServiceServer interface {
+	// CreatePullRequest creates a PR and assigns reviewers from the author's team.
+	CreatePullRequest(context.Context, *CreatePullRequestRequest) (*CreatePullRequestResponse, error)
+	// MergePullRequest marks a PR as merged, it is idempotent.
+	MergePullRequest(context.Context, *MergePullRequestRequest) (*MergePullRequestResponse, error)
+	// ReassignReviewer replaces a reviewer with another member of their team.
+	ReassignReviewer(context.Context, *ReassignReviewerRequest) (*ReassignReviewerResponse, error)
+	// GetUserReviews lists PRs where the user is a reviewer.
+	GetUserReviews(context.Context, *GetUserReviewsRequest) (*GetUserReviewsResponse, error)
+	mustEmbedUnimplementedPullRequestsServiceServer()
+}
+
+// UnimplementedPullRequestsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPullRequestsServiceServer struct{}
+
+func (UnimplementedPullRequestsServiceServer) CreatePullRequest(context.Context, *CreatePullRequestRequest) (*CreatePullRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePullRequest not implemented")
+}
+func (UnimplementedPullRequestsServiceServer) MergePullRequest(context.Context, *MergePullRequestRequest) (*MergePullRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergePullRequest not implemented")
+}
+func (UnimplementedPullRequestsServiceServer) ReassignReviewer(context.Context, *ReassignReviewerRequest) (*ReassignReviewerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReassignReviewer not implemented")
+}
+func (UnimplementedPullRequestsServiceServer) GetUserReviews(context.Context, *GetUserReviewsRequest) (*GetUserReviewsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserReviews not implemented")
+}
+func (UnimplementedPullRequestsServiceServer) mustEmbedUnimplementedPullRequestsServiceServer() {}
+func (UnimplementedPullRequestsServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafePullRequestsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PullRequestsServiceServer will
+// result in compilation errors.
+type UnsafePullRequestsServiceServer interface {
+	mustEmbedUnimplementedPullRequestsServiceServer()
+}
+
+func RegisterPullRequestsServiceServer(s grpc.ServiceRegistrar, srv PullRequestsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPullRequestsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PullRequestsService_ServiceDesc, srv)
+}
+
+func _PullRequestsService_CreatePullRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePullRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestsServiceServer).CreatePullRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestsService_CreatePullRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestsServiceServer).CreatePullRequest(ctx, req.(*CreatePullRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestsService_MergePullRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergePullRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestsServiceServer).MergePullRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestsService_MergePullRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestsServiceServer).MergePullRequest(ctx, req.(*MergePullRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestsService_ReassignReviewer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReassignReviewerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestsServiceServer).ReassignReviewer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestsService_ReassignReviewer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestsServiceServer).ReassignReviewer(ctx, req.(*ReassignReviewerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PullRequestsService_GetUserReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PullRequestsServiceServer).GetUserReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PullRequestsService_GetUserReviews_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PullRequestsServiceServer).GetUserReviews(ctx, req.(*GetUserReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PullRequestsService_ServiceDesc is the grpc.ServiceDesc for PullRequestsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PullRequestsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "reviewer.v1.PullRequestsService",
+	HandlerType: (*PullRequestsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePullRequest",
+			Handler:    _PullRequestsService_CreatePullRequest_Handler,
+		},
+		{
+			MethodName: "MergePullRequest",
+			Handler:    _PullRequestsService_MergePullRequest_Handler,
+		},
+		{
+			MethodName: "ReassignReviewer",
+			Handler:    _PullRequestsService_ReassignReviewer_Handler,
+		},
+		{
+			MethodName: "GetUserReviews",
+			Handler:    _PullRequestsService_GetUserReviews_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reviewer/v1/pull_requests.proto",
+}
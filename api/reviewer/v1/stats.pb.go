@@ -0,0 +1,309 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: reviewer/v1/stats.proto
+
+package reviewerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// repository limits the statistics to one repository, all of them when empty.
+	Repository    string `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetStatsRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+type ReviewerStat struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ReviewerId      string                 `protobuf:"bytes,1,opt,name=reviewer_id,json=reviewerId,proto3" json:"reviewer_id,omitempty"`
+	AssignmentCount int64                  `protobuf:"varint,2,opt,name=assignment_count,json=assignmentCount,proto3" json:"assignment_count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ReviewerStat) Reset() {
+	*x = ReviewerStat{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewerStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewerStat) ProtoMessage() {}
+
+func (x *ReviewerStat) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewerStat.ProtoReflect.Descriptor instead.
+func (*ReviewerStat) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReviewerStat) GetReviewerId() string {
+	if x != nil {
+		return x.ReviewerId
+	}
+	return ""
+}
+
+func (x *ReviewerStat) GetAssignmentCount() int64 {
+	if x != nil {
+		return x.AssignmentCount
+	}
+	return 0
+}
+
+type PRStatusStat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PRStatusStat) Reset() {
+	*x = PRStatusStat{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PRStatusStat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PRStatusStat) ProtoMessage() {}
+
+func (x *PRStatusStat) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PRStatusStat.ProtoReflect.Descriptor instead.
+func (*PRStatusStat) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PRStatusStat) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PRStatusStat) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TopReviewers         []*ReviewerStat        `protobuf:"bytes,1,rep,name=top_reviewers,json=topReviewers,proto3" json:"top_reviewers,omitempty"`
+	PrStatusDistribution []*PRStatusStat        `protobuf:"bytes,2,rep,name=pr_status_distribution,json=prStatusDistribution,proto3" json:"pr_status_distribution,omitempty"`
+	TotalActiveUsers     int64                  `protobuf:"varint,3,opt,name=total_active_users,json=totalActiveUsers,proto3" json:"total_active_users,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_reviewer_v1_stats_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_stats_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_stats_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStatsResponse) GetTopReviewers() []*ReviewerStat {
+	if x != nil {
+		return x.TopReviewers
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetPrStatusDistribution() []*PRStatusStat {
+	if x != nil {
+		return x.PrStatusDistribution
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetTotalActiveUsers() int64 {
+	if x != nil {
+		return x.TotalActiveUsers
+	}
+	return 0
+}
+
+var File_reviewer_v1_stats_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_stats_proto_rawDesc = "" +
+	"\n" +
+	"\x17reviewer/v1/stats.proto\x12\vreviewer.v1\"1\n" +
+	"\x0fGetStatsRequest\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x01 \x01(\tR\n" +
+	"repository\"Z\n" +
+	"\fReviewerStat\x12\x1f\n" +
+	"\vreviewer_id\x18\x01 \x01(\tR\n" +
+	"reviewerId\x12)\n" +
+	"\x10assignment_count\x18\x02 \x01(\x03R\x0fassignmentCount\"<\n" +
+	"\fPRStatusStat\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xd1\x01\n" +
+	"\x10GetStatsResponse\x12>\n" +
+	"\rtop_reviewers\x18\x01 \x03(\v2\x19.reviewer.v1.ReviewerStatR\ftopReviewers\x12O\n" +
+	"\x16pr_status_distribution\x18\x02 \x03(\v2\x19.reviewer.v1.PRStatusStatR\x14prStatusDistribution\x12,\n" +
+	"\x12total_active_users\x18\x03 \x01(\x03R\x10totalActiveUsers2W\n" +
+	"\fStatsService\x12G\n" +
+	"\bGetStats\x12\x1c.reviewer.v1.GetStatsRequest\x1a\x1d.reviewer.v1.GetStatsResponseBKZIgithub.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1;reviewerv1b\x06proto3"
+
+var (
+	file_reviewer_v1_stats_proto_rawDescOnce sync.Once
+	file_reviewer_v1_stats_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_stats_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_stats_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_stats_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_stats_proto_rawDesc), len(file_reviewer_v1_stats_proto_rawDesc)))
+	})
+	return file_reviewer_v1_stats_proto_rawDescData
+}
+
+var file_reviewer_v1_stats_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_reviewer_v1_stats_proto_goTypes = []any{
+	(*GetStatsRequest)(nil),  // 0: reviewer.v1.GetStatsRequest
+	(*ReviewerStat)(nil),     // 1: reviewer.v1.ReviewerStat
+	(*PRStatusStat)(nil),     // 2: reviewer.v1.PRStatusStat
+	(*GetStatsResponse)(nil), // 3: reviewer.v1.GetStatsResponse
+}
+var file_reviewer_v1_stats_proto_depIdxs = []int32{
+	1, // 0: reviewer.v1.GetStatsResponse.top_reviewers:type_name -> reviewer.v1.ReviewerStat
+	2, // 1: reviewer.v1.GetStatsResponse.pr_status_distribution:type_name -> reviewer.v1.PRStatusStat
+	0, // 2: reviewer.v1.StatsService.GetStats:input_type -> reviewer.v1.GetStatsRequest
+	3, // 3: reviewer.v1.StatsService.GetStats:output_type -> reviewer.v1.GetStatsResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_stats_proto_init() }
+func file_reviewer_v1_stats_proto_init() {
+	if File_reviewer_v1_stats_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_stats_proto_rawDesc), len(file_reviewer_v1_stats_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewer_v1_stats_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_stats_proto_depIdxs,
+		MessageInfos:      file_reviewer_v1_stats_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_stats_proto = out.File
+	file_reviewer_v1_stats_proto_goTypes = nil
+	file_reviewer_v1_stats_proto_depIdxs = nil
+}
@@ -0,0 +1,573 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: reviewer/v1/teams.proto
+
+package reviewerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamRequest) Reset() {
+	*x = GetTeamRequest{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamRequest) ProtoMessage() {}
+
+func (x *GetTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamRequest.ProtoReflect.Descriptor instead.
+func (*GetTeamRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetTeamRequest) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+type GetTeamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTeamResponse) Reset() {
+	*x = GetTeamResponse{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTeamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTeamResponse) ProtoMessage() {}
+
+func (x *GetTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTeamResponse.ProtoReflect.Descriptor instead.
+func (*GetTeamResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetTeamResponse) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type CreateTeamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTeamRequest) Reset() {
+	*x = CreateTeamRequest{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTeamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTeamRequest) ProtoMessage() {}
+
+func (x *CreateTeamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTeamRequest.ProtoReflect.Descriptor instead.
+func (*CreateTeamRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTeamRequest) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type CreateTeamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          *Team                  `protobuf:"bytes,1,opt,name=team,proto3" json:"team,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTeamResponse) Reset() {
+	*x = CreateTeamResponse{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTeamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTeamResponse) ProtoMessage() {}
+
+func (x *CreateTeamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTeamResponse.ProtoReflect.Descriptor instead.
+func (*CreateTeamResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateTeamResponse) GetTeam() *Team {
+	if x != nil {
+		return x.Team
+	}
+	return nil
+}
+
+type DeactivateUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Users []string               `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// dry_run rolls everything back and only reports what would happen.
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateUsersRequest) Reset() {
+	*x = DeactivateUsersRequest{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateUsersRequest) ProtoMessage() {}
+
+func (x *DeactivateUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateUsersRequest.ProtoReflect.Descriptor instead.
+func (*DeactivateUsersRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeactivateUsersRequest) GetUsers() []string {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *DeactivateUsersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type DeactivateUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpdatedPrs    []*PullRequest         `protobuf:"bytes,1,rep,name=updated_prs,json=updatedPrs,proto3" json:"updated_prs,omitempty"`
+	Reassignments []*Reassignment        `protobuf:"bytes,2,rep,name=reassignments,proto3" json:"reassignments,omitempty"`
+	Users         []*DeactivatedUser     `protobuf:"bytes,3,rep,name=users,proto3" json:"users,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivateUsersResponse) Reset() {
+	*x = DeactivateUsersResponse{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivateUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivateUsersResponse) ProtoMessage() {}
+
+func (x *DeactivateUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivateUsersResponse.ProtoReflect.Descriptor instead.
+func (*DeactivateUsersResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeactivateUsersResponse) GetUpdatedPrs() []*PullRequest {
+	if x != nil {
+		return x.UpdatedPrs
+	}
+	return nil
+}
+
+func (x *DeactivateUsersResponse) GetReassignments() []*Reassignment {
+	if x != nil {
+		return x.Reassignments
+	}
+	return nil
+}
+
+func (x *DeactivateUsersResponse) GetUsers() []*DeactivatedUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *DeactivateUsersResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// Reassignment is a review taken from a deactivated user, new_reviewer_id
+// is empty when nobody could take it over.
+type Reassignment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	OldReviewerId string                 `protobuf:"bytes,2,opt,name=old_reviewer_id,json=oldReviewerId,proto3" json:"old_reviewer_id,omitempty"`
+	NewReviewerId string                 `protobuf:"bytes,3,opt,name=new_reviewer_id,json=newReviewerId,proto3" json:"new_reviewer_id,omitempty"`
+	Repository    string                 `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reassignment) Reset() {
+	*x = Reassignment{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reassignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reassignment) ProtoMessage() {}
+
+func (x *Reassignment) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reassignment.ProtoReflect.Descriptor instead.
+func (*Reassignment) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Reassignment) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *Reassignment) GetOldReviewerId() string {
+	if x != nil {
+		return x.OldReviewerId
+	}
+	return ""
+}
+
+func (x *Reassignment) GetNewReviewerId() string {
+	if x != nil {
+		return x.NewReviewerId
+	}
+	return ""
+}
+
+func (x *Reassignment) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+// DeactivatedUser lists what happened to the reviews of a deactivated user.
+type DeactivatedUser struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// reassigned are the open PRs another reviewer took over.
+	Reassigned []string `protobuf:"bytes,2,rep,name=reassigned,proto3" json:"reassigned,omitempty"`
+	// lost_reviewer are the open PRs left with one reviewer less.
+	LostReviewer []string `protobuf:"bytes,3,rep,name=lost_reviewer,json=lostReviewer,proto3" json:"lost_reviewer,omitempty"`
+	// skipped are the merged PRs that were not touched.
+	Skipped       []string `protobuf:"bytes,4,rep,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeactivatedUser) Reset() {
+	*x = DeactivatedUser{}
+	mi := &file_reviewer_v1_teams_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeactivatedUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeactivatedUser) ProtoMessage() {}
+
+func (x *DeactivatedUser) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_teams_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeactivatedUser.ProtoReflect.Descriptor instead.
+func (*DeactivatedUser) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_teams_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeactivatedUser) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeactivatedUser) GetReassigned() []string {
+	if x != nil {
+		return x.Reassigned
+	}
+	return nil
+}
+
+func (x *DeactivatedUser) GetLostReviewer() []string {
+	if x != nil {
+		return x.LostReviewer
+	}
+	return nil
+}
+
+func (x *DeactivatedUser) GetSkipped() []string {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+var File_reviewer_v1_teams_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_teams_proto_rawDesc = "" +
+	"\n" +
+	"\x17reviewer/v1/teams.proto\x12\vreviewer.v1\x1a\x17reviewer/v1/types.proto\"-\n" +
+	"\x0eGetTeamRequest\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\"8\n" +
+	"\x0fGetTeamResponse\x12%\n" +
+	"\x04team\x18\x01 \x01(\v2\x11.reviewer.v1.TeamR\x04team\":\n" +
+	"\x11CreateTeamRequest\x12%\n" +
+	"\x04team\x18\x01 \x01(\v2\x11.reviewer.v1.TeamR\x04team\";\n" +
+	"\x12CreateTeamResponse\x12%\n" +
+	"\x04team\x18\x01 \x01(\v2\x11.reviewer.v1.TeamR\x04team\"G\n" +
+	"\x16DeactivateUsersRequest\x12\x14\n" +
+	"\x05users\x18\x01 \x03(\tR\x05users\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"\xe2\x01\n" +
+	"\x17DeactivateUsersResponse\x129\n" +
+	"\vupdated_prs\x18\x01 \x03(\v2\x18.reviewer.v1.PullRequestR\n" +
+	"updatedPrs\x12?\n" +
+	"\rreassignments\x18\x02 \x03(\v2\x19.reviewer.v1.ReassignmentR\rreassignments\x122\n" +
+	"\x05users\x18\x03 \x03(\v2\x1c.reviewer.v1.DeactivatedUserR\x05users\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"\xa6\x01\n" +
+	"\fReassignment\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12&\n" +
+	"\x0fold_reviewer_id\x18\x02 \x01(\tR\roldReviewerId\x12&\n" +
+	"\x0fnew_reviewer_id\x18\x03 \x01(\tR\rnewReviewerId\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x04 \x01(\tR\n" +
+	"repository\"\x89\x01\n" +
+	"\x0fDeactivatedUser\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1e\n" +
+	"\n" +
+	"reassigned\x18\x02 \x03(\tR\n" +
+	"reassigned\x12#\n" +
+	"\rlost_reviewer\x18\x03 \x03(\tR\flostReviewer\x12\x18\n" +
+	"\askipped\x18\x04 \x03(\tR\askipped2\x81\x02\n" +
+	"\fTeamsService\x12D\n" +
+	"\aGetTeam\x12\x1b.reviewer.v1.GetTeamRequest\x1a\x1c.reviewer.v1.GetTeamResponse\x12M\n" +
+	"\n" +
+	"CreateTeam\x12\x1e.reviewer.v1.CreateTeamRequest\x1a\x1f.reviewer.v1.CreateTeamResponse\x12\\\n" +
+	"\x0fDeactivateUsers\x12#.reviewer.v1.DeactivateUsersRequest\x1a$.reviewer.v1.DeactivateUsersResponseBKZIgithub.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1;reviewerv1b\x06proto3"
+
+var (
+	file_reviewer_v1_teams_proto_rawDescOnce sync.Once
+	file_reviewer_v1_teams_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_teams_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_teams_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_teams_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_teams_proto_rawDesc), len(file_reviewer_v1_teams_proto_rawDesc)))
+	})
+	return file_reviewer_v1_teams_proto_rawDescData
+}
+
+var file_reviewer_v1_teams_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_reviewer_v1_teams_proto_goTypes = []any{
+	(*GetTeamRequest)(nil),          // 0: reviewer.v1.GetTeamRequest
+	(*GetTeamResponse)(nil),         // 1: reviewer.v1.GetTeamResponse
+	(*CreateTeamRequest)(nil),       // 2: reviewer.v1.CreateTeamRequest
+	(*CreateTeamResponse)(nil),      // 3: reviewer.v1.CreateTeamResponse
+	(*DeactivateUsersRequest)(nil),  // 4: reviewer.v1.DeactivateUsersRequest
+	(*DeactivateUsersResponse)(nil), // 5: reviewer.v1.DeactivateUsersResponse
+	(*Reassignment)(nil),            // 6: reviewer.v1.Reassignment
+	(*DeactivatedUser)(nil),         // 7: reviewer.v1.DeactivatedUser
+	(*Team)(nil),                    // 8: reviewer.v1.Team
+	(*PullRequest)(nil),             // 9: reviewer.v1.PullRequest
+}
+var file_reviewer_v1_teams_proto_depIdxs = []int32{
+	8, // 0: reviewer.v1.GetTeamResponse.team:type_name -> reviewer.v1.Team
+	8, // 1: reviewer.v1.CreateTeamRequest.team:type_name -> reviewer.v1.Team
+	8, // 2: reviewer.v1.CreateTeamResponse.team:type_name -> reviewer.v1.Team
+	9, // 3: reviewer.v1.DeactivateUsersResponse.updated_prs:type_name -> reviewer.v1.PullRequest
+	6, // 4: reviewer.v1.DeactivateUsersResponse.reassignments:type_name -> reviewer.v1.Reassignment
+	7, // 5: reviewer.v1.DeactivateUsersResponse.users:type_name -> reviewer.v1.DeactivatedUser
+	0, // 6: reviewer.v1.TeamsService.GetTeam:input_type -> reviewer.v1.GetTeamRequest
+	2, // 7: reviewer.v1.TeamsService.CreateTeam:input_type -> reviewer.v1.CreateTeamRequest
+	4, // 8: reviewer.v1.TeamsService.DeactivateUsers:input_type -> reviewer.v1.DeactivateUsersRequest
+	1, // 9: reviewer.v1.TeamsService.GetTeam:output_type -> reviewer.v1.GetTeamResponse
+	3, // 10: reviewer.v1.TeamsService.CreateTeam:output_type -> reviewer.v1.CreateTeamResponse
+	5, // 11: reviewer.v1.TeamsService.DeactivateUsers:output_type -> reviewer.v1.DeactivateUsersResponse
+	9, // [9:12] is the sub-list for method output_type
+	6, // [6:9] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_teams_proto_init() }
+func file_reviewer_v1_teams_proto_init() {
+	if File_reviewer_v1_teams_proto != nil {
+		return
+	}
+	file_reviewer_v1_types_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_teams_proto_rawDesc), len(file_reviewer_v1_teams_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewer_v1_teams_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_teams_proto_depIdxs,
+		MessageInfos:      file_reviewer_v1_teams_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_teams_proto = out.File
+	file_reviewer_v1_teams_proto_goTypes = nil
+	file_reviewer_v1_teams_proto_depIdxs = nil
+}
@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: reviewer/v1/teams.proto
+
+package reviewerv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TeamsService_GetTeam_FullMethodName         = "/reviewer.v1.TeamsService/GetTeam"
+	TeamsService_CreateTeam_FullMethodName      = "/reviewer.v1.TeamsService/CreateTeam"
+	TeamsService_DeactivateUsers_FullMethodName = "/reviewer.v1.TeamsService/DeactivateUsers"
+)
+
+// TeamsServiceClient is the client API for TeamsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TeamsService manages teams, mirrors POST /team/* of the HTTP API.
+type TeamsServiceClient interface {
+	// GetTeam returns a team with its members.
+	GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*GetTeamResponse, error)
+	// CreateTeam creates a team, creating or moving its members.
+	CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*CreateTeamResponse, error)
+	// DeactivateUsers deactivates users and reassigns their reviews.
+	DeactivateUsers(ctx context.Context, in *DeactivateUsersRequest, opts ...grpc.CallOption) (*DeactivateUsersResponse, error)
+}
+
+type teamsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTeamsServiceClient(cc grpc.ClientConnInterface) TeamsServiceClient {
+	return &teamsServiceClient{cc}
+}
+
+func (c *teamsServiceClient) GetTeam(ctx context.Context, in *GetTeamRequest, opts ...grpc.CallOption) (*GetTeamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTeamResponse)
+	err := c.cc.Invoke(ctx, TeamsService_GetTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamsServiceClient) CreateTeam(ctx context.Context, in *CreateTeamRequest, opts ...grpc.CallOption) (*CreateTeamResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTeamResponse)
+	err := c.cc.Invoke(ctx, TeamsService_CreateTeam_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamsServiceClient) DeactivateUsers(ctx context.Context, in *DeactivateUsersRequest, opts ...grpc.CallOption) (*DeactivateUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeactivateUsersResponse)
+	err := c.cc.Invoke(ctx, TeamsService_DeactivateUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TeamsServiceServer is the server API for TeamsService service.
+// All implementations must embed UnimplementedTeamsServiceServer
+// for forward compatibility.
+//
+// TeamsService manages teams, mirrors POST /team/* of the HTTP API.
+type TeamsServiceServer interface {
+	// GetTeam returns a team with its members.
+	GetTeam(context.Context, *GetTeamRequest) (*GetTeamResponse, error)
+	// CreateTeam creates a team, creating or moving its members.
+	CreateTeam(context.Context, *CreateTeamRequest) (*CreateTeamResponse, error)
+	// DeactivateUsers deactivates users and reassigns their reviews.
+	DeactivateUsers(context.Context, *DeactivateUsersRequest) (*DeactivateUsersResponse, error)
+	mustEmbedUnimplementedTeamsServiceServer()
+}
+
+// UnimplementedTeamsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTeamsServiceServer struct{}
+
+func (UnimplementedTeamsServiceServer) GetTeam(context.Context, *GetTeamRequest) (*GetTeamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTeam not implemented")
+}
+func (UnimplementedTeamsServiceServer) CreateTeam(context.Context, *CreateTeamRequest) (*CreateTeamResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTeam not implemented")
+}
+func (UnimplementedTeamsServiceServer) DeactivateUsers(context.Context, *DeactivateUsersRequest) (*DeactivateUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateUsers not implemented")
+}
+func (UnimplementedTeamsServiceServer) mustEmbedUnimplementedTeamsServiceServer() {}
+func (UnimplementedTeamsServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeTeamsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TeamsServiceServer will
+// result in compilation errors.
+type UnsafeTeamsServiceServer interface {
+	mustEmbedUnimplementedTeamsServiceServer()
+}
+
+func RegisterTeamsServiceServer(s grpc.ServiceRegistrar, srv TeamsServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTeamsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TeamsService_ServiceDesc, srv)
+}
+
+func _TeamsService_GetTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServiceServer).GetTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TeamsService_GetTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServiceServer).GetTeam(ctx, req.(*GetTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TeamsService_CreateTeam_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTeamRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServiceServer).CreateTeam(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TeamsService_CreateTeam_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServiceServer).CreateTeam(ctx, req.(*CreateTeamRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TeamsService_DeactivateUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamsServiceServer).DeactivateUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TeamsService_DeactivateUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamsServiceServer).DeactivateUsers(ctx, req.(*DeactivateUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TeamsService_ServiceDesc is the grpc.ServiceDesc for TeamsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TeamsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "reviewer.v1.TeamsService",
+	HandlerType: (*TeamsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTeam",
+			Handler:    _TeamsService_GetTeam_Handler,
+		},
+		{
+			MethodName: "CreateTeam",
+			Handler:    _TeamsService_CreateTeam_Handler,
+		},
+		{
+			MethodName: "DeactivateUsers",
+			Handler:    _TeamsService_DeactivateUsers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "reviewer/v1/teams.proto",
+}
@@ -0,0 +1,536 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: reviewer/v1/types.proto
+
+package reviewerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PullRequestStatus is the state of a pull request.
+type PullRequestStatus int32
+
+const (
+	PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED PullRequestStatus = 0
+	PullRequestStatus_PULL_REQUEST_STATUS_OPEN        PullRequestStatus = 1
+	PullRequestStatus_PULL_REQUEST_STATUS_MERGED      PullRequestStatus = 2
+)
+
+// Enum value maps for PullRequestStatus.
+var (
+	PullRequestStatus_name = map[int32]string{
+		0: "PULL_REQUEST_STATUS_UNSPECIFIED",
+		1: "PULL_REQUEST_STATUS_OPEN",
+		2: "PULL_REQUEST_STATUS_MERGED",
+	}
+	PullRequestStatus_value = map[string]int32{
+		"PULL_REQUEST_STATUS_UNSPECIFIED": 0,
+		"PULL_REQUEST_STATUS_OPEN":        1,
+		"PULL_REQUEST_STATUS_MERGED":      2,
+	}
+)
+
+func (x PullRequestStatus) Enum() *PullRequestStatus {
+	p := new(PullRequestStatus)
+	*p = x
+	return p
+}
+
+func (x PullRequestStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PullRequestStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_reviewer_v1_types_proto_enumTypes[0].Descriptor()
+}
+
+func (PullRequestStatus) Type() protoreflect.EnumType {
+	return &file_reviewer_v1_types_proto_enumTypes[0]
+}
+
+func (x PullRequestStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PullRequestStatus.Descriptor instead.
+func (PullRequestStatus) EnumDescriptor() ([]byte, []int) {
+	return file_reviewer_v1_types_proto_rawDescGZIP(), []int{0}
+}
+
+// TeamMember is a user as seen from their team.
+type TeamMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	IsActive      bool                   `protobuf:"varint,3,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamMember) Reset() {
+	*x = TeamMember{}
+	mi := &file_reviewer_v1_types_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamMember) ProtoMessage() {}
+
+func (x *TeamMember) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_types_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamMember.ProtoReflect.Descriptor instead.
+func (*TeamMember) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_types_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TeamMember) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TeamMember) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *TeamMember) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+// Team is a group of users with a unique name.
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TeamName      string                 `protobuf:"bytes,1,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	Members       []*TeamMember          `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_reviewer_v1_types_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_types_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_types_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Team) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *Team) GetMembers() []*TeamMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+// User is a team member with their team name.
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	TeamName      string                 `protobuf:"bytes,3,opt,name=team_name,json=teamName,proto3" json:"team_name,omitempty"`
+	IsActive      bool                   `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_reviewer_v1_types_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_types_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_types_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *User) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetTeamName() string {
+	if x != nil {
+		return x.TeamName
+	}
+	return ""
+}
+
+func (x *User) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+// PullRequest is a PR with its assigned reviewers.
+type PullRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId     string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName   string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId          string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Status            PullRequestStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=reviewer.v1.PullRequestStatus" json:"status,omitempty"`
+	AssignedReviewers []string               `protobuf:"bytes,5,rep,name=assigned_reviewers,json=assignedReviewers,proto3" json:"assigned_reviewers,omitempty"`
+	Repository        string                 `protobuf:"bytes,6,opt,name=repository,proto3" json:"repository,omitempty"`
+	// missing_senior is set when the team of the author requires a senior
+	// reviewer and none of the assigned reviewers is one.
+	MissingSenior bool `protobuf:"varint,7,opt,name=missing_senior,json=missingSenior,proto3" json:"missing_senior,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullRequest) Reset() {
+	*x = PullRequest{}
+	mi := &file_reviewer_v1_types_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequest) ProtoMessage() {}
+
+func (x *PullRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_types_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequest.ProtoReflect.Descriptor instead.
+func (*PullRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_types_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PullRequest) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *PullRequest) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *PullRequest) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *PullRequest) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+func (x *PullRequest) GetAssignedReviewers() []string {
+	if x != nil {
+		return x.AssignedReviewers
+	}
+	return nil
+}
+
+func (x *PullRequest) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *PullRequest) GetMissingSenior() bool {
+	if x != nil {
+		return x.MissingSenior
+	}
+	return false
+}
+
+// PullRequestShort is a PR without its reviewers.
+type PullRequestShort struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PullRequestId   string                 `protobuf:"bytes,1,opt,name=pull_request_id,json=pullRequestId,proto3" json:"pull_request_id,omitempty"`
+	PullRequestName string                 `protobuf:"bytes,2,opt,name=pull_request_name,json=pullRequestName,proto3" json:"pull_request_name,omitempty"`
+	AuthorId        string                 `protobuf:"bytes,3,opt,name=author_id,json=authorId,proto3" json:"author_id,omitempty"`
+	Status          PullRequestStatus      `protobuf:"varint,4,opt,name=status,proto3,enum=reviewer.v1.PullRequestStatus" json:"status,omitempty"`
+	Repository      string                 `protobuf:"bytes,5,opt,name=repository,proto3" json:"repository,omitempty"`
+	// missing_senior as in PullRequest.
+	MissingSenior bool `protobuf:"varint,6,opt,name=missing_senior,json=missingSenior,proto3" json:"missing_senior,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullRequestShort) Reset() {
+	*x = PullRequestShort{}
+	mi := &file_reviewer_v1_types_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullRequestShort) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullRequestShort) ProtoMessage() {}
+
+func (x *PullRequestShort) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_types_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullRequestShort.ProtoReflect.Descriptor instead.
+func (*PullRequestShort) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_types_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PullRequestShort) GetPullRequestId() string {
+	if x != nil {
+		return x.PullRequestId
+	}
+	return ""
+}
+
+func (x *PullRequestShort) GetPullRequestName() string {
+	if x != nil {
+		return x.PullRequestName
+	}
+	return ""
+}
+
+func (x *PullRequestShort) GetAuthorId() string {
+	if x != nil {
+		return x.AuthorId
+	}
+	return ""
+}
+
+func (x *PullRequestShort) GetStatus() PullRequestStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+}
+
+func (x *PullRequestShort) GetRepository() string {
+	if x != nil {
+		return x.Repository
+	}
+	return ""
+}
+
+func (x *PullRequestShort) GetMissingSenior() bool {
+	if x != nil {
+		return x.MissingSenior
+	}
+	return false
+}
+
+var File_reviewer_v1_types_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_types_proto_rawDesc = "" +
+	"\n" +
+	"\x17reviewer/v1/types.proto\x12\vreviewer.v1\"^\n" +
+	"\n" +
+	"TeamMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tis_active\x18\x03 \x01(\bR\bisActive\"V\n" +
+	"\x04Team\x12\x1b\n" +
+	"\tteam_name\x18\x01 \x01(\tR\bteamName\x121\n" +
+	"\amembers\x18\x02 \x03(\v2\x17.reviewer.v1.TeamMemberR\amembers\"u\n" +
+	"\x04User\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1b\n" +
+	"\tteam_name\x18\x03 \x01(\tR\bteamName\x12\x1b\n" +
+	"\tis_active\x18\x04 \x01(\bR\bisActive\"\xac\x02\n" +
+	"\vPullRequest\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x126\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1e.reviewer.v1.PullRequestStatusR\x06status\x12-\n" +
+	"\x12assigned_reviewers\x18\x05 \x03(\tR\x11assignedReviewers\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x06 \x01(\tR\n" +
+	"repository\x12%\n" +
+	"\x0emissing_senior\x18\a \x01(\bR\rmissingSenior\"\x82\x02\n" +
+	"\x10PullRequestShort\x12&\n" +
+	"\x0fpull_request_id\x18\x01 \x01(\tR\rpullRequestId\x12*\n" +
+	"\x11pull_request_name\x18\x02 \x01(\tR\x0fpullRequestName\x12\x1b\n" +
+	"\tauthor_id\x18\x03 \x01(\tR\bauthorId\x126\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x1e.reviewer.v1.PullRequestStatusR\x06status\x12\x1e\n" +
+	"\n" +
+	"repository\x18\x05 \x01(\tR\n" +
+	"repository\x12%\n" +
+	"\x0emissing_senior\x18\x06 \x01(\bR\rmissingSenior*v\n" +
+	"\x11PullRequestStatus\x12#\n" +
+	"\x1fPULL_REQUEST_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PULL_REQUEST_STATUS_OPEN\x10\x01\x12\x1e\n" +
+	"\x1aPULL_REQUEST_STATUS_MERGED\x10\x02BKZIgithub.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1;reviewerv1b\x06proto3"
+
+var (
+	file_reviewer_v1_types_proto_rawDescOnce sync.Once
+	file_reviewer_v1_types_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_types_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_types_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_types_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_types_proto_rawDesc), len(file_reviewer_v1_types_proto_rawDesc)))
+	})
+	return file_reviewer_v1_types_proto_rawDescData
+}
+
+var file_reviewer_v1_types_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_reviewer_v1_types_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_reviewer_v1_types_proto_goTypes = []any{
+	(PullRequestStatus)(0),   // 0: reviewer.v1.PullRequestStatus
+	(*TeamMember)(nil),       // 1: reviewer.v1.TeamMember
+	(*Team)(nil),             // 2: reviewer.v1.Team
+	(*User)(nil),             // 3: reviewer.v1.User
+	(*PullRequest)(nil),      // 4: reviewer.v1.PullRequest
+	(*PullRequestShort)(nil), // 5: reviewer.v1.PullRequestShort
+}
+var file_reviewer_v1_types_proto_depIdxs = []int32{
+	1, // 0: reviewer.v1.Team.members:type_name -> reviewer.v1.TeamMember
+	0, // 1: reviewer.v1.PullRequest.status:type_name -> reviewer.v1.PullRequestStatus
+	0, // 2: reviewer.v1.PullRequestShort.status:type_name -> reviewer.v1.PullRequestStatus
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_types_proto_init() }
+func file_reviewer_v1_types_proto_init() {
+	if File_reviewer_v1_types_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_types_proto_rawDesc), len(file_reviewer_v1_types_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_reviewer_v1_types_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_types_proto_depIdxs,
+		EnumInfos:         file_reviewer_v1_types_proto_enumTypes,
+		MessageInfos:      file_reviewer_v1_types_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_types_proto = out.File
+	file_reviewer_v1_types_proto_goTypes = nil
+	file_reviewer_v1_types_proto_depIdxs = nil
+}
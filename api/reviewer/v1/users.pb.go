@@ -0,0 +1,188 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: reviewer/v1/users.proto
+
+package reviewerv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SetIsActiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsActive      *bool                  `protobuf:"varint,2,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIsActiveRequest) Reset() {
+	*x = SetIsActiveRequest{}
+	mi := &file_reviewer_v1_users_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIsActiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIsActiveRequest) ProtoMessage() {}
+
+func (x *SetIsActiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_users_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIsActiveRequest.ProtoReflect.Descriptor instead.
+func (*SetIsActiveRequest) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_users_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SetIsActiveRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetIsActiveRequest) GetIsActive() bool {
+	if x != nil && x.IsActive != nil {
+		return *x.IsActive
+	}
+	return false
+}
+
+type SetIsActiveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetIsActiveResponse) Reset() {
+	*x = SetIsActiveResponse{}
+	mi := &file_reviewer_v1_users_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetIsActiveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetIsActiveResponse) ProtoMessage() {}
+
+func (x *SetIsActiveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_reviewer_v1_users_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetIsActiveResponse.ProtoReflect.Descriptor instead.
+func (*SetIsActiveResponse) Descriptor() ([]byte, []int) {
+	return file_reviewer_v1_users_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetIsActiveResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+var File_reviewer_v1_users_proto protoreflect.FileDescriptor
+
+const file_reviewer_v1_users_proto_rawDesc = "" +
+	"\n" +
+	"\x17reviewer/v1/users.proto\x12\vreviewer.v1\x1a\x17reviewer/v1/types.proto\"]\n" +
+	"\x12SetIsActiveRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12 \n" +
+	"\tis_active\x18\x02 \x01(\bH\x00R\bisActive\x88\x01\x01B\f\n" +
+	"\n" +
+	"_is_active\"<\n" +
+	"\x13SetIsActiveResponse\x12%\n" +
+	"\x04user\x18\x01 \x01(\v2\x11.reviewer.v1.UserR\x04user2`\n" +
+	"\fUsersService\x12P\n" +
+	"\vSetIsActive\x12\x1f.reviewer.v1.SetIsActiveRequest\x1a .reviewer.v1.SetIsActiveResponseBKZIgithub.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1;reviewerv1b\x06proto3"
+
+var (
+	file_reviewer_v1_users_proto_rawDescOnce sync.Once
+	file_reviewer_v1_users_proto_rawDescData []byte
+)
+
+func file_reviewer_v1_users_proto_rawDescGZIP() []byte {
+	file_reviewer_v1_users_proto_rawDescOnce.Do(func() {
+		file_reviewer_v1_users_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_reviewer_v1_users_proto_rawDesc), len(file_reviewer_v1_users_proto_rawDesc)))
+	})
+	return file_reviewer_v1_users_proto_rawDescData
+}
+
+var file_reviewer_v1_users_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_reviewer_v1_users_proto_goTypes = []any{
+	(*SetIsActiveRequest)(nil),  // 0: reviewer.v1.SetIsActiveRequest
+	(*SetIsActiveResponse)(nil), // 1: reviewer.v1.SetIsActiveResponse
+	(*User)(nil),                // 2: reviewer.v1.User
+}
+var file_reviewer_v1_users_proto_depIdxs = []int32{
+	2, // 0: reviewer.v1.SetIsActiveResponse.user:type_name -> reviewer.v1.User
+	0, // 1: reviewer.v1.UsersService.SetIsActive:input_type -> reviewer.v1.SetIsActiveRequest
+	1, // 2: reviewer.v1.UsersService.SetIsActive:output_type -> reviewer.v1.SetIsActiveResponse
+	2, // [2:3] is the sub-list for method output_type
+	1, // [1:2] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_reviewer_v1_users_proto_init() }
+func file_reviewer_v1_users_proto_init() {
+	if File_reviewer_v1_users_proto != nil {
+		return
+	}
+	file_reviewer_v1_types_proto_init()
+	file_reviewer_v1_users_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_reviewer_v1_users_proto_rawDesc), len(file_reviewer_v1_users_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_reviewer_v1_users_proto_goTypes,
+		DependencyIndexes: file_reviewer_v1_users_proto_depIdxs,
+		MessageInfos:      file_reviewer_v1_users_proto_msgTypes,
+	}.Build()
+	File_reviewer_v1_users_proto = out.File
+	file_reviewer_v1_users_proto_goTypes = nil
+	file_reviewer_v1_users_proto_depIdxs = nil
+}
@@ -2,89 +2,397 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/codeowners"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/config"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/graph"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/grpcapi"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/openapi"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/pr"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/ratelimit"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/repositories"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/stats"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/users"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type application struct {
-	config config
-	db     *pgxpool.Pool
-}
+	config    config.Config
+	db        database
+	validator *openapi.Validator
+	limiter   ratelimit.Limiter
+	clients   *ratelimit.Clients
+	// limiterDB is the pool of the postgres rate limiter, nil with other backends.
+	limiterDB database
+	workers   []worker
 
-type config struct {
-	addr string
-	db   dbConfig
+	inFlight atomic.Int64
 }
 
-type dbConfig struct {
-	dsn string
+// database is the connection pool, integration tests replace it with memdb.
+type database interface {
+	postgres.DB
+	Close()
 }
 
+// worker is a background job that runs until its context is cancelled.
+type worker func(ctx context.Context)
+
 // mount
 func (app *application) mount() http.Handler {
 	r := chi.NewRouter()
 
 	// Add middlewares
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(app.clientsOrDefault().RealIP)
+	r.Use(app.trackInFlight)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(time.Minute))
+	r.Use(middleware.Timeout(app.config.HTTP.RequestTimeout))
+	r.Use(app.limitBody)
+	if app.validator != nil {
+		r.Use(app.validator.Middleware)
+	}
 
 	// handlers
 	// for healthcheck
 	r.Get("/ping", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 		_, _ = w.Write([]byte("pong"))
 	})
 
+	svc := app.services()
+
 	// for teams
-	teamsService := teams.NewService(repo.New(app.db), app.db)
-	teamsHandler := teams.NewHandler(teamsService)
-	r.Get("/team/get", teamsHandler.GetTeamByName)
-	r.Post("/team/add", teamsHandler.CreateTeam)
-	r.Post("/team/deactivateUsers", teamsHandler.DeactivateUsers)
+	teamsHandler := teams.NewHandler(svc.teams)
+	r.Group(func(r chi.Router) {
+		r.Use(app.rateLimit("team"))
+		r.Get("/team/get", teamsHandler.GetTeamByName)
+		r.Post("/team/add", teamsHandler.CreateTeam)
+		r.Post("/team/members/upsert", teamsHandler.UpsertMembers)
+		r.Get("/team/fallbacks", teamsHandler.GetFallbacks)
+		r.Post("/team/setFallbacks", teamsHandler.SetFallbacks)
+		r.Get("/team/rules", teamsHandler.GetRules)
+		r.Post("/team/rules/setRequireSenior", teamsHandler.SetRequireSenior)
+		r.Post("/team/rules/setPair", teamsHandler.SetPairRule)
+		r.Post("/team/rules/deletePair", teamsHandler.DeletePairRule)
+		if app.config.Features.MassDeactivation {
+			r.Post("/team/deactivateUsers", teamsHandler.DeactivateUsers)
+		}
+	})
 
 	// for users
-	usersService := users.NewService(repo.New(app.db), app.db)
-	usersHandler := users.NewHandler(usersService)
-	r.Post("/users/setIsActive", usersHandler.SetUserActivity)
+	usersHandler := users.NewHandler(svc.users)
+	prHandler := pr.NewHandler(svc.pr)
+	r.Group(func(r chi.Router) {
+		r.Use(app.rateLimit("users"))
+		r.Post("/users/setIsActive", usersHandler.SetUserActivity)
+		r.Post("/users/bulkSetIsActive", usersHandler.BulkSetUserActivity)
+		r.Post("/users/absence/add", usersHandler.AddAbsence)
+		r.Get("/users/absence/list", usersHandler.ListAbsences)
+		r.Post("/users/absence/delete", usersHandler.DeleteAbsence)
+		r.Post("/users/setMaxOpenReviews", usersHandler.SetMaxOpenReviews)
+		r.Post("/users/setLevel", usersHandler.SetLevel)
+		r.Get("/users/getReview", prHandler.GetUserReviews)
+	})
 
 	// for PRs
-	prService := pr.NewService(repo.New(app.db), app.db)
-	prHandler := pr.NewHandler(prService)
-	r.Post("/pullRequest/create", prHandler.CreatePR)
-	r.Post("/pullRequest/merge", prHandler.MergePR)
-	r.Post("/pullRequest/reassign", prHandler.ReassignReviewer)
-	r.Get("/pullRequest/userReviews", prHandler.GetUserReviews)
+	r.Group(func(r chi.Router) {
+		r.Use(app.rateLimit("pullRequest"))
+		r.Post("/pullRequest/create", prHandler.CreatePR)
+		r.Post("/pullRequest/merge", prHandler.MergePR)
+		r.Post("/pullRequest/reassign", prHandler.ReassignReviewer)
+		r.Post("/pullRequest/previewAssignment", prHandler.PreviewAssignment)
+		r.Get("/pullRequest/list", prHandler.ListPRs)
+		r.Get("/pullRequest/userReviews", prHandler.GetUserReviews) // deprecated alias of /users/getReview
+	})
+
+	// for repositories
+	repositoriesHandler := repositories.NewHandler(svc.repositories)
+	r.Group(func(r chi.Router) {
+		r.Use(app.rateLimit("repository"))
+		r.Post("/repository/setTeam", repositoriesHandler.SetTeam)
+		r.Get("/repository/list", repositoriesHandler.List)
+	})
+
+	// for code owners
+	codeownersHandler := codeowners.NewHandler(svc.codeowners)
+	r.Group(func(r chi.Router) {
+		r.Use(app.rateLimit("codeowners"))
+		r.Post("/codeowners/upload", codeownersHandler.Upload)
+	})
 
 	// for stats
-	statsService := stats.NewService(repo.New(app.db), app.db)
-	statsHandler := stats.NewHandler(statsService)
-	r.Get("/stats", statsHandler.GetStats)
+	if svc.stats != nil {
+		statsHandler := stats.NewHandler(svc.stats)
+		r.Group(func(r chi.Router) {
+			r.Use(app.rateLimit("stats"))
+			r.Get("/stats", statsHandler.GetStats)
+		})
+	}
+
+	// for GraphQL
+	if app.config.Features.GraphQL {
+		graphHandler := graph.NewHandler(repo.New(app.db))
+		r.Group(func(r chi.Router) {
+			r.Use(app.rateLimit("graphql"))
+			r.Post("/graphql", graphHandler.ServeHTTP)
+		})
+	}
 
 	return r
 }
 
+// services are shared by the HTTP and gRPC APIs.
+type services struct {
+	teams        teams.Service
+	users        users.Service
+	pr           pr.Service
+	repositories repositories.Service
+	codeowners   codeowners.Service
+	stats        stats.Service // nil when the stats feature is disabled
+}
+
+func (app *application) services() services {
+	s := services{
+		teams:        teams.NewService(repo.New(app.db), app.db),
+		users:        users.NewService(repo.New(app.db), app.db),
+		pr:           pr.NewService(repo.New(app.db), app.db, app.config.Reviewers.Count),
+		repositories: repositories.NewService(repo.New(app.db), app.db),
+		codeowners:   codeowners.NewService(repo.New(app.db), app.db),
+	}
+	if app.config.Features.Stats {
+		s.stats = stats.NewService(repo.New(app.db), app.db)
+	}
+
+	return s
+}
+
+// grpcServer builds the gRPC API on top of the same services as mount.
+func (app *application) grpcServer() *grpcapi.Server {
+	svc := app.services()
+
+	opts := grpcapi.Options{MaxMessageBytes: int(app.config.HTTP.MaxBodyBytes)}
+	if app.limiter != nil {
+		opts.RateLimit = &grpcapi.RateLimit{
+			Limiter:  app.limiter,
+			Clients:  app.clientsOrDefault(),
+			LimitFor: app.config.RateLimit.LimitFor,
+		}
+	}
+
+	return grpcapi.NewServer(grpcapi.Services{
+		Teams:            svc.teams,
+		Users:            svc.users,
+		PullRequests:     svc.pr,
+		Stats:            svc.stats,
+		MassDeactivation: app.config.Features.MassDeactivation,
+	}, opts)
+}
+
+// rateLimit returns the rate limiting middleware of a route group,
+// or a no-op one when rate limiting is disabled.
+func (app *application) rateLimit(group string) func(http.Handler) http.Handler {
+	if app.limiter == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return ratelimit.Middleware(
+		app.limiter,
+		group,
+		app.config.RateLimit.LimitFor(group),
+		app.clientsOrDefault(),
+	)
+}
+
+// clientsOrDefault returns the client identifier, without one no API key is
+// known and no proxy is trusted.
+func (app *application) clientsOrDefault() *ratelimit.Clients {
+	if app.clients == nil {
+		return &ratelimit.Clients{}
+	}
+
+	return app.clients
+}
+
+// limitBody caps the request body at http.max_body_bytes, reading past it fails
+// and the handlers (or the OpenAPI validator) answer with INVALID_INPUT.
+func (app *application) limitBody(next http.Handler) http.Handler {
+	limit := app.config.HTTP.MaxBodyBytes
+	if limit <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// trackInFlight counts requests that are currently being served,
+// so the shutdown summary can report how many had to be drained.
+func (app *application) trackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		app.inFlight.Add(1)
+		defer app.inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (app *application) run(h http.Handler) error {
+	ln, err := net.Listen("tcp", app.config.HTTP.Addr)
+	if err != nil {
+		return err
+	}
+
+	var grpcLn net.Listener
+	if app.config.GRPC.Enabled {
+		grpcLn, err = net.Listen("tcp", app.config.GRPC.Addr)
+		if err != nil {
+			_ = ln.Close()
+			return err
+		}
+	}
+
+	return app.serve(ln, h, grpcLn)
+}
+
+// serve accepts HTTP connections on ln and, when grpcLn is not nil,
+// gRPC connections on grpcLn until SIGINT/SIGTERM is received,
+// then drains in-flight requests and calls, stops background workers
+// and closes the database pool, in that order.
+func (app *application) serve(ln net.Listener, h http.Handler, grpcLn net.Listener) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	srv := &http.Server{
-		Addr:         app.config.addr,
 		Handler:      h,
-		WriteTimeout: time.Second * 30,
-		ReadTimeout:  time.Second * 30,
-		IdleTimeout:  time.Minute,
+		WriteTimeout: app.config.HTTP.WriteTimeout,
+		ReadTimeout:  app.config.HTTP.ReadTimeout,
+		IdleTimeout:  app.config.HTTP.IdleTimeout,
+	}
+
+	// background workers
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var wg sync.WaitGroup
+	for _, w := range app.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w(workersCtx)
+		}()
 	}
 
-	slog.Info("server has started", "address", app.config.addr)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(ln)
+	}()
+
+	slog.Info("server has started", "address", ln.Addr().String())
+
+	var grpcSrv *grpcapi.Server
+	grpcErr := make(chan error, 1)
+	if grpcLn != nil {
+		grpcSrv = app.grpcServer()
+		go func() {
+			grpcErr <- grpcSrv.Serve(grpcLn)
+		}()
+
+		slog.Info("gRPC server has started", "address", grpcLn.Addr().String())
+	}
 
-	return srv.ListenAndServe()
+	select {
+	case err := <-grpcErr:
+		_ = srv.Close()
+		stopWorkers()
+		wg.Wait()
+		app.closeDB()
+		return err
+	case err := <-serveErr:
+		if grpcSrv != nil {
+			grpcSrv.Close()
+		}
+		stopWorkers()
+		wg.Wait()
+		app.closeDB()
+		return err
+	case <-ctx.Done():
+	}
+
+	started := time.Now()
+	inFlight := app.inFlight.Load()
+	slog.Info("shutdown signal received, draining requests",
+		"in_flight", inFlight,
+		"timeout", app.config.HTTP.ShutdownTimeout,
+	)
+
+	// stop receiving signals so a second one kills the process immediately
+	stop()
+
+	drainCtx, cancel := context.WithTimeout(context.Background(), app.config.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	// gRPC calls are drained concurrently with HTTP requests
+	grpcDrained := make(chan bool, 1)
+	if grpcSrv != nil {
+		go func() {
+			grpcDrained <- grpcSrv.Stop(drainCtx)
+		}()
+	} else {
+		grpcDrained <- true
+	}
+
+	shutdownErr := srv.Shutdown(drainCtx)
+	if shutdownErr != nil {
+		slog.Error("failed to drain requests in time", "error", shutdownErr)
+		_ = srv.Close()
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("server stopped with error", "error", err)
+	}
+	grpcGraceful := <-grpcDrained
+	if !grpcGraceful {
+		slog.Error("failed to drain gRPC calls in time")
+	}
+
+	stopWorkers()
+	wg.Wait()
+
+	app.closeDB()
+
+	aborted := app.inFlight.Load()
+	slog.Info("server has stopped",
+		"drained", inFlight-aborted,
+		"aborted", aborted,
+		"graceful", shutdownErr == nil && grpcGraceful,
+		"duration", time.Since(started),
+	)
+
+	return nil
+}
+
+func (app *application) closeDB() {
+	if app.db != nil {
+		app.db.Close()
+	}
+	if app.limiterDB != nil {
+		app.limiterDB.Close()
+	}
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServe_DrainsInFlightRequestsOnSignal(t *testing.T) {
+	app := &application{
+		config: config.Config{HTTP: config.HTTPConfig{ShutdownTimeout: 5 * time.Second}},
+	}
+
+	workerStopped := make(chan struct{})
+	app.workers = append(app.workers, func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerStopped)
+	})
+
+	requestStarted := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, _ *http.Request) {
+		close(requestStarted)
+		time.Sleep(300 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- app.serve(ln, app.trackInFlight(mux), nil)
+	}()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			resCh <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		resCh <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	select {
+	case <-requestStarted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("slow request never reached the handler")
+	}
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGTERM))
+
+	res := <-resCh
+	require.NoError(t, res.err)
+	assert.Equal(t, http.StatusOK, res.status)
+	assert.Equal(t, "done", res.body)
+
+	select {
+	case err := <-serveErr:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not return after shutdown")
+	}
+
+	select {
+	case <-workerStopped:
+	default:
+		t.Fatal("background worker was not stopped")
+	}
+
+	// the listener is closed, new connections are refused
+	_, err = net.DialTimeout("tcp", ln.Addr().String(), time.Second)
+	assert.Error(t, err)
+}
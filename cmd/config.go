@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/config"
+)
+
+const configUsage = "usage: main config print"
+
+// runConfig executes the `config` subcommand.
+func runConfig(cfg config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return fmt.Errorf("%s", configUsage)
+	}
+
+	return cfg.Print(os.Stdout)
+}
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/config"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/openapi"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/memdb"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/pgtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fuzzMaxBodyBytes keeps the body limit reachable by the fuzzer.
+const fuzzMaxBodyBytes = 4 << 10
+
+// errorCodes are the codes an ErrorResponse may carry.
+var errorCodes = map[string]bool{
+	errors.ErrTeamExists.Code:   true,
+	errors.ErrPRExists.Code:     true,
+	errors.ErrPRMerged.Code:     true,
+	errors.ErrNotAssigned.Code:  true,
+	errors.ErrNoCandidate.Code:  true,
+	errors.ErrNotFound.Code:     true,
+	errors.ErrRateLimited.Code:  true,
+	errors.ErrInvalidInput.Code: true,
+}
+
+// fuzzRouters returns the router with and without OpenAPI request validation,
+// so the handlers' own decoding is fuzzed too. Both share a database holding
+// team "backend" (author, r1, r2, r3) and the open PR "pr-1".
+func fuzzRouters(f *testing.F) map[bool]http.Handler {
+	f.Helper()
+
+	cfg := config.Default()
+	cfg.HTTP.MaxBodyBytes = fuzzMaxBodyBytes
+	db := pgtest.Open(f)
+
+	validator, err := openapi.NewValidator(false)
+	require.NoError(f, err)
+
+	routers := map[bool]http.Handler{
+		true:  (&application{config: cfg, db: db, validator: validator}).mount(),
+		false: (&application{config: cfg, db: db}).mount(),
+	}
+
+	for _, req := range []struct{ path, body string }{
+		{"/team/add", `{"team_name":"backend","members":[
+			{"user_id":"author","username":"Author","is_active":true},
+			{"user_id":"r1","username":"R1","is_active":true},
+			{"user_id":"r2","username":"R2","is_active":true},
+			{"user_id":"r3","username":"R3","is_active":true}]}`},
+		{"/pullRequest/create", `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"author"}`},
+	} {
+		r := httptest.NewRequest(http.MethodPost, req.path, strings.NewReader(req.body))
+		r.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		routers[true].ServeHTTP(rec, r)
+		require.Equal(f, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	return routers
+}
+
+// checkResponse asserts the response is never a 500 and every error is a well-formed ErrorResponse.
+func checkResponse(t *testing.T, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	require.Less(t, rec.Code, http.StatusInternalServerError, "body: %s", rec.Body.String())
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"), "body: %s", rec.Body.String())
+
+	if rec.Code < http.StatusBadRequest {
+		require.True(t, stdjson.Valid(rec.Body.Bytes()), "invalid JSON: %s", rec.Body.String())
+		return
+	}
+
+	var resp struct {
+		Error *struct {
+			Code      string              `json:"code"`
+			Message   string              `json:"message"`
+			Details   []errors.FieldError `json:"details"`
+			RequestID string              `json:"request_id"`
+		} `json:"error"`
+	}
+	dec := stdjson.NewDecoder(bytes.NewReader(rec.Body.Bytes()))
+	dec.DisallowUnknownFields()
+	require.NoError(t, dec.Decode(&resp), "body: %s", rec.Body.String())
+	require.NotNil(t, resp.Error, "body: %s", rec.Body.String())
+
+	assert.True(t, errorCodes[resp.Error.Code], "unexpected code %q", resp.Error.Code)
+	assert.NotEmpty(t, resp.Error.Message)
+	for _, d := range resp.Error.Details {
+		assert.NotEmpty(t, d.Field, "body: %s", rec.Body.String())
+		assert.NotEmpty(t, d.Reason, "body: %s", rec.Body.String())
+	}
+}
+
+// fuzzBody fuzzes the JSON body of a POST endpoint.
+func fuzzBody(f *testing.F, path string, seeds ...string) {
+	seeds = append(seeds,
+		``,
+		`null`,
+		`[]`,
+		`{`,
+		`{}`,
+		`{} {}`,
+		`{"unknown":1}`,
+		`"`+strings.Repeat("x", fuzzMaxBodyBytes)+`"`,
+	)
+	for _, s := range seeds {
+		f.Add([]byte(s), true)
+		f.Add([]byte(s), false)
+	}
+
+	routers := fuzzRouters(f)
+	f.Fuzz(func(t *testing.T, body []byte, validate bool) {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := httptest.NewRecorder()
+		routers[validate].ServeHTTP(rec, req)
+		checkResponse(t, rec)
+	})
+}
+
+// fuzzQuery fuzzes a query parameter of a GET endpoint.
+func fuzzQuery(f *testing.F, path, param string, seeds ...string) {
+	seeds = append(seeds, "", "ghost", "\x00", "%zz", strings.Repeat("x", 1000))
+	for _, s := range seeds {
+		f.Add(s, true)
+		f.Add(s, false)
+	}
+
+	routers := fuzzRouters(f)
+	f.Fuzz(func(t *testing.T, value string, validate bool) {
+		req := httptest.NewRequest(http.MethodGet, path+"?"+url.Values{param: {value}}.Encode(), nil)
+
+		rec := httptest.NewRecorder()
+		routers[validate].ServeHTTP(rec, req)
+		checkResponse(t, rec)
+	})
+}
+
+func FuzzTeamAdd(f *testing.F) {
+	fuzzBody(f, "/team/add",
+		`{"team_name":"payments","members":[{"user_id":"p1","username":"P1","is_active":true}]}`,
+		`{"team_name":"backend","members":[{"user_id":"x","username":"X","is_active":true}]}`,
+		`{"team_name":"","members":[]}`,
+		`{"team_name":"t","members":[{"user_id":"u\u0000","username":"U","is_active":true}]}`,
+		`{"team_name":1,"members":{}}`,
+	)
+}
+
+func FuzzTeamDeactivateUsers(f *testing.F) {
+	fuzzBody(f, "/team/deactivateUsers",
+		`{"users":["r3"]}`,
+		`{"users":["ghost"]}`,
+		`{"users":["r1","r2"],"dry_run":true}`,
+		`{"users":["r1"],"dry_run":"yes"}`,
+		`{"users":[]}`,
+		`{"users":[""]}`,
+		`{"users":["\u0000"]}`,
+		`{"users":`+strings.Repeat(`"r1",`, 300)+`"r1"]}`,
+	)
+}
+
+func FuzzUsersSetIsActive(f *testing.F) {
+	fuzzBody(f, "/users/setIsActive",
+		`{"user_id":"r1","is_active":true}`,
+		`{"user_id":"ghost","is_active":false}`,
+		`{"user_id":"r1"}`,
+		`{"user_id":"r1","is_active":"yes"}`,
+	)
+}
+
+func FuzzUsersBulkSetIsActive(f *testing.F) {
+	fuzzBody(f, "/users/bulkSetIsActive",
+		`{"users":[{"user_id":"r1","is_active":false},{"user_id":"ghost","is_active":true}]}`,
+		`{"users":[{"user_id":"r1","is_active":true},{"user_id":"r1","is_active":false}]}`,
+		`{"users":[{"user_id":"r1"}]}`,
+		`{"users":[]}`,
+	)
+}
+
+func FuzzUsersAbsenceAdd(f *testing.F) {
+	fuzzBody(f, "/users/absence/add",
+		`{"user_id":"r1","starts_at":"2026-01-01T00:00:00Z","ends_at":"2999-01-01T00:00:00+03:00"}`,
+		`{"user_id":"r1","starts_at":"2999-01-02T00:00:00Z","ends_at":"2999-01-01T00:00:00Z"}`,
+		`{"user_id":"ghost","starts_at":"2026-01-01T00:00:00Z","ends_at":"2999-01-01T00:00:00Z"}`,
+		`{"user_id":"r1","starts_at":"2026-01-01","ends_at":"tomorrow"}`,
+		`{"user_id":"r1","starts_at":"0000-01-01T00:00:00Z","ends_at":"9999-12-31T23:59:59Z"}`,
+		`{"user_id":"r1"}`,
+	)
+}
+
+func FuzzUsersAbsenceDelete(f *testing.F) {
+	fuzzBody(f, "/users/absence/delete",
+		`{"absence_id":"abs1"}`,
+		`{"absence_id":""}`,
+		`{"absence_id":1}`,
+	)
+}
+
+func FuzzUsersSetMaxOpenReviews(f *testing.F) {
+	fuzzBody(f, "/users/setMaxOpenReviews",
+		`{"user_id":"r1","max_open_reviews":2}`,
+		`{"user_id":"r1","max_open_reviews":null}`,
+		`{"user_id":"r1","max_open_reviews":-1}`,
+		`{"user_id":"r1","max_open_reviews":3000000000}`,
+		`{"user_id":"ghost","max_open_reviews":0}`,
+		`{"user_id":""}`,
+	)
+}
+
+func FuzzUsersSetLevel(f *testing.F) {
+	fuzzBody(f, "/users/setLevel",
+		`{"user_id":"r1","level":"senior"}`,
+		`{"user_id":"r1","level":"maintainer"}`,
+		`{"user_id":"r1","level":"boss"}`,
+		`{"user_id":"ghost","level":"regular"}`,
+		`{"user_id":"","level":""}`,
+	)
+}
+
+func FuzzTeamMembersUpsert(f *testing.F) {
+	fuzzBody(f, "/team/members/upsert",
+		`{"team_name":"backend","members":[{"user_id":"r4","username":"R4","is_active":true}],"remove":["r3"]}`,
+		`{"team_name":"backend","remove":["author","ghost"]}`,
+		`{"team_name":"ghost","members":[{"user_id":"x","username":"X","is_active":true}]}`,
+		`{"team_name":"backend","members":[{"user_id":"","username":"","is_active":true}],"remove":[""]}`,
+		`{"team_name":"backend"}`,
+	)
+}
+
+func FuzzTeamSetFallbacks(f *testing.F) {
+	fuzzBody(f, "/team/setFallbacks",
+		`{"team_name":"backend","fallback_teams":[]}`,
+		`{"team_name":"backend","fallback_teams":["backend"]}`,
+		`{"team_name":"backend","fallback_teams":["ghost","ghost",""]}`,
+		`{"team_name":"ghost","fallback_teams":["backend"]}`,
+		`{"team_name":"backend","fallback_teams":"backend"}`,
+		`{"team_name":"backend","fallback_teams":`+strings.Repeat(`"t",`, 20)+`"t"]}`,
+	)
+}
+
+func FuzzTeamFallbacks(f *testing.F) {
+	fuzzQuery(f, "/team/fallbacks", "team_name", "backend")
+}
+
+func FuzzTeamRulesSetRequireSenior(f *testing.F) {
+	fuzzBody(f, "/team/rules/setRequireSenior",
+		`{"team_name":"backend","require_senior":true}`,
+		`{"team_name":"backend","require_senior":false}`,
+		`{"team_name":"backend"}`,
+		`{"team_name":"ghost","require_senior":true}`,
+		`{"team_name":"","require_senior":"yes"}`,
+	)
+}
+
+func FuzzTeamRulesSetPair(f *testing.F) {
+	fuzzBody(f, "/team/rules/setPair",
+		`{"team_name":"backend","reviewer_id":"r1","author_id":"author","kind":"never"}`,
+		`{"team_name":"backend","reviewer_id":"r2","author_id":"author","kind":"prefer"}`,
+		`{"team_name":"backend","reviewer_id":"r1","author_id":"r1","kind":"never"}`,
+		`{"team_name":"ghost","reviewer_id":"r1","author_id":"author","kind":"always"}`,
+		`{"team_name":"","reviewer_id":"","author_id":"","kind":""}`,
+	)
+}
+
+func FuzzTeamRulesDeletePair(f *testing.F) {
+	fuzzBody(f, "/team/rules/deletePair",
+		`{"team_name":"backend","reviewer_id":"r1","author_id":"author"}`,
+		`{"team_name":"backend","reviewer_id":"ghost","author_id":"author"}`,
+		`{"team_name":"","reviewer_id":"","author_id":""}`,
+	)
+}
+
+func FuzzTeamRules(f *testing.F) {
+	fuzzQuery(f, "/team/rules", "team_name", "backend")
+}
+
+func FuzzPullRequestCreate(f *testing.F) {
+	fuzzBody(f, "/pullRequest/create",
+		`{"pull_request_id":"pr-2","pull_request_name":"Fix","author_id":"r1"}`,
+		`{"pull_request_id":"pr-1","pull_request_name":"Again","author_id":"author"}`,
+		`{"pull_request_id":"pr-3","pull_request_name":"Orphan","author_id":"ghost"}`,
+		`{"pull_request_id":"","pull_request_name":"","author_id":""}`,
+		`{"pull_request_id":"pr-4","pull_request_name":"Owned","author_id":"author","repository":"api","changed_files":["internal/pr/service.go"]}`,
+		`{"pull_request_id":"pr-5","pull_request_name":"No repo","author_id":"author","changed_files":["","a"]}`,
+	)
+}
+
+func FuzzPullRequestMerge(f *testing.F) {
+	fuzzBody(f, "/pullRequest/merge",
+		`{"pull_request_id":"pr-1"}`,
+		`{"pull_request_id":"ghost"}`,
+		`{"pull_request_id":""}`,
+	)
+}
+
+func FuzzPullRequestReassign(f *testing.F) {
+	fuzzBody(f, "/pullRequest/reassign",
+		`{"pull_request_id":"pr-1","old_user_id":"r1"}`,
+		`{"pull_request_id":"pr-1","old_user_id":"author"}`,
+		`{"pull_request_id":"ghost","old_user_id":"r1"}`,
+		`{"pull_request_id":"","old_user_id":""}`,
+	)
+}
+
+func FuzzPullRequestPreviewAssignment(f *testing.F) {
+	fuzzBody(f, "/pullRequest/previewAssignment",
+		`{"author_id":"author"}`,
+		`{"author_id":"ghost"}`,
+		`{"author_id":""}`,
+		`{"author_id":"author","repository":"api","changed_files":["README.md","docs/"]}`,
+	)
+}
+
+func FuzzRepositorySetTeam(f *testing.F) {
+	fuzzBody(f, "/repository/setTeam",
+		`{"repository_name":"api","team_name":"backend"}`,
+		`{"repository_name":"api","team_name":"ghost"}`,
+		`{"repository_name":"","team_name":""}`,
+		`{"repository_name":["api"],"team_name":"backend"}`,
+	)
+}
+
+func FuzzCodeOwnersUpload(f *testing.F) {
+	fuzzBody(f, "/codeowners/upload",
+		`{"repository":"api","content":"* @r1\n/internal/ @acme/backend dev@example.com # api\n"}`,
+		`{"repository":"api","content":"!*.md @r1\nsrc/[ab].go @r2\n*.txt r3\n/ @r1\n"}`,
+		`{"repository":"api","content":"\\#notes @ghost\r\n**/logs/** @acme/\n"}`,
+		`{"repository":"","content":""}`,
+	)
+}
+
+func FuzzGraphQL(f *testing.F) {
+	fuzzBody(f, "/graphql",
+		`{"query":"{ team(name: \"backend\") { members { id reviews { id reviewers { id } } } } }"}`,
+		`{"query":"query($id: ID!) { user(id: $id) { id } }","variables":{"id":"r1"}}`,
+		`{"query":"{ nope }"}`,
+		`{"query":""}`,
+		`{"query":"`+strings.Repeat("{ team(name: \\\"x\\\") ", 20)+`"}`,
+	)
+}
+
+func FuzzTeamGet(f *testing.F) {
+	fuzzQuery(f, "/team/get", "team_name", "backend")
+}
+
+func FuzzUsersGetReview(f *testing.F) {
+	fuzzQuery(f, "/users/getReview", "user_id", "r1")
+}
+
+func FuzzRepositoryList(f *testing.F) {
+	fuzzQuery(f, "/repository/list", "team_name", "backend")
+}
+
+func FuzzPullRequestList(f *testing.F) {
+	fuzzQuery(f, "/pullRequest/list", "status", "OPEN", "CLOSED")
+}
+
+func FuzzStats(f *testing.F) {
+	fuzzQuery(f, "/stats", "repository", "api")
+}
+
+func FuzzUsersAbsenceList(f *testing.F) {
+	fuzzQuery(f, "/users/absence/list", "user_id", "r1")
+}
+
+func TestMount_RejectsLargeBodies(t *testing.T) {
+	cfg := config.Default()
+	cfg.HTTP.MaxBodyBytes = 64
+
+	validator, err := openapi.NewValidator(false)
+	require.NoError(t, err)
+
+	body := `{"users":["` + strings.Repeat("u", 100) + `"]}`
+	for name, app := range map[string]*application{
+		"validated": {config: cfg, db: memdb.New(), validator: validator},
+		"handler":   {config: cfg, db: memdb.New()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/team/deactivateUsers", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			app.mount().ServeHTTP(rec, req)
+
+			checkResponse(t, rec)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"field":"body","reason":"must not exceed 64 bytes"`)
+		})
+	}
+}
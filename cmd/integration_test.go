@@ -0,0 +1,1301 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/config"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/openapi"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/pgtest"
+	"github.com/Joskmo/avito-trainee-assignment-api/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer mounts the full router on an in-memory database.
+// Responses are validated against docs/openapi.yml, so a handler that
+// drifts from the spec fails the tests with INTERNAL_ERROR.
+func newTestServer(t *testing.T) (*httptest.Server, *client.Client) {
+	t.Helper()
+
+	validator, err := openapi.NewValidator(true)
+	require.NoError(t, err)
+
+	app := &application{
+		config:    config.Default(),
+		db:        pgtest.Open(t),
+		validator: validator,
+	}
+
+	srv := httptest.NewServer(app.mount())
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(srv.URL,
+		client.WithHTTPClient(srv.Client()),
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+	)
+	require.NoError(t, err)
+
+	return srv, c
+}
+
+func addTeam(t *testing.T, c *client.Client, name string, userIDs ...string) {
+	t.Helper()
+
+	team := client.Team{TeamName: name}
+	for _, id := range userIDs {
+		team.Members = append(team.Members, client.TeamMember{UserID: id, Username: "name-" + id, IsActive: true})
+	}
+
+	_, err := c.CreateTeam(context.Background(), team)
+	require.NoError(t, err)
+}
+
+func requireCode(t *testing.T, want *client.Error, err error) {
+	t.Helper()
+
+	require.Error(t, err)
+	require.ErrorIs(t, err, want, "got %v", err)
+}
+
+func TestIntegration_Ping(t *testing.T) {
+	_, c := newTestServer(t)
+	require.NoError(t, c.Ping(context.Background()))
+}
+
+func TestIntegration_Teams(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+
+	team, err := c.CreateTeam(ctx, client.Team{
+		TeamName: "backend",
+		Members: []client.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: false},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "backend", team.TeamName)
+	assert.Len(t, team.Members, 2)
+
+	t.Run("get", func(t *testing.T) {
+		got, err := c.GetTeam(ctx, "backend")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []client.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: false},
+		}, got.Members)
+	})
+
+	t.Run("TEAM_EXISTS", func(t *testing.T) {
+		_, err := c.CreateTeam(ctx, client.Team{
+			TeamName: "backend",
+			Members:  []client.TeamMember{{UserID: "u3", Username: "Carol", IsActive: true}},
+		})
+		requireCode(t, client.ErrTeamExists, err)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.CreateTeam(ctx, client.Team{TeamName: "", Members: []client.TeamMember{}})
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.GetTeam(ctx, "nobody")
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_Users(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "u1", "u2")
+
+	t.Run("setIsActive", func(t *testing.T) {
+		user, err := c.SetIsActive(ctx, "u2", false)
+		require.NoError(t, err)
+		assert.Equal(t, client.User{UserID: "u2", Username: "name-u2", TeamName: "backend", IsActive: false}, *user)
+
+		team, err := c.GetTeam(ctx, "backend")
+		require.NoError(t, err)
+		for _, m := range team.Members {
+			assert.Equal(t, m.UserID != "u2", m.IsActive, m.UserID)
+		}
+	})
+
+	t.Run("setIsActive NOT_FOUND", func(t *testing.T) {
+		_, err := c.SetIsActive(ctx, "ghost", true)
+		requireCode(t, client.ErrNotFound, err)
+	})
+
+	t.Run("bulkSetIsActive", func(t *testing.T) {
+		results, err := c.BulkSetIsActive(ctx, map[string]bool{"u1": true, "u2": true, "ghost": false})
+		require.NoError(t, err)
+		assert.Equal(t, []client.BulkResult{
+			{UserID: "ghost", Result: "not_found"},
+			{UserID: "u1", Result: "unchanged", User: &client.User{UserID: "u1", Username: "name-u1", TeamName: "backend", IsActive: true}},
+			{UserID: "u2", Result: "updated", User: &client.User{UserID: "u2", Username: "name-u2", TeamName: "backend", IsActive: true}},
+		}, results)
+
+		_, err = c.BulkSetIsActive(ctx, map[string]bool{})
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("getReview without reviews", func(t *testing.T) {
+		reviews, err := c.GetUserReviews(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, "u1", reviews.UserID)
+		assert.Empty(t, reviews.PullRequests)
+	})
+}
+
+func TestIntegration_PullRequests(t *testing.T) {
+	ctx := context.Background()
+	srv, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2", "r3")
+
+	pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+	require.NoError(t, err)
+	assert.Equal(t, client.StatusOpen, pr.Status)
+	require.Len(t, pr.AssignedReviewers, 2)
+	assert.NotContains(t, pr.AssignedReviewers, "author")
+
+	t.Run("create PR_EXISTS", func(t *testing.T) {
+		_, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Again", AuthorID: "author"})
+		requireCode(t, client.ErrPRExists, err)
+	})
+
+	t.Run("create NOT_FOUND author", func(t *testing.T) {
+		_, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-x", PullRequestName: "Orphan", AuthorID: "ghost"})
+		requireCode(t, client.ErrNotFound, err)
+	})
+
+	t.Run("getReview lists the PR", func(t *testing.T) {
+		reviews, err := c.GetUserReviews(ctx, pr.AssignedReviewers[0])
+		require.NoError(t, err)
+		require.Len(t, reviews.PullRequests, 1)
+		assert.Equal(t, "pr-1", reviews.PullRequests[0].PullRequestID)
+
+		// the deprecated alias serves the same payload
+		resp, err := srv.Client().Get(srv.URL + "/pullRequest/userReviews?user_id=" + pr.AssignedReviewers[0])
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var alias client.UserReviews
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&alias))
+		assert.Equal(t, *reviews, alias)
+	})
+
+	t.Run("reassign", func(t *testing.T) {
+		old := pr.AssignedReviewers[0]
+		res, err := c.ReassignReviewer(ctx, "pr-1", old)
+		require.NoError(t, err)
+		assert.NotEqual(t, old, res.ReplacedBy)
+		assert.NotEqual(t, "author", res.ReplacedBy)
+		assert.Contains(t, res.PR.AssignedReviewers, res.ReplacedBy)
+		assert.NotContains(t, res.PR.AssignedReviewers, old)
+		assert.Len(t, res.PR.AssignedReviewers, 2)
+
+		pr = &res.PR
+	})
+
+	t.Run("reassign NOT_ASSIGNED", func(t *testing.T) {
+		_, err := c.ReassignReviewer(ctx, "pr-1", "author")
+		requireCode(t, client.ErrNotAssigned, err)
+	})
+
+	t.Run("reassign NO_CANDIDATE", func(t *testing.T) {
+		// the only other member is already a reviewer
+		addTeam(t, c, "small", "s-author", "s1", "s2")
+		small, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-small", PullRequestName: "Tiny", AuthorID: "s-author"})
+		require.NoError(t, err)
+		require.Len(t, small.AssignedReviewers, 2)
+
+		_, err = c.ReassignReviewer(ctx, "pr-small", small.AssignedReviewers[0])
+		requireCode(t, client.ErrNoCandidate, err)
+	})
+
+	t.Run("reassign NOT_FOUND", func(t *testing.T) {
+		_, err := c.ReassignReviewer(ctx, "pr-missing", "r1")
+		requireCode(t, client.ErrNotFound, err)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		merged, err := c.MergePR(ctx, "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, client.StatusMerged, merged.Status)
+		assert.ElementsMatch(t, pr.AssignedReviewers, merged.AssignedReviewers)
+
+		// merging again is a no-op
+		again, err := c.MergePR(ctx, "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, *merged, *again)
+	})
+
+	t.Run("reassign PR_MERGED", func(t *testing.T) {
+		_, err := c.ReassignReviewer(ctx, "pr-1", pr.AssignedReviewers[0])
+		requireCode(t, client.ErrPRMerged, err)
+	})
+
+	t.Run("merge NOT_FOUND", func(t *testing.T) {
+		_, err := c.MergePR(ctx, "pr-missing")
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_CreatePRWithoutCandidates(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "solo", "alone")
+
+	pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-solo", PullRequestName: "Lonely", AuthorID: "alone"})
+	require.NoError(t, err)
+	assert.Empty(t, pr.AssignedReviewers)
+}
+
+func TestIntegration_FallbackTeams(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "mobile", "author", "m1")
+	addTeam(t, c, "frontend", "f1")
+	addTeam(t, c, "backend", "b1", "b2")
+
+	fallbacks, err := c.SetTeamFallbacks(ctx, "mobile", "frontend", "backend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "backend"}, fallbacks)
+	fallbacks, err = c.GetTeamFallbacks(ctx, "mobile")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend", "backend"}, fallbacks)
+
+	t.Run("missing reviewers come from fallback teams in order", func(t *testing.T) {
+		preview, err := c.PreviewAssignment(ctx, "author")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"m1"}, preview.Candidates)
+		assert.Equal(t, []client.FallbackCandidate{
+			{UserID: "f1", TeamName: "frontend"},
+			{UserID: "b1", TeamName: "backend"},
+			{UserID: "b2", TeamName: "backend"},
+		}, preview.FallbackCandidates)
+		assert.Equal(t, 2, preview.ReviewersCount)
+
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"m1", "f1"}, pr.AssignedReviewers)
+		assert.Equal(t, []string{"f1"}, pr.FallbackReviewers)
+
+		// the next fallback team is used once the first has nobody left
+		_, err = c.SetIsActive(ctx, "m1", false)
+		require.NoError(t, err)
+		_, err = c.SetIsActive(ctx, "f1", false)
+		require.NoError(t, err)
+		pr, err = c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-2", PullRequestName: "Fix search", AuthorID: "author"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"b1", "b2"}, pr.AssignedReviewers)
+		assert.ElementsMatch(t, []string{"b1", "b2"}, pr.FallbackReviewers)
+
+		// dropped fallback team members are listed with the team mates
+		preview, err = c.PreviewAssignment(ctx, "author")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []client.Exclusion{
+			{UserID: "author", TeamName: "mobile", Reason: "author"},
+			{UserID: "m1", TeamName: "mobile", Reason: "inactive"},
+			{UserID: "f1", TeamName: "frontend", Reason: "inactive"},
+		}, preview.Excluded)
+	})
+
+	t.Run("a team with enough candidates does not use fallbacks", func(t *testing.T) {
+		addTeam(t, c, "platform", "p-author", "p1", "p2")
+		_, err := c.SetTeamFallbacks(ctx, "platform", "backend")
+		require.NoError(t, err)
+		preview, err := c.PreviewAssignment(ctx, "p-author")
+		require.NoError(t, err)
+		assert.Empty(t, preview.FallbackCandidates)
+	})
+
+	t.Run("an empty list removes the fallbacks", func(t *testing.T) {
+		fallbacks, err := c.SetTeamFallbacks(ctx, "mobile")
+		require.NoError(t, err)
+		assert.Empty(t, fallbacks)
+
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-3", PullRequestName: "Lonely", AuthorID: "author"})
+		require.NoError(t, err)
+		assert.Empty(t, pr.AssignedReviewers)
+		assert.Empty(t, pr.FallbackReviewers)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.SetTeamFallbacks(ctx, "mobile", "mobile")
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.SetTeamFallbacks(ctx, "mobile", "backend", "backend")
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.SetTeamFallbacks(ctx, "mobile", "")
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.SetTeamFallbacks(ctx, "ghost", "backend")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.SetTeamFallbacks(ctx, "mobile", "backend", "ghost")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.GetTeamFallbacks(ctx, "ghost")
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_CodeOwners(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "b1", "b2", "b3")
+	addTeam(t, c, "docs", "d1")
+	for _, name := range []string{"api", "web"} {
+		_, err := c.SetRepositoryTeam(ctx, name, "backend")
+		require.NoError(t, err)
+	}
+
+	upload, err := c.UploadCodeOwners(ctx, "api", `# owners of the api repository
+*             @b3
+/internal/pr/ @b1 @ghost
+*.md          @acme/docs dev@example.com @acme/nobody
+`)
+	require.NoError(t, err)
+	assert.Equal(t, "api", upload.Repository)
+	assert.Equal(t, []client.CodeOwnersRule{
+		{Line: 2, Pattern: "*", Owners: []string{"@b3"}},
+		{Line: 3, Pattern: "/internal/pr/", Owners: []string{"@b1", "@ghost"}},
+		{Line: 4, Pattern: "*.md", Owners: []string{"@acme/docs", "dev@example.com", "@acme/nobody"}},
+	}, upload.Rules)
+	assert.Equal(t, []string{"@ghost", "dev@example.com", "@acme/nobody"}, upload.UnknownOwners)
+
+	t.Run("owners of the changed files are preferred", func(t *testing.T) {
+		files := []string{"internal/pr/service.go", "README.md"}
+		preview, err := c.PreviewAssignmentForChanges(ctx, "author", "api", files)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b1", "d1"}, preview.OwnerCandidates)
+		assert.ElementsMatch(t, []string{"b1", "b2", "b3"}, preview.Candidates)
+
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{
+			PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author",
+			Repository: "api", ChangedFiles: files,
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"b1", "d1"}, pr.AssignedReviewers)
+		assert.ElementsMatch(t, []string{"b1", "d1"}, pr.OwnerReviewers)
+	})
+
+	t.Run("team mates fill the remaining places", func(t *testing.T) {
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{
+			PullRequestID: "pr-2", PullRequestName: "Fix build", AuthorID: "author",
+			Repository: "api", ChangedFiles: []string{"Makefile"},
+		})
+		require.NoError(t, err)
+		assert.Len(t, pr.AssignedReviewers, 2)
+		assert.Contains(t, pr.AssignedReviewers, "b3")
+		assert.Equal(t, []string{"b3"}, pr.OwnerReviewers)
+	})
+
+	t.Run("a repository without CODEOWNERS has no owners", func(t *testing.T) {
+		preview, err := c.PreviewAssignmentForChanges(ctx, "author", "web", []string{"README.md"})
+		require.NoError(t, err)
+		assert.Empty(t, preview.OwnerCandidates)
+	})
+
+	t.Run("dropped owners are excluded with a reason", func(t *testing.T) {
+		for _, id := range []string{"b1", "d1"} {
+			_, err := c.SetIsActive(ctx, id, false)
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_, err := c.SetIsActive(ctx, id, true)
+				require.NoError(t, err)
+			})
+		}
+
+		preview, err := c.PreviewAssignmentForChanges(ctx, "author", "api", []string{"internal/pr/service.go", "README.md"})
+		require.NoError(t, err)
+		assert.Empty(t, preview.OwnerCandidates)
+		// b1 is an owner and a team mate, but listed once
+		assert.ElementsMatch(t, []client.Exclusion{
+			{UserID: "author", TeamName: "backend", Reason: "author"},
+			{UserID: "b1", TeamName: "backend", Reason: "inactive"},
+			{UserID: "d1", TeamName: "docs", Reason: "inactive"},
+		}, preview.Excluded)
+	})
+
+	t.Run("a new upload replaces the file", func(t *testing.T) {
+		upload, err := c.UploadCodeOwners(ctx, "api", "* @b2\n")
+		require.NoError(t, err)
+		assert.Empty(t, upload.UnknownOwners)
+
+		preview, err := c.PreviewAssignmentForChanges(ctx, "author", "api", []string{"internal/pr/service.go"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b2"}, preview.OwnerCandidates)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.UploadCodeOwners(ctx, "api", "*.go @b1\n!*.md @b2\n*.txt b2\n")
+		requireCode(t, client.ErrInvalidInput, err)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Len(t, apiErr.Details, 2)
+		assert.Equal(t, "content", apiErr.Details[0].Field)
+		assert.Contains(t, apiErr.Details[0].Reason, "line 2")
+		assert.Contains(t, apiErr.Details[1].Reason, "line 3")
+
+		_, err = c.UploadCodeOwners(ctx, "", "* @b1")
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.PreviewAssignmentForChanges(ctx, "author", "", []string{"README.md"})
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.CreatePR(ctx, client.CreatePRRequest{
+			PullRequestID: "pr-3", PullRequestName: "Empty path", AuthorID: "author",
+			Repository: "api", ChangedFiles: []string{""},
+		})
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.UploadCodeOwners(ctx, "ghost", "* @b1")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.PreviewAssignmentForChanges(ctx, "author", "ghost", []string{"README.md"})
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_Repositories(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "b1")
+	addTeam(t, c, "mobile", "m1")
+
+	for name, team := range map[string]string{"api": "backend", "app": "mobile", "worker": "mobile"} {
+		_, err := c.SetRepositoryTeam(ctx, name, team)
+		require.NoError(t, err)
+	}
+
+	t.Run("ownership", func(t *testing.T) {
+		repos, err := c.ListRepositories(ctx, "mobile")
+		require.NoError(t, err)
+		assert.Equal(t, []client.Repository{
+			{RepositoryName: "app", TeamName: "mobile"},
+			{RepositoryName: "worker", TeamName: "mobile"},
+		}, repos)
+
+		// a repository is handed over by setting its team again
+		moved, err := c.SetRepositoryTeam(ctx, "worker", "backend")
+		require.NoError(t, err)
+		assert.Equal(t, &client.Repository{RepositoryName: "worker", TeamName: "backend"}, moved)
+		repos, err = c.ListRepositories(ctx, "")
+		require.NoError(t, err)
+		assert.Equal(t, []client.Repository{
+			{RepositoryName: "api", TeamName: "backend"},
+			{RepositoryName: "app", TeamName: "mobile"},
+			{RepositoryName: "worker", TeamName: "backend"},
+		}, repos)
+	})
+
+	for _, req := range []client.CreatePRRequest{
+		{PullRequestID: "api#1", PullRequestName: "Add search", AuthorID: "author", Repository: "api"},
+		{PullRequestID: "api#2", PullRequestName: "Fix search", AuthorID: "author", Repository: "api"},
+		{PullRequestID: "app#1", PullRequestName: "Add search", AuthorID: "author", Repository: "app"},
+		{PullRequestID: "pr-1", PullRequestName: "Old style", AuthorID: "author"},
+	} {
+		pr, err := c.CreatePR(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, req.Repository, pr.Repository)
+	}
+	merged, err := c.MergePRInRepository(ctx, "api#2", "api")
+	require.NoError(t, err)
+	assert.Equal(t, "api", merged.Repository)
+
+	t.Run("list PRs", func(t *testing.T) {
+		prs, err := c.ListPRs(ctx, "", "")
+		require.NoError(t, err)
+		assert.Len(t, prs, 4)
+
+		prs, err = c.ListPRs(ctx, "api", "")
+		require.NoError(t, err)
+		assert.Equal(t, []client.PullRequestShort{
+			{PullRequestID: "api#1", PullRequestName: "Add search", AuthorID: "author", Status: "OPEN", Repository: "api"},
+			{PullRequestID: "api#2", PullRequestName: "Fix search", AuthorID: "author", Status: "MERGED", Repository: "api"},
+		}, prs)
+
+		prs, err = c.ListPRs(ctx, "api", "OPEN")
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.Equal(t, "api#1", prs[0].PullRequestID)
+
+		prs, err = c.ListPRs(ctx, "worker", "")
+		require.NoError(t, err)
+		assert.Empty(t, prs)
+	})
+
+	t.Run("user reviews and stats by repository", func(t *testing.T) {
+		reviews, err := c.GetUserReviewsInRepository(ctx, "b1", "app")
+		require.NoError(t, err)
+		require.Len(t, reviews.PullRequests, 1)
+		assert.Equal(t, "app#1", reviews.PullRequests[0].PullRequestID)
+		assert.Equal(t, "app", reviews.PullRequests[0].Repository)
+
+		reviews, err = c.GetUserReviews(ctx, "b1")
+		require.NoError(t, err)
+		assert.Len(t, reviews.PullRequests, 4)
+
+		stats, err := c.RepositoryStats(ctx, "api")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []client.PRStatusStat{{Status: "OPEN", Count: 1}, {Status: "MERGED", Count: 1}}, stats.PRStatusDistribution)
+		assert.Equal(t, []client.ReviewerStat{{ReviewerID: "b1", AssignmentCount: 2}}, stats.TopReviewers)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.SetRepositoryTeam(ctx, "", "backend")
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.SetRepositoryTeam(ctx, "api", "")
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.ListPRs(ctx, "", "CLOSED")
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.SetRepositoryTeam(ctx, "api", "ghost")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "ghost#1", PullRequestName: "Lost", AuthorID: "author", Repository: "ghost"})
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.ListPRs(ctx, "ghost", "")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.GetUserReviewsInRepository(ctx, "b1", "ghost")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.RepositoryStats(ctx, "ghost")
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+// TestIntegration_PRIDsPerRepository creates the same PR id in two repositories
+// and without one, every call touches only the PR of its repository.
+func TestIntegration_PRIDsPerRepository(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "b1", "b2", "b3")
+	for _, name := range []string{"api", "web"} {
+		_, err := c.SetRepositoryTeam(ctx, name, "backend")
+		require.NoError(t, err)
+	}
+
+	// b3 joins once the PRs are created, so b1 and b2 review all of them
+	_, err := c.SetIsActive(ctx, "b3", false)
+	require.NoError(t, err)
+	for _, repository := range []string{"api", "web", ""} {
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Change " + repository, AuthorID: "author", Repository: repository})
+		require.NoError(t, err)
+		assert.Equal(t, repository, pr.Repository)
+		assert.ElementsMatch(t, []string{"b1", "b2"}, pr.AssignedReviewers)
+	}
+	_, err = c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Again", AuthorID: "author", Repository: "web"})
+	requireCode(t, client.ErrPRExists, err)
+	_, err = c.SetIsActive(ctx, "b3", true)
+	require.NoError(t, err)
+
+	merged, err := c.MergePRInRepository(ctx, "pr-1", "api")
+	require.NoError(t, err)
+	assert.Equal(t, "MERGED", merged.Status)
+	assert.Equal(t, "Change api", merged.PullRequestName)
+
+	reassigned, err := c.ReassignReviewerInRepository(ctx, "pr-1", "web", "b1")
+	require.NoError(t, err)
+	assert.Equal(t, "b3", reassigned.ReplacedBy)
+	assert.Equal(t, "web", reassigned.PR.Repository)
+	assert.ElementsMatch(t, []string{"b2", "b3"}, reassigned.PR.AssignedReviewers)
+
+	prs, err := c.ListPRs(ctx, "", "")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []client.PullRequestShort{
+		{PullRequestID: "pr-1", PullRequestName: "Change api", AuthorID: "author", Status: "MERGED", Repository: "api"},
+		{PullRequestID: "pr-1", PullRequestName: "Change web", AuthorID: "author", Status: "OPEN", Repository: "web"},
+		{PullRequestID: "pr-1", PullRequestName: "Change ", AuthorID: "author", Status: "OPEN"},
+	}, prs)
+
+	// b1 still reviews the PRs of api and without a repository
+	reviews, err := c.GetUserReviews(ctx, "b1")
+	require.NoError(t, err)
+	repositories := make([]string, len(reviews.PullRequests))
+	for i, pr := range reviews.PullRequests {
+		repositories[i] = pr.Repository
+	}
+	assert.ElementsMatch(t, []string{"api", ""}, repositories)
+
+	// the merged PR of api is skipped, the open ones are handed over one by one
+	plan, err := c.PlanDeactivation(ctx, []string{"b2"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []client.Reassignment{
+		{PullRequestID: "pr-1", OldReviewerID: "b2", NewReviewerID: "b3"},
+		{PullRequestID: "pr-1", Repository: "web", OldReviewerID: "b2", NewReviewerID: "b1"},
+	}, plan.Reassignments)
+
+	t.Run("PR_MERGED", func(t *testing.T) {
+		_, err := c.ReassignReviewerInRepository(ctx, "pr-1", "api", "b1")
+		requireCode(t, client.ErrPRMerged, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.MergePRInRepository(ctx, "pr-1", "ghost")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.ReassignReviewerInRepository(ctx, "pr-2", "web", "b2")
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_UpsertTeamMembers(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2", "idle")
+	addTeam(t, c, "frontend", "f1", "f2")
+
+	// the author has history and cannot be deleted, idle is never picked as a reviewer
+	_, err := c.SetIsActive(ctx, "idle", false)
+	require.NoError(t, err)
+	_, err = c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+	require.NoError(t, err)
+
+	res, err := c.UpsertTeamMembers(ctx, client.UpsertMembersRequest{
+		TeamName: "backend",
+		Members: []client.TeamMember{
+			{UserID: "new", Username: "New", IsActive: true},
+			{UserID: "f1", Username: "name-f1", IsActive: true},
+			{UserID: "r1", Username: "Renamed", IsActive: true},
+			{UserID: "r2", Username: "name-r2", IsActive: true},
+		},
+		Remove: []string{"idle", "author", "f2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []client.MemberResult{
+		{UserID: "new", Result: "added"},
+		{UserID: "f1", Result: "moved", FromTeam: "frontend"},
+		{UserID: "r1", Result: "updated"},
+		{UserID: "r2", Result: "unchanged"},
+		{UserID: "idle", Result: "removed"},
+		{UserID: "author", Result: "in_use"},
+		{UserID: "f2", Result: "not_found"},
+	}, res.Results)
+
+	team, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, res.Team.Members, team.Members)
+	ids := make([]string, len(team.Members))
+	for i, m := range team.Members {
+		ids[i] = m.UserID
+	}
+	assert.ElementsMatch(t, []string{"author", "r1", "r2", "new", "f1"}, ids)
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.UpsertTeamMembers(ctx, client.UpsertMembersRequest{TeamName: "ghost", Remove: []string{"r1"}})
+		requireCode(t, client.ErrNotFound, err)
+	})
+
+	t.Run("INVALID_INPUT rolls back", func(t *testing.T) {
+		_, err := c.UpsertTeamMembers(ctx, client.UpsertMembersRequest{
+			TeamName: "backend",
+			Members:  []client.TeamMember{{UserID: "other", Username: "Other", IsActive: true}},
+			Remove:   []string{"other"},
+		})
+		requireCode(t, client.ErrInvalidInput, err)
+
+		team, err := c.GetTeam(ctx, "backend")
+		require.NoError(t, err)
+		assert.Len(t, team.Members, 5)
+	})
+}
+
+func TestIntegration_PreviewAssignment(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2", "r3")
+	_, err := c.SetIsActive(ctx, "r3", false)
+	require.NoError(t, err)
+
+	preview, err := c.PreviewAssignment(ctx, "author")
+	require.NoError(t, err)
+	assert.Equal(t, "backend", preview.TeamName)
+	assert.Equal(t, 2, preview.ReviewersCount)
+	assert.ElementsMatch(t, []string{"r1", "r2"}, preview.Candidates)
+	assert.ElementsMatch(t, []client.Exclusion{
+		{UserID: "author", TeamName: "backend", Reason: "author"},
+		{UserID: "r3", TeamName: "backend", Reason: "inactive"},
+	}, preview.Excluded)
+
+	// the preview writes nothing, the PR picks from the same pool
+	pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, preview.Candidates, pr.AssignedReviewers)
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.PreviewAssignment(ctx, "ghost")
+		requireCode(t, client.ErrNotFound, err)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.PreviewAssignment(ctx, "")
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+}
+
+func TestIntegration_Absences(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2", "r3", "r4")
+
+	now := time.Now()
+	away, err := c.AddAbsence(ctx, "r3", now.Add(-time.Hour), now.Add(24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "r3", away.UserID)
+	assert.False(t, away.ReviewsReleased)
+	_, err = c.AddAbsence(ctx, "r4", now.Add(24*time.Hour), now.Add(48*time.Hour))
+	require.NoError(t, err)
+
+	t.Run("list", func(t *testing.T) {
+		absences, err := c.ListAbsences(ctx, "r3")
+		require.NoError(t, err)
+		require.Len(t, absences, 1)
+		assert.Equal(t, away.AbsenceID, absences[0].AbsenceID)
+		assert.True(t, away.StartsAt.Equal(absences[0].StartsAt))
+
+		absences, err = c.ListAbsences(ctx, "r1")
+		require.NoError(t, err)
+		assert.Empty(t, absences)
+	})
+
+	t.Run("absent users are not candidates", func(t *testing.T) {
+		// r4 is away only from tomorrow
+		preview, err := c.PreviewAssignment(ctx, "author")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"r1", "r2", "r4"}, preview.Candidates)
+		assert.ElementsMatch(t, []client.Exclusion{
+			{UserID: "author", TeamName: "backend", Reason: "author"},
+			{UserID: "r3", TeamName: "backend", Reason: "absent"},
+		}, preview.Excluded)
+
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+		require.NoError(t, err)
+		assert.Len(t, pr.AssignedReviewers, 2)
+		assert.NotContains(t, pr.AssignedReviewers, "r3")
+	})
+
+	t.Run("reassign skips absent users until the absence is deleted", func(t *testing.T) {
+		addTeam(t, c, "small", "s-author", "s1", "s2", "s3")
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-small", PullRequestName: "Tiny", AuthorID: "s-author"})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 2)
+
+		var spare string
+		for _, id := range []string{"s1", "s2", "s3"} {
+			if !slices.Contains(pr.AssignedReviewers, id) {
+				spare = id
+			}
+		}
+		absence, err := c.AddAbsence(ctx, spare, now.Add(-time.Hour), now.Add(time.Hour))
+		require.NoError(t, err)
+
+		_, err = c.ReassignReviewer(ctx, "pr-small", pr.AssignedReviewers[0])
+		requireCode(t, client.ErrNoCandidate, err)
+
+		deleted, err := c.DeleteAbsence(ctx, absence.AbsenceID)
+		require.NoError(t, err)
+		assert.Equal(t, absence.AbsenceID, deleted.AbsenceID)
+
+		res, err := c.ReassignReviewer(ctx, "pr-small", pr.AssignedReviewers[0])
+		require.NoError(t, err)
+		assert.Equal(t, spare, res.ReplacedBy)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.AddAbsence(ctx, "r1", now.Add(time.Hour), now)
+		requireCode(t, client.ErrInvalidInput, err)
+
+		_, err = c.AddAbsence(ctx, "r1", now.Add(-2*time.Hour), now.Add(-time.Hour))
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.AddAbsence(ctx, "ghost", now, now.Add(time.Hour))
+		requireCode(t, client.ErrNotFound, err)
+
+		_, err = c.ListAbsences(ctx, "ghost")
+		requireCode(t, client.ErrNotFound, err)
+
+		_, err = c.DeleteAbsence(ctx, "abs-missing")
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_ReviewCapacity(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2", "r3")
+
+	zero, one := int32(0), int32(1)
+	load, err := c.SetMaxOpenReviews(ctx, "r3", &zero)
+	require.NoError(t, err)
+	assert.Equal(t, client.ReviewLoad{UserID: "r3", MaxOpenReviews: &zero}, *load)
+
+	t.Run("users at capacity are not candidates", func(t *testing.T) {
+		preview, err := c.PreviewAssignment(ctx, "author")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"r1", "r2"}, preview.Candidates)
+		assert.ElementsMatch(t, []client.Exclusion{
+			{UserID: "author", TeamName: "backend", Reason: "author"},
+			{UserID: "r3", TeamName: "backend", Reason: "over_capacity"},
+		}, preview.Excluded)
+
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"r1", "r2"}, pr.AssignedReviewers)
+	})
+
+	t.Run("reassign explains capacity in NO_CANDIDATE", func(t *testing.T) {
+		_, err := c.ReassignReviewer(ctx, "pr-1", "r1")
+		requireCode(t, client.ErrNoCandidate, err)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Contains(t, apiErr.Details, client.FieldError{Field: "candidates", Reason: "r3 is at capacity: 0 of 0 open reviews"})
+
+		load, err := c.SetMaxOpenReviews(ctx, "r3", &one)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), load.OpenReviews)
+
+		res, err := c.ReassignReviewer(ctx, "pr-1", "r1")
+		require.NoError(t, err)
+		assert.Equal(t, "r3", res.ReplacedBy)
+	})
+
+	t.Run("limit counts open reviews only", func(t *testing.T) {
+		load, err := c.SetMaxOpenReviews(ctx, "r2", nil)
+		require.NoError(t, err)
+		assert.Nil(t, load.MaxOpenReviews)
+		assert.Equal(t, int64(1), load.OpenReviews)
+
+		_, err = c.MergePR(ctx, "pr-1")
+		require.NoError(t, err)
+		load, err = c.SetMaxOpenReviews(ctx, "r3", &one)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), load.OpenReviews)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		negative := int32(-1)
+		_, err := c.SetMaxOpenReviews(ctx, "r1", &negative)
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.SetMaxOpenReviews(ctx, "", nil)
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.SetMaxOpenReviews(ctx, "ghost", &one)
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_SeniorRule(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "s1", "r1", "r2")
+	require.NoError(t, c.SetUserLevel(ctx, "s1", client.LevelSenior))
+
+	rules, err := c.GetTeamRules(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, client.TeamRules{TeamName: "backend", PairRules: []client.PairRule{}}, *rules)
+	rules, err = c.SetTeamRequireSenior(ctx, "backend", true)
+	require.NoError(t, err)
+	assert.True(t, rules.RequireSenior)
+
+	t.Run("a senior is always picked", func(t *testing.T) {
+		preview, err := c.PreviewAssignment(ctx, "author")
+		require.NoError(t, err)
+		assert.True(t, preview.RequireSenior)
+		assert.Equal(t, []string{"s1"}, preview.SeniorCandidates)
+
+		for i := range 5 {
+			pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: fmt.Sprintf("pr-%d", i), PullRequestName: "Add search", AuthorID: "author"})
+			require.NoError(t, err)
+			assert.Len(t, pr.AssignedReviewers, 2)
+			assert.Contains(t, pr.AssignedReviewers, "s1")
+			assert.False(t, pr.MissingSenior)
+		}
+	})
+
+	t.Run("the only senior is replaced by a senior", func(t *testing.T) {
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-reassign", PullRequestName: "Fix search", AuthorID: "author"})
+		require.NoError(t, err)
+		free := "r1"
+		if slices.Contains(pr.AssignedReviewers, free) {
+			free = "r2"
+		}
+
+		_, err = c.ReassignReviewer(ctx, "pr-reassign", "s1")
+		requireCode(t, client.ErrNoCandidate, err)
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Contains(t, apiErr.Details, client.FieldError{Field: "candidates", Reason: "no senior candidate can replace the only senior reviewer"})
+
+		require.NoError(t, c.SetUserLevel(ctx, free, client.LevelMaintainer))
+		res, err := c.ReassignReviewer(ctx, "pr-reassign", "s1")
+		require.NoError(t, err)
+		assert.Equal(t, free, res.ReplacedBy)
+		assert.False(t, res.PR.MissingSenior)
+		require.NoError(t, c.SetUserLevel(ctx, free, client.LevelRegular))
+	})
+
+	t.Run("PRs without an available senior are flagged", func(t *testing.T) {
+		addTeam(t, c, "mobile", "m-author", "m1", "m2")
+		_, err := c.SetTeamRequireSenior(ctx, "mobile", true)
+		require.NoError(t, err)
+
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-mobile", PullRequestName: "Add login", AuthorID: "m-author"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"m1", "m2"}, pr.AssignedReviewers)
+		assert.True(t, pr.MissingSenior)
+
+		// a senior of a fallback team takes the place of a team mate
+		addTeam(t, c, "platform", "p1")
+		require.NoError(t, c.SetUserLevel(ctx, "p1", client.LevelSenior))
+		_, err = c.SetTeamFallbacks(ctx, "mobile", "platform")
+		require.NoError(t, err)
+		pr, err = c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-mobile-2", PullRequestName: "Fix login", AuthorID: "m-author"})
+		require.NoError(t, err)
+		assert.Len(t, pr.AssignedReviewers, 2)
+		assert.Contains(t, pr.AssignedReviewers, "p1")
+		assert.Equal(t, []string{"p1"}, pr.FallbackReviewers)
+		assert.False(t, pr.MissingSenior)
+	})
+
+	t.Run("the flag is kept with the PR", func(t *testing.T) {
+		addTeam(t, c, "ios", "i-author", "i1", "i2")
+		_, err := c.SetTeamRequireSenior(ctx, "ios", true)
+		require.NoError(t, err)
+		_, err = c.SetRepositoryTeam(ctx, "ios-app", "ios")
+		require.NoError(t, err)
+
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-ios", PullRequestName: "Add push", AuthorID: "i-author", Repository: "ios-app"})
+		require.NoError(t, err)
+		assert.True(t, pr.MissingSenior)
+
+		reviews, err := c.GetUserReviews(ctx, "i1")
+		require.NoError(t, err)
+		require.Len(t, reviews.PullRequests, 1)
+		assert.True(t, reviews.PullRequests[0].MissingSenior)
+
+		list, err := c.ListPRs(ctx, "ios-app", "")
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.True(t, list[0].MissingSenior)
+
+		merged, err := c.MergePRInRepository(ctx, "pr-ios", "ios-app")
+		require.NoError(t, err)
+		assert.True(t, merged.MissingSenior)
+
+		// PRs that got a senior are not flagged anywhere
+		list, err = c.ListPRs(ctx, "", "")
+		require.NoError(t, err)
+		for _, p := range list {
+			assert.Equal(t, p.PullRequestID == "pr-mobile" || p.PullRequestID == "pr-ios", p.MissingSenior, "missing senior on %s", p.PullRequestID)
+		}
+	})
+
+	t.Run("a leaving senior hands the review to a senior", func(t *testing.T) {
+		addTeam(t, c, "web", "w-author", "ws", "w1", "w2", "w3")
+		require.NoError(t, c.SetUserLevel(ctx, "ws", client.LevelSenior))
+		_, err := c.SetTeamRequireSenior(ctx, "web", true)
+		require.NoError(t, err)
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-web", PullRequestName: "Add page", AuthorID: "w-author"})
+		require.NoError(t, err)
+		require.Contains(t, pr.AssignedReviewers, "ws")
+
+		var free []string
+		for _, id := range []string{"w1", "w2", "w3"} {
+			if !slices.Contains(pr.AssignedReviewers, id) {
+				free = append(free, id)
+			}
+		}
+		require.NoError(t, c.SetUserLevel(ctx, free[0], client.LevelSenior))
+
+		updated, err := c.DeactivateUsers(ctx, []string{"ws"})
+		require.NoError(t, err)
+		require.Len(t, updated, 1)
+		assert.Contains(t, updated[0].AssignedReviewers, free[0])
+		assert.False(t, updated[0].MissingSenior)
+
+		updated, err = c.DeactivateUsers(ctx, []string{free[0]})
+		require.NoError(t, err)
+		require.Len(t, updated, 1)
+		assert.True(t, updated[0].MissingSenior)
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		err := c.SetUserLevel(ctx, "r1", "boss")
+		requireCode(t, client.ErrInvalidInput, err)
+		err = c.SetUserLevel(ctx, "", client.LevelSenior)
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.GetTeamRules(ctx, "")
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		err := c.SetUserLevel(ctx, "ghost", client.LevelSenior)
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.GetTeamRules(ctx, "ghost")
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.SetTeamRequireSenior(ctx, "ghost", true)
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_PairRules(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2", "r3")
+
+	rules, err := c.SetTeamPairRule(ctx, "backend", "r1", "author", client.PairNever)
+	require.NoError(t, err)
+	rules, err = c.SetTeamPairRule(ctx, "backend", "r2", "author", client.PairPrefer)
+	require.NoError(t, err)
+	assert.Equal(t, []client.PairRule{
+		{ReviewerID: "r1", AuthorID: "author", Kind: client.PairNever},
+		{ReviewerID: "r2", AuthorID: "author", Kind: client.PairPrefer},
+	}, rules.PairRules)
+
+	t.Run("never and prefer rules steer the selection", func(t *testing.T) {
+		preview, err := c.PreviewAssignment(ctx, "author")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"r2", "r3"}, preview.Candidates)
+		assert.Contains(t, preview.Excluded, client.Exclusion{UserID: "r1", TeamName: "backend", Reason: "pair_rule"})
+		assert.Equal(t, []string{"r2"}, preview.PreferredCandidates)
+
+		for i := range 5 {
+			pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: fmt.Sprintf("pr-%d", i), PullRequestName: "Add search", AuthorID: "author"})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"r2", "r3"}, pr.AssignedReviewers)
+		}
+
+		// the rules follow the author, not the reviewer's own PRs
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-r3", PullRequestName: "Fix search", AuthorID: "r3"})
+		require.NoError(t, err)
+		assert.Len(t, pr.AssignedReviewers, 2)
+	})
+
+	t.Run("reassignment skips never pairs", func(t *testing.T) {
+		_, err := c.ReassignReviewer(ctx, "pr-0", "r3")
+		requireCode(t, client.ErrNoCandidate, err)
+
+		_, err = c.UpsertTeamMembers(ctx, client.UpsertMembersRequest{
+			TeamName: "backend",
+			Members:  []client.TeamMember{{UserID: "r4", Username: "name-r4", IsActive: true}},
+		})
+		require.NoError(t, err)
+		res, err := c.ReassignReviewer(ctx, "pr-0", "r3")
+		require.NoError(t, err)
+		assert.Equal(t, "r4", res.ReplacedBy)
+	})
+
+	t.Run("deactivation skips never pairs", func(t *testing.T) {
+		updated, err := c.DeactivateUsers(ctx, []string{"r4"})
+		require.NoError(t, err)
+		require.Len(t, updated, 1)
+		assert.ElementsMatch(t, []string{"r2", "r3"}, updated[0].AssignedReviewers)
+	})
+
+	t.Run("a deleted rule no longer applies", func(t *testing.T) {
+		rules, err := c.DeleteTeamPairRule(ctx, "backend", "r1", "author")
+		require.NoError(t, err)
+		assert.Equal(t, []client.PairRule{{ReviewerID: "r2", AuthorID: "author", Kind: client.PairPrefer}}, rules.PairRules)
+
+		preview, err := c.PreviewAssignment(ctx, "author")
+		require.NoError(t, err)
+		assert.Contains(t, preview.Candidates, "r1")
+		assert.NotContains(t, preview.Excluded, client.Exclusion{UserID: "r1", TeamName: "backend", Reason: "pair_rule"})
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.SetTeamPairRule(ctx, "backend", "r1", "author", "always")
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.SetTeamPairRule(ctx, "backend", "r1", "r1", client.PairNever)
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.SetTeamPairRule(ctx, "", "r1", "author", client.PairNever)
+		requireCode(t, client.ErrInvalidInput, err)
+
+		addTeam(t, c, "mobile", "m1")
+		_, err = c.SetTeamPairRule(ctx, "backend", "r1", "m1", client.PairNever)
+		requireCode(t, client.ErrInvalidInput, err)
+		_, err = c.DeleteTeamPairRule(ctx, "backend", "", "author")
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+
+	t.Run("NOT_FOUND", func(t *testing.T) {
+		_, err := c.SetTeamPairRule(ctx, "ghost", "r1", "author", client.PairNever)
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.SetTeamPairRule(ctx, "backend", "ghost", "author", client.PairNever)
+		requireCode(t, client.ErrNotFound, err)
+		_, err = c.DeleteTeamPairRule(ctx, "backend", "r3", "author")
+		requireCode(t, client.ErrNotFound, err)
+	})
+}
+
+func TestIntegration_DeactivateUsersDryRun(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2")
+
+	pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"r1", "r2"}, pr.AssignedReviewers)
+
+	plan, err := c.PlanDeactivation(ctx, []string{"r1"})
+	require.NoError(t, err)
+	assert.True(t, plan.DryRun)
+	// nobody is left to take the review over
+	assert.Equal(t, []client.Reassignment{{PullRequestID: "pr-1", OldReviewerID: "r1"}}, plan.Reassignments)
+	require.Len(t, plan.UpdatedPRs, 1)
+	assert.Equal(t, []string{"r2"}, plan.UpdatedPRs[0].AssignedReviewers)
+
+	// nothing was committed
+	reviews, err := c.GetUserReviews(ctx, "r1")
+	require.NoError(t, err)
+	assert.Len(t, reviews.PullRequests, 1)
+	team, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	for _, m := range team.Members {
+		assert.True(t, m.IsActive, m.UserID)
+	}
+}
+
+func TestIntegration_DeactivateUsers(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2", "r3")
+
+	pr, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "author"})
+	require.NoError(t, err)
+	require.Len(t, pr.AssignedReviewers, 2)
+
+	leaving := pr.AssignedReviewers[0]
+	updated, err := c.DeactivateUsers(ctx, []string{leaving})
+	require.NoError(t, err)
+	require.Len(t, updated, 1)
+	assert.Equal(t, "pr-1", updated[0].PullRequestID)
+	assert.NotContains(t, updated[0].AssignedReviewers, leaving)
+	assert.NotContains(t, updated[0].AssignedReviewers, "author")
+	assert.Len(t, updated[0].AssignedReviewers, 2)
+
+	team, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	for _, m := range team.Members {
+		assert.Equal(t, m.UserID != leaving, m.IsActive, m.UserID)
+	}
+
+	t.Run("merged PRs are skipped", func(t *testing.T) {
+		_, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-2", PullRequestName: "Fix", AuthorID: "author"})
+		require.NoError(t, err)
+		merged, err := c.MergePR(ctx, "pr-2")
+		require.NoError(t, err)
+
+		reviewer := merged.AssignedReviewers[0]
+		plan, err := c.PlanDeactivation(ctx, []string{reviewer})
+		require.NoError(t, err)
+		require.Len(t, plan.Users, 1)
+		assert.Equal(t, reviewer, plan.Users[0].UserID)
+		assert.Equal(t, []string{"pr-2"}, plan.Users[0].Skipped)
+		assert.Equal(t, []string{"pr-1"}, append(plan.Users[0].Reassigned, plan.Users[0].LostReviewer...))
+		for _, p := range plan.UpdatedPRs {
+			assert.NotEqual(t, "pr-2", p.PullRequestID)
+		}
+	})
+
+	t.Run("NOT_FOUND rolls back", func(t *testing.T) {
+		other := updated[0].AssignedReviewers[0]
+		_, err := c.DeactivateUsers(ctx, []string{other, "ghost"})
+		requireCode(t, client.ErrNotFound, err)
+
+		team, err := c.GetTeam(ctx, "backend")
+		require.NoError(t, err)
+		for _, m := range team.Members {
+			if m.UserID == other {
+				assert.True(t, m.IsActive, "deactivation of %s was not rolled back", other)
+			}
+		}
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		_, err := c.DeactivateUsers(ctx, []string{})
+		requireCode(t, client.ErrInvalidInput, err)
+	})
+}
+
+func TestIntegration_Stats(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2")
+
+	_, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "One", AuthorID: "author"})
+	require.NoError(t, err)
+	_, err = c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-2", PullRequestName: "Two", AuthorID: "author"})
+	require.NoError(t, err)
+	_, err = c.MergePR(ctx, "pr-2")
+	require.NoError(t, err)
+
+	stats, err := c.Stats(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, stats.TotalActiveUsers)
+	assert.ElementsMatch(t, []client.ReviewerStat{
+		{ReviewerID: "r1", AssignmentCount: 2},
+		{ReviewerID: "r2", AssignmentCount: 2},
+	}, stats.TopReviewers)
+	assert.ElementsMatch(t, []client.PRStatusStat{
+		{Status: client.StatusOpen, Count: 1},
+		{Status: client.StatusMerged, Count: 1},
+	}, stats.PRStatusDistribution)
+}
+
+func TestIntegration_GraphQL(t *testing.T) {
+	ctx := context.Background()
+	_, c := newTestServer(t)
+	addTeam(t, c, "backend", "author", "r1", "r2")
+
+	_, err := c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "One", AuthorID: "author"})
+	require.NoError(t, err)
+
+	res, err := c.GraphQL(ctx, client.GraphQLRequest{
+		Query: `{ pullRequest(id: "pr-1") { name author { id team { name } } reviewers { id } } }`,
+	})
+	require.NoError(t, err)
+	require.Empty(t, res.Errors)
+
+	var data struct {
+		PullRequest struct {
+			Name   string
+			Author struct {
+				ID   string
+				Team struct{ Name string }
+			}
+			Reviewers []struct{ ID string }
+		}
+	}
+	require.NoError(t, json.Unmarshal(res.Data, &data))
+	assert.Equal(t, "One", data.PullRequest.Name)
+	assert.Equal(t, "author", data.PullRequest.Author.ID)
+	assert.Equal(t, "backend", data.PullRequest.Author.Team.Name)
+	assert.Len(t, data.PullRequest.Reviewers, 2)
+
+	t.Run("unknown ids resolve to null", func(t *testing.T) {
+		res, err := c.GraphQL(ctx, client.GraphQLRequest{Query: `{ user(id: "ghost") { id } }`})
+		require.NoError(t, err)
+		assert.Empty(t, res.Errors)
+		assert.JSONEq(t, `{"user": null}`, string(res.Data))
+	})
+
+	t.Run("INVALID_INPUT", func(t *testing.T) {
+		res, err := c.GraphQL(ctx, client.GraphQLRequest{Query: `{ nope }`})
+		require.NoError(t, err)
+		assert.NotEmpty(t, res.Errors)
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// drive runs iteration on opts.concurrency workers until opts.duration passes
+// or ctx is cancelled. With a rate set, iterations are started by a ticker and
+// a tick that finds every worker busy is dropped (and counted) rather than
+// queued, so a slow service does not get a burst once it recovers.
+func drive(ctx context.Context, opts options, iteration func(ctx context.Context, n int64)) (iterations, dropped int64) {
+	ctx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+
+	var (
+		wg    sync.WaitGroup
+		count atomic.Int64
+		ticks chan struct{}
+	)
+	if opts.rps > 0 {
+		ticks = make(chan struct{})
+	}
+
+	for range opts.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if ticks != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticks:
+					}
+				} else if ctx.Err() != nil {
+					return
+				}
+
+				// an iteration in flight is finished, the deadline only stops new ones
+				iteration(context.WithoutCancel(ctx), count.Add(1))
+			}
+		}()
+	}
+
+	if ticks != nil {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.rps))
+		defer ticker.Stop()
+
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-ticker.C:
+				select {
+				case ticks <- struct{}{}:
+				default:
+					dropped++
+				}
+			}
+		}
+	}
+
+	wg.Wait()
+
+	return count.Load(), dropped
+}
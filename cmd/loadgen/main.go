@@ -0,0 +1,146 @@
+// Command loadgen replays the load test scenario against a running service
+// and checks the latency and error rate thresholds.
+//
+// Every iteration creates a team of four, creates a PR by the first member,
+// reassigns one of its reviewers and merges it. Iterations are started at
+// -rps per second by -c workers for -d, then a per-endpoint report is printed:
+//
+//	loadgen -url http://localhost:8080 -rps 20 -c 50 -d 1m
+//
+// The exit code is 1 when a threshold is exceeded and 2 on invalid flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// errThresholds is returned when the run finished but did not meet the thresholds.
+var errThresholds = errors.New("thresholds exceeded")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errThresholds):
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(1)
+	default:
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(2)
+	}
+}
+
+// options are the command-line flags.
+type options struct {
+	url         string
+	token       string
+	rps         float64
+	concurrency int
+	duration    time.Duration
+	timeout     time.Duration
+	thresholds  thresholds
+}
+
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	opts := options{}
+	fs.StringVar(&opts.url, "url", envOr("LOADGEN_URL", "http://localhost:8080"), "service base URL")
+	fs.StringVar(&opts.token, "token", os.Getenv("LOADGEN_TOKEN"), "API token, sent in X-API-Key")
+	fs.Float64Var(&opts.rps, "rps", 5, "scenario iterations started per second (4 requests each), 0 = as fast as the workers go")
+	fs.IntVar(&opts.concurrency, "c", 10, "concurrent workers")
+	fs.DurationVar(&opts.duration, "d", 30*time.Second, "test duration")
+	fs.DurationVar(&opts.timeout, "timeout", 5*time.Second, "timeout of a single request")
+	fs.DurationVar(&opts.thresholds.p95, "max-p95", 300*time.Millisecond, "p95 latency threshold per endpoint, 0 = off")
+	fs.DurationVar(&opts.thresholds.p99, "max-p99", 0, "p99 latency threshold per endpoint, 0 = off")
+	fs.Float64Var(&opts.thresholds.errorRate, "max-error-rate", 0.001, "error rate threshold per endpoint")
+
+	if err := fs.Parse(args); err != nil {
+		return options{}, err
+	}
+	if fs.NArg() > 0 {
+		return options{}, fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	var errs []error
+	if opts.rps < 0 {
+		errs = append(errs, errors.New("-rps must not be negative"))
+	}
+	if opts.concurrency < 1 {
+		errs = append(errs, errors.New("-c must be at least 1"))
+	}
+	if opts.duration <= 0 {
+		errs = append(errs, errors.New("-d must be positive"))
+	}
+	if opts.timeout <= 0 {
+		errs = append(errs, errors.New("-timeout must be positive"))
+	}
+	if opts.thresholds.errorRate < 0 || opts.thresholds.errorRate > 1 {
+		errs = append(errs, errors.New("-max-error-rate must be between 0 and 1"))
+	}
+
+	return opts, errors.Join(errs...)
+}
+
+// run executes the load test and writes the report to stdout.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	opts, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+
+	rec := newRecorder()
+	sc, err := newScenario(opts, rec)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stderr, "loadgen: %s for %s, %d workers, %s\n", opts.url, opts.duration, opts.concurrency, rateString(opts.rps))
+
+	started := time.Now()
+	iterations, dropped := drive(ctx, opts, sc.iteration)
+	elapsed := time.Since(started)
+
+	rep := rec.report()
+	rep.write(stdout, iterations, dropped, elapsed)
+
+	if failures := rep.check(opts.thresholds); len(failures) > 0 {
+		for _, f := range failures {
+			fmt.Fprintln(stdout, "FAIL", f)
+		}
+		return errThresholds
+	}
+	fmt.Fprintln(stdout, "PASS")
+
+	return nil
+}
+
+func rateString(rps float64) string {
+	if rps == 0 {
+		return "unpaced"
+	}
+
+	return fmt.Sprintf("%g iterations/s", rps)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return fallback
+}
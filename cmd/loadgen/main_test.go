@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newService fakes the four endpoints of the scenario, reassign sleeps for reassignDelay
+// and answers with reassignStatus.
+func newService(t *testing.T, reassignDelay time.Duration, reassignStatus int) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /team/add", func(w http.ResponseWriter, r *http.Request) {
+		var team map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&team))
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"team": team})
+	})
+	mux.HandleFunc("POST /pullRequest/create", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"pr":{"pull_request_id":"` + req["pull_request_id"] + `","status":"OPEN","assigned_reviewers":["u2","u3"]}}`))
+	})
+	mux.HandleFunc("POST /pullRequest/reassign", func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(reassignDelay)
+		w.WriteHeader(reassignStatus)
+		if reassignStatus != http.StatusOK {
+			_, _ = w.Write([]byte(`{"error":{"code":"NO_CANDIDATE","message":"no active replacement candidate in team"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"pr":{"pull_request_id":"pr","status":"OPEN","assigned_reviewers":["u3","u4"]},"replaced_by":"u4"}`))
+	})
+	mux.HandleFunc("POST /pullRequest/merge", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"pr":{"pull_request_id":"pr","status":"MERGED","assigned_reviewers":["u3","u4"]}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func runLoadgen(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestRun_Pass(t *testing.T) {
+	srv := newService(t, 0, http.StatusOK)
+
+	out, err := runLoadgen(t, "-url", srv.URL, "-rps", "50", "-c", "4", "-d", "300ms")
+	require.NoError(t, err, out)
+
+	for _, endpoint := range []string{"POST /team/add", "POST /pullRequest/create", "POST /pullRequest/reassign", "POST /pullRequest/merge"} {
+		assert.Contains(t, out, endpoint)
+	}
+	assert.Contains(t, out, "PASS")
+}
+
+func TestRun_LatencyThreshold(t *testing.T) {
+	srv := newService(t, 30*time.Millisecond, http.StatusOK)
+
+	out, err := runLoadgen(t, "-url", srv.URL, "-rps", "0", "-c", "2", "-d", "200ms", "-max-p95", "20ms")
+	require.ErrorIs(t, err, errThresholds)
+	assert.Contains(t, out, "FAIL POST /pullRequest/reassign: p95")
+	assert.NotContains(t, out, "FAIL POST /team/add")
+}
+
+func TestRun_ErrorRateThreshold(t *testing.T) {
+	srv := newService(t, 0, http.StatusConflict)
+
+	out, err := runLoadgen(t, "-url", srv.URL, "-rps", "50", "-c", "2", "-d", "200ms")
+	require.ErrorIs(t, err, errThresholds)
+	assert.Contains(t, out, "FAIL POST /pullRequest/reassign: error rate 100.00%")
+	// the iteration stops at the failed step
+	assert.NotContains(t, out, "POST /pullRequest/merge")
+}
+
+func TestRun_InvalidFlags(t *testing.T) {
+	_, err := runLoadgen(t, "-c", "0", "-d", "-1s")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, errThresholds)
+	assert.ErrorContains(t, err, "-c must be at least 1")
+	assert.ErrorContains(t, err, "-d must be positive")
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	assert.Equal(t, 95*time.Millisecond, percentile(sorted, 95))
+	assert.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	assert.Equal(t, 7*time.Millisecond, percentile([]time.Duration{7 * time.Millisecond}, 99))
+	assert.Zero(t, percentile(nil, 50))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// thresholds fail the run when any endpoint exceeds them, zero latencies are not checked.
+type thresholds struct {
+	p95       time.Duration
+	p99       time.Duration
+	errorRate float64
+}
+
+// recorder collects the latency of every request per endpoint.
+type recorder struct {
+	mu        sync.Mutex
+	endpoints map[string]*samples
+}
+
+type samples struct {
+	latencies []time.Duration
+	errors    int
+}
+
+func newRecorder() *recorder {
+	return &recorder{endpoints: make(map[string]*samples)}
+}
+
+func (r *recorder) add(endpoint string, latency time.Duration, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.endpoints[endpoint]
+	if s == nil {
+		s = &samples{}
+		r.endpoints[endpoint] = s
+	}
+	s.latencies = append(s.latencies, latency)
+	if !ok {
+		s.errors++
+	}
+}
+
+// endpointReport is the summary of one endpoint.
+type endpointReport struct {
+	endpoint      string
+	requests      int
+	errors        int
+	p50, p95, p99 time.Duration
+	max           time.Duration
+}
+
+func (e endpointReport) errorRate() float64 {
+	if e.requests == 0 {
+		return 0
+	}
+
+	return float64(e.errors) / float64(e.requests)
+}
+
+type report []endpointReport
+
+// report summarises the samples, endpoints are sorted by name.
+func (r *recorder) report() report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rep report
+	for endpoint, s := range r.endpoints {
+		sorted := slices.Clone(s.latencies)
+		slices.Sort(sorted)
+
+		rep = append(rep, endpointReport{
+			endpoint: endpoint,
+			requests: len(sorted),
+			errors:   s.errors,
+			p50:      percentile(sorted, 50),
+			p95:      percentile(sorted, 95),
+			p99:      percentile(sorted, 99),
+			max:      sorted[len(sorted)-1],
+		})
+	}
+	slices.SortFunc(rep, func(a, b endpointReport) int { return strings.Compare(a.endpoint, b.endpoint) })
+
+	return rep
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(float64(len(sorted))*p/100)) - 1
+	rank = max(0, min(rank, len(sorted)-1))
+
+	return sorted[rank]
+}
+
+func (rep report) write(w io.Writer, iterations, dropped int64, elapsed time.Duration) {
+	total := 0
+	for _, e := range rep {
+		total += e.requests
+	}
+
+	fmt.Fprintf(w, "iterations: %d, dropped: %d, requests: %d, elapsed: %s, throughput: %.1f req/s\n\n",
+		iterations, dropped, total, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tREQUESTS\tERRORS\tERROR RATE\tP50\tP95\tP99\tMAX")
+	for _, e := range rep {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f%%\t%s\t%s\t%s\t%s\n",
+			e.endpoint, e.requests, e.errors, e.errorRate()*100,
+			ms(e.p50), ms(e.p95), ms(e.p99), ms(e.max))
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+}
+
+// check returns a description of every exceeded threshold.
+func (rep report) check(t thresholds) []string {
+	if len(rep) == 0 {
+		return []string{"no requests were made"}
+	}
+
+	var failures []string
+	for _, e := range rep {
+		if t.p95 > 0 && e.p95 > t.p95 {
+			failures = append(failures, fmt.Sprintf("%s: p95 %s > %s", e.endpoint, ms(e.p95), ms(t.p95)))
+		}
+		if t.p99 > 0 && e.p99 > t.p99 {
+			failures = append(failures, fmt.Sprintf("%s: p99 %s > %s", e.endpoint, ms(e.p99), ms(t.p99)))
+		}
+		if e.errorRate() > t.errorRate {
+			failures = append(failures, fmt.Sprintf("%s: error rate %.2f%% > %.2f%%", e.endpoint, e.errorRate()*100, t.errorRate*100))
+		}
+	}
+
+	return failures
+}
+
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
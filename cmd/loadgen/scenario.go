@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/pkg/client"
+)
+
+// scenario is the load test flow: team create, PR create, reassign, merge.
+type scenario struct {
+	client *client.Client
+	runID  string
+}
+
+func newScenario(opts options, rec *recorder) (*scenario, error) {
+	httpClient := &http.Client{
+		Timeout: opts.timeout,
+		Transport: &recordingTransport{
+			next: &http.Transport{
+				MaxIdleConns:        opts.concurrency,
+				MaxIdleConnsPerHost: opts.concurrency,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			rec: rec,
+		},
+	}
+
+	clientOpts := []client.Option{
+		client.WithHTTPClient(httpClient),
+		client.WithUserAgent("loadgen"),
+		// every attempt is measured, a retry would hide the failed one
+		client.WithRetryPolicy(client.RetryPolicy{MaxAttempts: 1}),
+	}
+	if opts.token != "" {
+		clientOpts = append(clientOpts, client.WithToken(opts.token))
+	}
+
+	c, err := client.New(opts.url, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scenario{
+		client: c,
+		runID:  strconv.FormatInt(time.Now().UnixNano(), 36),
+	}, nil
+}
+
+// iteration runs the scenario once with IDs unique to this run and iteration.
+// It stops at the first failed step, the failure is already recorded.
+func (s *scenario) iteration(ctx context.Context, n int64) {
+	id := fmt.Sprintf("load_%s_%d", s.runID, n)
+
+	team := client.Team{TeamName: id}
+	for i := 1; i <= 4; i++ {
+		team.Members = append(team.Members, client.TeamMember{
+			UserID:   fmt.Sprintf("%s_u%d", id, i),
+			Username: fmt.Sprintf("User%d", i),
+			IsActive: true,
+		})
+	}
+	if _, err := s.client.CreateTeam(ctx, team); err != nil {
+		return
+	}
+
+	pr, err := s.client.CreatePR(ctx, client.CreatePRRequest{
+		PullRequestID:   "pr_" + id,
+		PullRequestName: "PR " + id,
+		AuthorID:        team.Members[0].UserID,
+	})
+	if err != nil {
+		return
+	}
+
+	// three team mates and two reviewers leave one candidate
+	if len(pr.AssignedReviewers) > 0 {
+		if _, err := s.client.ReassignReviewer(ctx, pr.PullRequestID, pr.AssignedReviewers[0]); err != nil {
+			return
+		}
+	}
+
+	_, _ = s.client.MergePR(ctx, pr.PullRequestID)
+}
+
+// recordingTransport measures every request until its body is read.
+// A transport error or a 4xx/5xx status counts as an error.
+type recordingTransport struct {
+	next http.RoundTripper
+	rec  *recorder
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.Method + " " + req.URL.Path
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.rec.add(endpoint, time.Since(start), false)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	t.rec.add(endpoint, time.Since(start), err == nil && resp.StatusCode < http.StatusBadRequest)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
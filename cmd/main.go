@@ -2,11 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"time"
 
-	"github.com/Joskmo/avito-trainee-assignment-api/internal/env"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/config"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/openapi"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/ratelimit"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/migrate"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 )
@@ -16,44 +24,127 @@ func main() {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	slog.SetDefault(logger)
 
-	// Loading config from env
+	// Loading config from file, env and flags
 	if err := godotenv.Load(); err != nil {
 		slog.Warn("the .env file wasn't read -> using default data", "warning", err)
 	}
-	cfg := config{
-		addr: ":8080",
-		db: dbConfig{
-			dsn: env.GetString(
-				"DATABASE_URL",
-				env.GetString("GOOSE_DBSTRING", "host=localhost user=trainee password=trainee_password dbname=trainee_db sslmode=disable"),
-			),
-		},
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
+	slog.SetDefault(cfg.Log.NewLogger(os.Stdout))
 
-	pool, err := pgxpool.New(ctx, cfg.db.dsn)
-	if err != nil {
-		slog.Error("failed to connect to the database", "error", err)
-		os.Exit(1)
+	if len(args) > 0 && args[0] == "config" {
+		if err := runConfig(cfg, args[1:]); err != nil {
+			slog.Error("config command failed", "error", err)
+			os.Exit(1)
+		}
+		return
 	}
-	defer pool.Close()
 
-	if err := pool.Ping(ctx); err != nil {
-		slog.Error("failed to ping the database", "error", err)
+	if err := run(cfg, args); err != nil {
+		slog.Error("exiting", "error", err)
 		os.Exit(1)
 	}
+}
+
+// run connects to the database and serves the API, or runs the migrate
+// command. Its deferred calls release the pools on every return.
+func run(cfg config.Config, args []string) error {
+	pool, err := connectDB(cfg.DB)
+	if err != nil {
+		return fmt.Errorf("connect to the database: %w", err)
+	}
+	// closing again after the graceful shutdown is harmless
+	defer pool.Close()
 
 	slog.Info("database connection pool ready")
 
+	// Migrations
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(context.Background(), pool, args[1:]); err != nil {
+			return fmt.Errorf("migrate command: %w", err)
+		}
+		return nil
+	}
+	if cfg.DB.MigrateOnStart {
+		m, err := migrate.New(pool)
+		if err == nil {
+			err = migrateUp(context.Background(), m)
+			_ = m.Close()
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	// Application
 	app := application{
 		config: cfg,
 		db:     pool,
 	}
-	if err := app.run(app.mount()); err != nil {
-		slog.Error("server failed to starts", "error", err)
-		os.Exit(1)
+	app.clients, err = ratelimit.NewClients(cfg.RateLimit.KeyHeader, cfg.RateLimit.APIKeys, cfg.HTTP.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("rate limit clients: %w", err)
 	}
+	if cfg.RateLimit.Enabled {
+		switch cfg.RateLimit.Backend {
+		case "postgres":
+			// the limiter gets its own pool, it must not wait for the connections it protects
+			limiterCfg := cfg.DB
+			limiterCfg.MaxConns, limiterCfg.MinConns = cfg.RateLimit.PostgresMaxConns, 0
+			limiterPool, err := connectDB(limiterCfg)
+			if err != nil {
+				return fmt.Errorf("connect the rate limiter to the database: %w", err)
+			}
+			defer limiterPool.Close()
+			app.limiterDB = limiterPool
+			app.limiter = ratelimit.NewPostgres(repo.New(limiterPool))
+		default:
+			app.limiter = ratelimit.NewMemory()
+		}
+		app.workers = append(app.workers, ratelimit.CleanupWorker(app.limiter, time.Minute, time.Minute*10))
+	}
+	if cfg.Absences.ReassignReviews {
+		teamsService := teams.NewService(repo.New(pool), pool)
+		app.workers = append(app.workers, teams.AbsenceWorker(teamsService, cfg.Absences.CheckInterval))
+	}
+	if cfg.OpenAPI.ValidateRequests || cfg.OpenAPI.ValidateResponses {
+		app.validator, err = openapi.NewValidator(cfg.OpenAPI.ValidateResponses)
+		if err != nil {
+			return fmt.Errorf("load the API spec: %w", err)
+		}
+	}
+
+	return app.run(app.mount())
+}
+
+// connectDB creates the connection pool and checks the database is reachable.
+func connectDB(cfg config.DBConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MinConns = cfg.MinConns
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/migrate"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "usage: main migrate up|down|status"
+
+// runMigrate executes the `migrate` subcommand.
+func runMigrate(ctx context.Context, pool *pgxpool.Pool, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	m, err := migrate.New(pool)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Close() }()
+
+	switch args[0] {
+	case "up":
+		return migrateUp(ctx, m)
+	case "down":
+		res, err := m.Down(ctx)
+		if err != nil {
+			return fmt.Errorf("rollback migration: %w", err)
+		}
+		slog.Info("migration rolled back", "result", res.String())
+		return nil
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("get migrations status: %w", err)
+		}
+		for _, st := range statuses {
+			appliedAt := "-"
+			if !st.AppliedAt.IsZero() {
+				appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-8s %-20s %s\n", st.State, appliedAt, st.Source.Path)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", args[0], migrateUsage)
+	}
+}
+
+// migrateUp applies all pending migrations.
+func migrateUp(ctx context.Context, m *migrate.Migrator) error {
+	results, err := m.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("apply migrations: %w", err)
+	}
+	for _, res := range results {
+		slog.Info("migration applied", "result", res.String())
+	}
+	slog.Info("database schema is up to date", "applied", len(results))
+
+	return nil
+}
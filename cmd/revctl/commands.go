@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/pkg/client"
+)
+
+type command struct {
+	client *client.Client
+	cfg    config
+	out    *printer
+	stdin  io.Reader
+}
+
+// errUsage is returned for unknown commands and wrong arguments.
+var errUsage = errors.New(`invalid usage, run "revctl help"`)
+
+func (c *command) dispatch(ctx context.Context, args []string) error {
+	name := args[0]
+	if len(args) > 1 && name != "ping" && name != "stats" && name != "graphql" {
+		name += " " + args[1]
+		args = args[2:]
+	} else {
+		args = args[1:]
+	}
+
+	switch name {
+	case "ping":
+		return c.ping(ctx)
+	case "team get":
+		return c.teamGet(ctx, args)
+	case "team add":
+		return c.teamAdd(ctx, args)
+	case "team deactivate":
+		return c.teamDeactivate(ctx, args)
+	case "user set-active":
+		return c.userSetActive(ctx, args)
+	case "user reviews":
+		return c.userReviews(ctx, args)
+	case "me reviews":
+		return c.meReviews(ctx, args)
+	case "pr create":
+		return c.prCreate(ctx, args)
+	case "pr preview":
+		return c.prPreview(ctx, args)
+	case "pr merge":
+		return c.prMerge(ctx, args)
+	case "pr reassign":
+		return c.prReassign(ctx, args)
+	case "stats":
+		return c.stats(ctx)
+	case "graphql":
+		return c.graphql(ctx, args)
+	default:
+		return fmt.Errorf("unknown command %q: %w", name, errUsage)
+	}
+}
+
+func exactArgs(args []string, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %d argument(s), got %d: %w", n, len(args), errUsage)
+	}
+
+	return nil
+}
+
+func (c *command) ping(ctx context.Context) error {
+	if err := c.client.Ping(ctx); err != nil {
+		return err
+	}
+
+	return c.out.print(map[string]string{"status": "ok"}, func(t *tabwriter.Writer) {
+		row(t, "ok")
+	})
+}
+
+func (c *command) teamGet(ctx context.Context, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+
+	team, err := c.client.GetTeam(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	return c.printTeam(team)
+}
+
+func (c *command) printTeam(team *client.Team) error {
+	return c.out.print(team, func(t *tabwriter.Writer) {
+		row(t, "TEAM", "USER", "USERNAME", "ACTIVE")
+		for _, m := range team.Members {
+			row(t, team.TeamName, m.UserID, m.Username, m.IsActive)
+		}
+	})
+}
+
+// memberFlag collects --member id:name[:inactive] values.
+type memberFlag []client.TeamMember
+
+func (m *memberFlag) String() string {
+	return fmt.Sprint(*m)
+}
+
+func (m *memberFlag) Set(s string) error {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("member must be id:name or id:name:inactive, got %q", s)
+	}
+	if len(parts) == 3 && parts[2] != "inactive" {
+		return fmt.Errorf("unknown member flag %q, expected inactive", parts[2])
+	}
+
+	*m = append(*m, client.TeamMember{UserID: parts[0], Username: parts[1], IsActive: len(parts) == 2})
+	return nil
+}
+
+func (c *command) teamAdd(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("team add", flag.ContinueOnError)
+	var members memberFlag
+	fs.Var(&members, "member", "team member as id:name[:inactive], repeatable")
+	name, err := parseWithName(fs, args)
+	if err != nil {
+		return err
+	}
+
+	team, err := c.client.CreateTeam(ctx, client.Team{TeamName: name, Members: members})
+	if err != nil {
+		return err
+	}
+
+	return c.printTeam(team)
+}
+
+// parseWithName parses flags around a single positional name,
+// so both "add name --flag" and "add --flag name" work.
+func parseWithName(fs *flag.FlagSet, args []string) (string, error) {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if name == "" && fs.NArg() == 1 {
+		name = fs.Arg(0)
+	} else if fs.NArg() != 0 || name == "" {
+		return "", fmt.Errorf("expected one name: %w", errUsage)
+	}
+
+	return name, nil
+}
+
+func (c *command) teamDeactivate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("team deactivate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "only show the reassignment plan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("expected user ids: %w", errUsage)
+	}
+
+	if !*dryRun {
+		prs, err := c.client.DeactivateUsers(ctx, fs.Args())
+		if err != nil {
+			return err
+		}
+
+		return c.printPRs(prs)
+	}
+
+	plan, err := c.client.PlanDeactivation(ctx, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(plan, func(t *tabwriter.Writer) {
+		row(t, "PR", "REVIEWER", "REPLACED BY")
+		for _, r := range plan.Reassignments {
+			by := r.NewReviewerID
+			if by == "" {
+				by = "-"
+			}
+			row(t, r.PullRequestID, r.OldReviewerID, by)
+		}
+	})
+}
+
+func (c *command) userSetActive(ctx context.Context, args []string) error {
+	if err := exactArgs(args, 2); err != nil {
+		return err
+	}
+	active, err := strconv.ParseBool(args[1])
+	if err != nil {
+		return fmt.Errorf("activity must be true or false, got %q", args[1])
+	}
+
+	user, err := c.client.SetIsActive(ctx, args[0], active)
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(user, func(t *tabwriter.Writer) {
+		row(t, "USER", "USERNAME", "TEAM", "ACTIVE")
+		row(t, user.UserID, user.Username, user.TeamName, user.IsActive)
+	})
+}
+
+func (c *command) userReviews(ctx context.Context, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+
+	return c.reviews(ctx, args[0])
+}
+
+func (c *command) meReviews(ctx context.Context, args []string) error {
+	if err := exactArgs(args, 0); err != nil {
+		return err
+	}
+	if c.cfg.User == "" {
+		return errors.New(`your user id is not set, add "user" to the config file or pass --user`)
+	}
+
+	return c.reviews(ctx, c.cfg.User)
+}
+
+func (c *command) reviews(ctx context.Context, userID string) error {
+	reviews, err := c.client.GetUserReviews(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(reviews, func(t *tabwriter.Writer) {
+		row(t, "PR", "NAME", "AUTHOR", "STATUS")
+		for _, pr := range reviews.PullRequests {
+			row(t, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status)
+		}
+	})
+}
+
+func (c *command) prCreate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr create", flag.ContinueOnError)
+	req := client.CreatePRRequest{AuthorID: c.cfg.User}
+	fs.StringVar(&req.PullRequestID, "id", "", "PR id")
+	fs.StringVar(&req.PullRequestName, "name", "", "PR name")
+	fs.StringVar(&req.AuthorID, "author", req.AuthorID, "author user id (default: your user id)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || req.PullRequestID == "" || req.PullRequestName == "" || req.AuthorID == "" {
+		return fmt.Errorf("--id, --name and --author (or your user id) are required: %w", errUsage)
+	}
+
+	pr, err := c.client.CreatePR(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return c.printPRs([]client.PullRequest{*pr})
+}
+
+func (c *command) prPreview(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr preview", flag.ContinueOnError)
+	author := fs.String("author", c.cfg.User, "author user id (default: your user id)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 || *author == "" {
+		return fmt.Errorf("--author (or your user id) is required: %w", errUsage)
+	}
+
+	preview, err := c.client.PreviewAssignment(ctx, *author)
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(preview, func(t *tabwriter.Writer) {
+		row(t, "TEAM", "REVIEWERS", "CANDIDATES")
+		row(t, preview.TeamName, preview.ReviewersCount, orDash(preview.Candidates))
+		row(t)
+		row(t, "EXCLUDED", "TEAM", "REASON")
+		for _, e := range preview.Excluded {
+			row(t, e.UserID, e.TeamName, e.Reason)
+		}
+	})
+}
+
+func (c *command) prMerge(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr merge", flag.ContinueOnError)
+	repository := fs.String("repository", "", "repository of the PR")
+	prID, err := parseWithName(fs, args)
+	if err != nil {
+		return err
+	}
+
+	pr, err := c.client.MergePRInRepository(ctx, prID, *repository)
+	if err != nil {
+		return err
+	}
+
+	return c.printPRs([]client.PullRequest{*pr})
+}
+
+func (c *command) prReassign(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("pr reassign", flag.ContinueOnError)
+	repository := fs.String("repository", "", "repository of the PR")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := exactArgs(fs.Args(), 2); err != nil {
+		return err
+	}
+	prID, oldUserID := fs.Arg(0), fs.Arg(1)
+
+	res, err := c.client.ReassignReviewerInRepository(ctx, prID, *repository, oldUserID)
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(res, func(t *tabwriter.Writer) {
+		row(t, "PR", "STATUS", "REPLACED", "BY", "REVIEWERS")
+		row(t, res.PR.PullRequestID, res.PR.Status, oldUserID, res.ReplacedBy, orDash(res.PR.AssignedReviewers))
+	})
+}
+
+func (c *command) printPRs(prs []client.PullRequest) error {
+	if prs == nil {
+		prs = []client.PullRequest{}
+	}
+
+	return c.out.print(prs, func(t *tabwriter.Writer) {
+		row(t, "PR", "NAME", "AUTHOR", "STATUS", "REVIEWERS")
+		for _, pr := range prs {
+			row(t, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, orDash(pr.AssignedReviewers))
+		}
+	})
+}
+
+func (c *command) stats(ctx context.Context) error {
+	stats, err := c.client.Stats(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.out.print(stats, func(t *tabwriter.Writer) {
+		row(t, "ACTIVE USERS", stats.TotalActiveUsers)
+		for _, s := range stats.PRStatusDistribution {
+			row(t, s.Status+" PRS", s.Count)
+		}
+		row(t)
+		row(t, "REVIEWER", "ASSIGNMENTS")
+		for _, r := range stats.TopReviewers {
+			row(t, r.ReviewerID, r.AssignmentCount)
+		}
+	})
+}
+
+// graphql always prints JSON, the shape of the data depends on the query.
+func (c *command) graphql(ctx context.Context, args []string) error {
+	if err := exactArgs(args, 1); err != nil {
+		return err
+	}
+
+	query := args[0]
+	if query == "-" {
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return err
+		}
+		query = string(data)
+	}
+
+	resp, err := c.client.GraphQL(ctx, client.GraphQLRequest{Query: query})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(c.out.w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("query failed: %s", resp.Errors[0].Message)
+	}
+
+	return nil
+}
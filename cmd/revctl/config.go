@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+// config is read from the config file, then REVCTL_* variables, then flags.
+type config struct {
+	BaseURL string `yaml:"base_url"`
+	Token   string `yaml:"token"`
+	// TokenHeader defaults to client.DefaultTokenHeader.
+	TokenHeader string `yaml:"token_header"`
+	User        string `yaml:"user"`
+	Output      string `yaml:"output"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "revctl", "config.yml")
+}
+
+// loadConfig reads the config file. A missing default file is not an error,
+// a missing file given explicitly is.
+func loadConfig(path string) (config, error) {
+	cfg := config{
+		BaseURL: "http://localhost:8080",
+		Output:  "table",
+	}
+
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, &cfg); err != nil {
+				return config{}, fmt.Errorf("parse config file %s: %w", path, err)
+			}
+		case errors.Is(err, os.ErrNotExist) && !explicit:
+		default:
+			return config{}, fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	cfg.merge(config{
+		BaseURL:     os.Getenv("REVCTL_URL"),
+		Token:       os.Getenv("REVCTL_TOKEN"),
+		TokenHeader: os.Getenv("REVCTL_TOKEN_HEADER"),
+		User:        os.Getenv("REVCTL_USER"),
+		Output:      os.Getenv("REVCTL_OUTPUT"),
+	})
+
+	return cfg, nil
+}
+
+// merge overrides the settings that are set in o.
+func (c *config) merge(o config) {
+	for _, f := range []struct{ dst, src *string }{
+		{&c.BaseURL, &o.BaseURL},
+		{&c.Token, &o.Token},
+		{&c.TokenHeader, &o.TokenHeader},
+		{&c.User, &o.User},
+		{&c.Output, &o.Output},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+}
+
+func (c *config) validate() error {
+	if c.Output != "table" && c.Output != "json" {
+		return fmt.Errorf("output must be table or json, got %q", c.Output)
+	}
+
+	return nil
+}
+
+func (c *config) client() (*client.Client, error) {
+	opts := []client.Option{client.WithUserAgent("revctl")}
+	if c.Token != "" {
+		opts = append(opts, client.WithToken(c.Token))
+	}
+	if c.TokenHeader != "" {
+		opts = append(opts, client.WithTokenHeader(c.TokenHeader))
+	}
+
+	return client.New(c.BaseURL, opts...)
+}
@@ -0,0 +1,104 @@
+// Command revctl is a command-line client of the reviewer assignment service.
+//
+//	revctl team get backend
+//	revctl pr create --id pr-1001 --name "Add search"
+//	revctl pr reassign pr-1001 u2
+//	revctl me reviews
+//
+// Run "revctl help" for every command.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "revctl:", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+const usage = `usage: revctl [flags] <command> [args]
+
+commands:
+  ping                                       check the service is up
+  team get <team>                            show a team and its members
+  team add <team> --member id:name[:inactive]...
+                                             create a team
+  team deactivate [--dry-run] <user>...      deactivate users and reassign their reviews
+  user set-active <user> <true|false>        set the activity flag of a user
+  user reviews <user>                        list PRs where the user is a reviewer
+  me reviews                                 list your reviews (user from config or --user)
+  pr create --id <id> --name <name> [--author <user>]
+                                             create a PR, the author defaults to you
+  pr preview [--author <user>]               show who may review a new PR, the author defaults to you
+  pr merge [--repository <repo>] <pr>        merge a PR
+  pr reassign [--repository <repo>] <pr> <old reviewer>
+                                             replace a reviewer
+  stats                                      show assignment statistics
+  graphql <query|->                          run a GraphQL query, - reads it from stdin
+
+flags:
+`
+
+// run parses global flags and dispatches the command.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("revctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	var overrides config
+	configPath := fs.String("config", os.Getenv("REVCTL_CONFIG"), "config file (default $XDG_CONFIG_HOME/revctl/config.yml)")
+	fs.StringVar(&overrides.BaseURL, "url", "", "service base URL")
+	fs.StringVar(&overrides.Token, "token", "", "API token")
+	fs.StringVar(&overrides.User, "user", "", "your user id, used by \"me\" commands and as the PR author")
+	fs.StringVar(&overrides.Output, "o", "", "output format: table, json")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 || fs.Arg(0) == "help" {
+		fs.Usage()
+		return nil
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	cfg.merge(overrides)
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	cl, err := cfg.client()
+	if err != nil {
+		return err
+	}
+
+	cmd := &command{
+		client: cl,
+		cfg:    cfg,
+		out:    newPrinter(stdout, cfg.Output),
+		stdin:  os.Stdin,
+	}
+
+	return cmd.dispatch(ctx, fs.Args())
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/getReview", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token-1", r.Header.Get("X-API-Key"))
+		_, _ = w.Write([]byte(`{"user_id":"` + r.URL.Query().Get("user_id") + `","pull_requests":[
+			{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN"}]}`))
+	})
+	mux.HandleFunc("POST /pullRequest/create", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"pr":{"pull_request_id":"` + req["pull_request_id"] + `","pull_request_name":"` + req["pull_request_name"] +
+			`","author_id":"` + req["author_id"] + `","status":"OPEN","assigned_reviewers":["u2","u3"]}}`))
+	})
+	mux.HandleFunc("POST /pullRequest/reassign", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte(`{"error":{"code":"PR_MERGED","message":"cannot reassign on merged PR"}}`))
+	})
+	mux.HandleFunc("POST /team/deactivateUsers", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Users  []string `json:"users"`
+			DryRun bool     `json:"dry_run"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.DryRun)
+		_, _ = w.Write([]byte(`{"updated_prs":[],"dry_run":true,"reassignments":[
+			{"pull_request_id":"pr-1","old_reviewer_id":"u2","new_reviewer_id":"u4"},
+			{"pull_request_id":"pr-2","old_reviewer_id":"u2"}]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// writeConfig points revctl at srv through a config file.
+func writeConfig(t *testing.T, srv *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	cfg := "base_url: " + srv.URL + "\ntoken: token-1\nuser: u2\n"
+	require.NoError(t, os.WriteFile(path, []byte(cfg), 0o600))
+
+	return path
+}
+
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	err := run(context.Background(), args, &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestRun_MeReviewsUsesConfigFile(t *testing.T) {
+	path := writeConfig(t, newService(t))
+
+	out, err := runCLI(t, "--config", path, "me", "reviews")
+	require.NoError(t, err)
+	assert.Equal(t, "PR    NAME        AUTHOR  STATUS\npr-1  Add search  u1      OPEN\n", out)
+
+	out, err = runCLI(t, "--config", path, "-o", "json", "me", "reviews")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"user_id":"u2","pull_requests":[
+		{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN"}]}`, out)
+}
+
+func TestRun_PRCreateDefaultsAuthorToMe(t *testing.T) {
+	path := writeConfig(t, newService(t))
+
+	out, err := runCLI(t, "--config", path, "-o", "json", "pr", "create", "--id", "pr-7", "--name", "Fix login")
+	require.NoError(t, err)
+
+	var prs []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &prs))
+	require.Len(t, prs, 1)
+	assert.Equal(t, "u2", prs[0]["author_id"])
+	assert.Equal(t, "pr-7", prs[0]["pull_request_id"])
+}
+
+func TestRun_TeamDeactivateDryRun(t *testing.T) {
+	path := writeConfig(t, newService(t))
+
+	out, err := runCLI(t, "--config", path, "team", "deactivate", "--dry-run", "u2")
+	require.NoError(t, err)
+	assert.Equal(t, "PR    REVIEWER  REPLACED BY\npr-1  u2        u4\npr-2  u2        -\n", out)
+}
+
+func TestRun_ReportsServiceErrors(t *testing.T) {
+	path := writeConfig(t, newService(t))
+
+	_, err := runCLI(t, "--config", path, "pr", "reassign", "pr-1", "u2")
+	require.Error(t, err)
+	assert.Equal(t, "PR_MERGED: cannot reassign on merged PR", err.Error())
+
+	_, err = runCLI(t, "--config", path, "pr", "reassign", "pr-1")
+	assert.ErrorIs(t, err, errUsage)
+
+	_, err = runCLI(t, "--config", path, "pr", "close", "pr-1")
+	assert.ErrorIs(t, err, errUsage)
+}
+
+func TestRun_FlagsOverrideConfig(t *testing.T) {
+	srv := newService(t)
+	path := writeConfig(t, srv)
+
+	out, err := runCLI(t, "--config", path, "--user", "u9", "-o", "json", "me", "reviews")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"user_id": "u9"`)
+
+	_, err = runCLI(t, "--config", filepath.Join(t.TempDir(), "missing.yml"), "me", "reviews")
+	assert.ErrorContains(t, err, "read config file")
+}
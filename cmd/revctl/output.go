@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer writes results either as aligned tables or as indented JSON.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func newPrinter(w io.Writer, format string) *printer {
+	return &printer{w: w, json: format == "json"}
+}
+
+// print writes v as JSON, or calls table to write it as a table.
+func (p *printer) print(v any, table func(t *tabwriter.Writer)) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	t := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	table(t)
+	return t.Flush()
+}
+
+// row writes tab separated cells.
+func row(t *tabwriter.Writer, cells ...any) {
+	s := make([]string, len(cells))
+	for i, c := range cells {
+		s[i] = fmt.Sprint(c)
+	}
+	fmt.Fprintln(t, strings.Join(s, "\t"))
+}
+
+func orDash(s []string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,9 @@
+// Package docs embeds the API documentation.
+package docs
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI specification of the HTTP API.
+//
+//go:embed openapi.yml
+var OpenAPI []byte
@@ -0,0 +1,191 @@
+// Package codeowners parses CODEOWNERS files and finds the owners of changed paths,
+// its handlers and service store the file of each repository.
+package codeowners
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Owner is an owner of a rule: a user (@user_id) or a team (@org/team_name,
+// the organization is ignored). Email owners have neither and are never picked.
+type Owner struct {
+	Token string
+	User  string
+	Team  string
+}
+
+// Rule is a pattern and its owners, a rule without owners leaves the paths unowned.
+type Rule struct {
+	Line    int
+	Pattern string
+	Owners  []Owner
+	re      *regexp.Regexp
+}
+
+// Rules are the rules of a file in their order, the last matching rule wins.
+type Rules []Rule
+
+// LineError is a line of a CODEOWNERS file that could not be parsed.
+type LineError struct {
+	Line   int
+	Reason string
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Reason)
+}
+
+// Parse reads a CODEOWNERS file. Every malformed line is reported as a *LineError,
+// joined with errors.Join.
+func Parse(content string) (Rules, error) {
+	var (
+		rules Rules
+		errs  []error
+	)
+	for i, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(stripComment(strings.TrimSuffix(line, "\r")))
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule, err := parseRule(fields)
+		if err != nil {
+			errs = append(errs, &LineError{Line: i + 1, Reason: err.Error()})
+			continue
+		}
+		rule.Line = i + 1
+		rules = append(rules, rule)
+	}
+
+	return rules, errors.Join(errs...)
+}
+
+// stripComment cuts the line at the first unescaped #.
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '#':
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+func parseRule(fields []string) (Rule, error) {
+	pattern := strings.ReplaceAll(fields[0], `\#`, "#")
+	switch {
+	case strings.HasPrefix(pattern, "!"):
+		return Rule{}, fmt.Errorf("negated pattern %q is not supported", pattern)
+	case strings.ContainsAny(pattern, "[]"):
+		return Rule{}, fmt.Errorf("character ranges in %q are not supported", pattern)
+	case strings.Trim(pattern, "/") == "":
+		return Rule{}, fmt.Errorf("pattern %q matches nothing", pattern)
+	}
+
+	rule := Rule{Pattern: pattern, re: compile(pattern)}
+	for _, token := range fields[1:] {
+		owner, err := parseOwner(token)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.Owners = append(rule.Owners, owner)
+	}
+
+	return rule, nil
+}
+
+func parseOwner(token string) (Owner, error) {
+	name, ok := strings.CutPrefix(token, "@")
+	switch {
+	case !ok && strings.Contains(token, "@"):
+		return Owner{Token: token}, nil
+	case !ok || name == "":
+		return Owner{}, fmt.Errorf("owner %q must be @user, @org/team or an email", token)
+	}
+
+	if org, team, ok := strings.Cut(name, "/"); ok {
+		if org == "" || team == "" || strings.Contains(team, "/") {
+			return Owner{}, fmt.Errorf("owner %q must be @user, @org/team or an email", token)
+		}
+		return Owner{Token: token, Team: team}, nil
+	}
+
+	return Owner{Token: token, User: name}, nil
+}
+
+// compile turns a pattern into a regexp over slash separated paths relative to
+// the repository root, following the gitignore rules GitHub uses:
+//
+//   - a pattern without an inner slash matches at any depth, "/x" and "x/y" only at the root;
+//   - "*" and "?" do not cross slashes, "**" does;
+//   - a pattern matching a directory matches everything below it, except "dir/*",
+//     which matches the files directly in dir only.
+func compile(pattern string) *regexp.Regexp {
+	p := strings.TrimPrefix(pattern, "/")
+	anchored := p != pattern
+	dir := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	if strings.Contains(p, "/") {
+		anchored = true
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for rest := p; rest != ""; {
+		switch {
+		case strings.HasPrefix(rest, "**/"):
+			b.WriteString("(?:.*/)?")
+			rest = rest[3:]
+		case strings.HasPrefix(rest, "**"):
+			b.WriteString(".*")
+			rest = rest[2:]
+		case rest[0] == '*':
+			b.WriteString("[^/]*")
+			rest = rest[1:]
+		case rest[0] == '?':
+			b.WriteString("[^/]")
+			rest = rest[1:]
+		default:
+			n := strings.IndexAny(rest, "*?")
+			if n < 0 {
+				n = len(rest)
+			}
+			b.WriteString(regexp.QuoteMeta(rest[:n]))
+			rest = rest[n:]
+		}
+	}
+	switch {
+	case dir:
+		b.WriteString("/.*")
+	case !strings.HasSuffix(p, "/*"):
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String())
+}
+
+// Match reports whether the rule's pattern matches the path.
+func (r Rule) Match(path string) bool {
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// Owners returns the owners of the path by the last matching rule, nil if none matches.
+func (rs Rules) Owners(path string) []Owner {
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].Match(path) {
+			return rs[i].Owners
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package codeowners
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		match   []string
+		noMatch []string
+	}{
+		{"*", []string{"README.md", "a/b/c.go"}, nil},
+		{"*.js", []string{"app.js", "web/src/app.js"}, []string{"app.jsx", "app.ts"}},
+		{"/build/logs/", []string{"build/logs/a.log", "build/logs/x/y.log"}, []string{"src/build/logs/a.log", "build/logs"}},
+		{"docs/*", []string{"docs/getting-started.md"}, []string{"docs/build-app/troubleshooting.md", "src/docs/a.md"}},
+		{"apps/", []string{"apps/a.go", "src/apps/b/c.go"}, []string{"apps", "myapps/a.go"}},
+		{"/docs/", []string{"docs/a.md", "docs/x/y.md"}, []string{"src/docs/a.md"}},
+		{"**/logs", []string{"logs/a.log", "build/logs/a.log", "deep/er/logs"}, []string{"logsx/a"}},
+		{"/scripts/**/*.sh", []string{"scripts/a.sh", "scripts/x/y/b.sh"}, []string{"tools/scripts/a.sh", "scripts/a.py"}},
+		{"internal/pr", []string{"internal/pr/service.go", "/internal/pr/types.go"}, []string{"cmd/internal/pr/x.go", "internal/prx/a.go"}},
+		{"Makefile", []string{"Makefile", "sub/Makefile"}, []string{"Makefile.old"}},
+		{"v?.go", []string{"v1.go"}, []string{"v10.go", "v/.go"}},
+		{"dir(1)/a+b.txt", []string{"dir(1)/a+b.txt"}, []string{"dir1/aab.txt"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			rules, err := Parse(tt.pattern + " @owner")
+			require.NoError(t, err)
+			require.Len(t, rules, 1)
+			for _, p := range tt.match {
+				assert.True(t, rules[0].Match(p), "%s should match %s", tt.pattern, p)
+			}
+			for _, p := range tt.noMatch {
+				assert.False(t, rules[0].Match(p), "%s should not match %s", tt.pattern, p)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(`# default owners
+*       @lead
+
+/internal/ @acme/backend alice@example.com # inline comment
+*.md    @writer @acme/docs
+/vendor/
+\#notes @lead
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Owner{{Token: "@lead", User: "lead"}}, rules.Owners("main.go"))
+	assert.Equal(t, []Owner{
+		{Token: "@acme/backend", Team: "backend"},
+		{Token: "alice@example.com"},
+	}, rules.Owners("internal/pr/service.go"))
+	// the last matching rule wins
+	assert.Equal(t, []Owner{
+		{Token: "@writer", User: "writer"},
+		{Token: "@acme/docs", Team: "docs"},
+	}, rules.Owners("internal/README.md"))
+	assert.Empty(t, rules.Owners("vendor/lib/a.go"))
+	assert.Equal(t, "#notes", rules[4].Pattern)
+	assert.Equal(t, 7, rules[4].Line)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("*.go @ok\n!*.md @a\nsrc/[ab].go @a\n*.txt team\n/ @a\n*.py @acme/\n")
+	require.Error(t, err)
+
+	var lines []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var lineErr *LineError
+		require.True(t, errors.As(e, &lineErr))
+		lines = append(lines, lineErr.Line)
+	}
+	assert.Equal(t, []int{2, 3, 4, 5, 6}, lines)
+}
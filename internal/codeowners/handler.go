@@ -0,0 +1,25 @@
+package codeowners
+
+import (
+	"net/http"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
+)
+
+// Upload handles uploading the CODEOWNERS file of a repository.
+func (h *Handler) Upload(w http.ResponseWriter, r *http.Request) {
+	var req UploadParams
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in Upload", errors.InvalidJSON(err))
+		return
+	}
+
+	response, err := h.service.Upload(r.Context(), req)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to upload code owners", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
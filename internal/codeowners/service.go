@@ -0,0 +1,103 @@
+package codeowners
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	apperrors "github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+)
+
+// Upload parses and stores the CODEOWNERS file of a registered repository. Owners may
+// refer to users and teams created later, they are reported but accepted.
+func (s *svc) Upload(ctx context.Context, params UploadParams) (UploadResponse, error) {
+	// validation
+	var details []apperrors.FieldError
+	if params.Repository == "" {
+		details = append(details, apperrors.FieldError{Field: "repository", Reason: "must not be empty"})
+	}
+	rules, err := Parse(params.Content)
+	if err != nil {
+		var joined interface{ Unwrap() []error }
+		if !errors.As(err, &joined) {
+			return UploadResponse{}, err
+		}
+		for _, e := range joined.Unwrap() {
+			details = append(details, apperrors.FieldError{Field: "content", Reason: e.Error()})
+		}
+	}
+	if len(details) > 0 {
+		return UploadResponse{}, apperrors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	if _, err := s.repo.GetRepository(ctx, params.Repository); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return UploadResponse{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "repository", Reason: "repository not found"})
+		}
+		return UploadResponse{}, err
+	}
+
+	unknown, err := s.unknownOwners(ctx, rules)
+	if err != nil {
+		return UploadResponse{}, err
+	}
+
+	if _, err := s.repo.UpsertCodeOwners(ctx, repo.UpsertCodeOwnersParams{Repository: params.Repository, Content: params.Content}); err != nil {
+		return UploadResponse{}, err
+	}
+
+	response := UploadResponse{Repository: params.Repository, Rules: []RuleResponse{}, UnknownOwners: unknown}
+	for _, r := range rules {
+		owners := make([]string, len(r.Owners))
+		for i, o := range r.Owners {
+			owners[i] = o.Token
+		}
+		response.Rules = append(response.Rules, RuleResponse{Line: r.Line, Pattern: r.Pattern, Owners: owners})
+	}
+
+	return response, nil
+}
+
+// unknownOwners returns the owners in file order that are no existing user or team.
+func (s *svc) unknownOwners(ctx context.Context, rules Rules) ([]string, error) {
+	var userIDs, teams []string
+	for _, r := range rules {
+		for _, o := range r.Owners {
+			switch {
+			case o.User != "":
+				userIDs = append(userIDs, o.User)
+			case o.Team != "":
+				teams = append(teams, o.Team)
+			}
+		}
+	}
+
+	known := make(map[Owner]bool)
+	users, err := s.repo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		known[Owner{User: u.UserID}] = true
+	}
+	members, err := s.repo.GetUsersByTeams(ctx, teams)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		known[Owner{Team: m.TeamName}] = true
+	}
+
+	unknown := []string{}
+	for _, r := range rules {
+		for _, o := range r.Owners {
+			if !known[Owner{User: o.User, Team: o.Team}] && !slices.Contains(unknown, o.Token) {
+				unknown = append(unknown, o.Token)
+			}
+		}
+	}
+
+	return unknown, nil
+}
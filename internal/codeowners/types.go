@@ -0,0 +1,60 @@
+package codeowners
+
+import (
+	"context"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+)
+
+// Service defines the interface for the code owners service.
+type Service interface {
+	// Upload replaces the CODEOWNERS file of the repository.
+	Upload(ctx context.Context, params UploadParams) (UploadResponse, error)
+}
+
+// Handler handles HTTP requests for the code owners service.
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new code owners handler.
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+type svc struct {
+	repo *repo.Queries
+	db   postgres.DB
+}
+
+// NewService creates a new code owners service.
+func NewService(repo *repo.Queries, db postgres.DB) Service {
+	return &svc{
+		repo: repo,
+		db:   db,
+	}
+}
+
+// UploadParams represents the request body for uploading a CODEOWNERS file.
+type UploadParams struct {
+	Repository string `json:"repository"`
+	Content    string `json:"content"`
+}
+
+// RuleResponse is a parsed rule of an uploaded file.
+type RuleResponse struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+// UploadResponse lists the stored rules and the owners that match no user or team,
+// they are skipped when reviewers are picked.
+type UploadResponse struct {
+	Repository    string         `json:"repository"`
+	Rules         []RuleResponse `json:"rules"`
+	UnknownOwners []string       `json:"unknown_owners"`
+}
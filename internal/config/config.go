@@ -0,0 +1,401 @@
+// Package config loads the service configuration from a YAML file,
+// environment variables and command-line flags.
+//
+// Sources are applied in order, each one overriding the previous:
+// built-in defaults, YAML file (--config or CONFIG_FILE), environment, flags.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/ratelimit"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the full configuration of the service.
+type Config struct {
+	HTTP      HTTPConfig      `yaml:"http"`
+	GRPC      GRPCConfig      `yaml:"grpc"`
+	DB        DBConfig        `yaml:"db"`
+	Log       LogConfig       `yaml:"log"`
+	Reviewers ReviewersConfig `yaml:"reviewers"`
+	Absences  AbsencesConfig  `yaml:"absences"`
+	Features  FeaturesConfig  `yaml:"features"`
+	OpenAPI   OpenAPIConfig   `yaml:"openapi"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+// HTTPConfig configures the HTTP server.
+type HTTPConfig struct {
+	Addr            string        `yaml:"addr"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	RequestTimeout  time.Duration `yaml:"request_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// MaxBodyBytes limits the size of a request body.
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	// TrustedProxies are the IPs or CIDR prefixes whose X-Forwarded-For and
+	// X-Real-IP headers (gRPC metadata) name the client, nobody else's are trusted.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// GRPCConfig configures the gRPC server, it serves the same API on its own port.
+type GRPCConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
+// DBConfig configures the PostgreSQL connection pool.
+type DBConfig struct {
+	DSN            string        `yaml:"dsn"`
+	ConnectTimeout time.Duration `yaml:"connect_timeout"`
+	MaxConns       int32         `yaml:"max_conns"`
+	MinConns       int32         `yaml:"min_conns"`
+	MigrateOnStart bool          `yaml:"migrate_on_start"`
+}
+
+// LogConfig configures the logger.
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// ReviewersConfig configures reviewer assignment.
+type ReviewersConfig struct {
+	// Count is how many reviewers are assigned to a new PR, 1 or 2:
+	// a PR never has more than two reviewers.
+	Count int `yaml:"count"`
+}
+
+// AbsencesConfig configures what happens to reviews when an absence begins.
+type AbsencesConfig struct {
+	// ReassignReviews starts a worker that reassigns the open reviews of users
+	// whose absence has begun, otherwise they keep them until they are back.
+	ReassignReviews bool          `yaml:"reassign_reviews"`
+	CheckInterval   time.Duration `yaml:"check_interval"`
+}
+
+// FeaturesConfig toggles optional parts of the API.
+type FeaturesConfig struct {
+	Stats            bool `yaml:"stats"`
+	MassDeactivation bool `yaml:"mass_deactivation"`
+	GraphQL          bool `yaml:"graphql"`
+}
+
+// OpenAPIConfig configures validation of the HTTP traffic against docs/openapi.yml.
+type OpenAPIConfig struct {
+	ValidateRequests bool `yaml:"validate_requests"`
+	// ValidateResponses is meant for tests, it buffers every response.
+	// It implies ValidateRequests.
+	ValidateResponses bool `yaml:"validate_responses"`
+}
+
+// RouteGroups lists the route groups that can have their own rate limit.
+var RouteGroups = []string{"team", "users", "pullRequest", "repository", "codeowners", "stats", "graphql"}
+
+// RateLimitConfig configures per-client rate limiting.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Backend is "memory" (per replica) or "postgres" (shared by all replicas).
+	Backend string `yaml:"backend"`
+	// KeyHeader carries the client API key; clients without a key listed in
+	// APIKeys are limited by IP.
+	KeyHeader string   `yaml:"key_header"`
+	APIKeys   []string `yaml:"api_keys"`
+	// PostgresMaxConns sizes the pool of the postgres backend, separate from db.max_conns.
+	PostgresMaxConns int32           `yaml:"postgres_max_conns"`
+	Default          ratelimit.Limit `yaml:"default"`
+	// Groups overrides Default for the route groups listed in RouteGroups.
+	Groups map[string]ratelimit.Limit `yaml:"groups"`
+}
+
+// LimitFor returns the limit of a route group.
+func (c RateLimitConfig) LimitFor(group string) ratelimit.Limit {
+	if l, ok := c.Groups[group]; ok {
+		return l
+	}
+
+	return c.Default
+}
+
+// Default returns the configuration used when nothing is overridden.
+func Default() Config {
+	return Config{
+		HTTP: HTTPConfig{
+			Addr:            ":8080",
+			ReadTimeout:     time.Second * 30,
+			WriteTimeout:    time.Second * 30,
+			IdleTimeout:     time.Minute,
+			RequestTimeout:  time.Minute,
+			ShutdownTimeout: time.Second * 15,
+			MaxBodyBytes:    1 << 20,
+		},
+		GRPC: GRPCConfig{
+			Enabled: true,
+			Addr:    ":9090",
+		},
+		DB: DBConfig{
+			DSN:            "host=localhost user=trainee password=trainee_password dbname=trainee_db sslmode=disable",
+			ConnectTimeout: time.Second * 5,
+			MaxConns:       10,
+			MinConns:       0,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Reviewers: ReviewersConfig{
+			Count: 2,
+		},
+		Absences: AbsencesConfig{
+			ReassignReviews: false,
+			CheckInterval:   time.Minute,
+		},
+		Features: FeaturesConfig{
+			Stats:            true,
+			MassDeactivation: true,
+			GraphQL:          true,
+		},
+		OpenAPI: OpenAPIConfig{
+			ValidateRequests:  true,
+			ValidateResponses: false,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:          true,
+			Backend:          "memory",
+			KeyHeader:        "X-API-Key",
+			PostgresMaxConns: 2,
+			Default:          ratelimit.Limit{RPS: 100, Burst: 200},
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the YAML file, environment
+// variables and the given command-line arguments.
+// It returns the validated configuration and the non-flag arguments (subcommands).
+func Load(args []string) (Config, []string, error) {
+	// first pass only finds the config file, flags are applied again on top of it
+	var path string
+	probe := newFlagSet(&Config{}, &path)
+	probe.SetOutput(io.Discard)
+	if err := probe.Parse(args); err != nil && !errors.Is(err, flag.ErrHelp) {
+		return Config{}, nil, err
+	}
+
+	cfg := Default()
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return Config{}, nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return Config{}, nil, err
+	}
+
+	fs := newFlagSet(&cfg, &path)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func newFlagSet(cfg *Config, path *string) *flag.FlagSet {
+	fs := flag.NewFlagSet("main", flag.ContinueOnError)
+
+	fs.StringVar(path, "config", os.Getenv("CONFIG_FILE"), "path to the YAML config file")
+
+	fs.StringVar(&cfg.HTTP.Addr, "addr", cfg.HTTP.Addr, "HTTP listen address")
+	fs.DurationVar(&cfg.HTTP.ReadTimeout, "read-timeout", cfg.HTTP.ReadTimeout, "HTTP server read timeout")
+	fs.DurationVar(&cfg.HTTP.WriteTimeout, "write-timeout", cfg.HTTP.WriteTimeout, "HTTP server write timeout")
+	fs.DurationVar(&cfg.HTTP.IdleTimeout, "idle-timeout", cfg.HTTP.IdleTimeout, "HTTP server idle timeout")
+	fs.DurationVar(&cfg.HTTP.RequestTimeout, "request-timeout", cfg.HTTP.RequestTimeout, "timeout of a single request")
+	fs.DurationVar(&cfg.HTTP.ShutdownTimeout, "shutdown-timeout", cfg.HTTP.ShutdownTimeout, "time to drain in-flight requests on shutdown")
+	fs.Int64Var(&cfg.HTTP.MaxBodyBytes, "max-body-bytes", cfg.HTTP.MaxBodyBytes, "maximum size of a request body")
+
+	fs.BoolVar(&cfg.GRPC.Enabled, "grpc", cfg.GRPC.Enabled, "serve the gRPC API")
+	fs.StringVar(&cfg.GRPC.Addr, "grpc-addr", cfg.GRPC.Addr, "gRPC listen address")
+
+	fs.StringVar(&cfg.DB.DSN, "dsn", cfg.DB.DSN, "PostgreSQL connection string")
+	fs.DurationVar(&cfg.DB.ConnectTimeout, "db-connect-timeout", cfg.DB.ConnectTimeout, "timeout of the initial database connection")
+	int32Var(fs, &cfg.DB.MaxConns, "db-max-conns", "maximum size of the connection pool")
+	int32Var(fs, &cfg.DB.MinConns, "db-min-conns", "minimum size of the connection pool")
+	fs.BoolVar(&cfg.DB.MigrateOnStart, "migrate", cfg.DB.MigrateOnStart, "apply pending database migrations before starting the server")
+
+	fs.StringVar(&cfg.Log.Level, "log-level", cfg.Log.Level, "log level: debug, info, warn, error")
+	fs.StringVar(&cfg.Log.Format, "log-format", cfg.Log.Format, "log format: text, json")
+
+	fs.IntVar(&cfg.Reviewers.Count, "reviewers", cfg.Reviewers.Count, "number of reviewers assigned to a new PR")
+
+	fs.BoolVar(&cfg.Absences.ReassignReviews, "absence-reassign", cfg.Absences.ReassignReviews, "reassign the open reviews of users when their absence begins")
+	fs.DurationVar(&cfg.Absences.CheckInterval, "absence-check-interval", cfg.Absences.CheckInterval, "how often to look for absences that have begun")
+
+	fs.BoolVar(&cfg.Features.Stats, "feature-stats", cfg.Features.Stats, "enable the /stats endpoint")
+	fs.BoolVar(&cfg.Features.MassDeactivation, "feature-mass-deactivation", cfg.Features.MassDeactivation, "enable the /team/deactivateUsers endpoint")
+	fs.BoolVar(&cfg.Features.GraphQL, "feature-graphql", cfg.Features.GraphQL, "enable the /graphql endpoint")
+
+	fs.BoolVar(&cfg.OpenAPI.ValidateRequests, "openapi-validate-requests", cfg.OpenAPI.ValidateRequests, "validate requests against docs/openapi.yml")
+	fs.BoolVar(&cfg.OpenAPI.ValidateResponses, "openapi-validate-responses", cfg.OpenAPI.ValidateResponses, "validate responses against docs/openapi.yml (test mode)")
+
+	fs.BoolVar(&cfg.RateLimit.Enabled, "rate-limit", cfg.RateLimit.Enabled, "enable per-client rate limiting")
+	fs.StringVar(&cfg.RateLimit.Backend, "rate-limit-backend", cfg.RateLimit.Backend, "rate limiter backend: memory, postgres")
+
+	return fs
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.HTTP.Addr == "" {
+		errs = append(errs, errors.New("http.addr must not be empty"))
+	}
+	if c.HTTP.MaxBodyBytes < 1 {
+		errs = append(errs, fmt.Errorf("http.max_body_bytes must be positive, got %d", c.HTTP.MaxBodyBytes))
+	}
+	for _, p := range c.HTTP.TrustedProxies {
+		if _, err := ratelimit.ParsePrefix(p); err != nil {
+			errs = append(errs, fmt.Errorf("http.trusted_proxies: %w", err))
+		}
+	}
+	if c.GRPC.Enabled {
+		if c.GRPC.Addr == "" {
+			errs = append(errs, errors.New("grpc.addr must not be empty"))
+		} else if c.GRPC.Addr == c.HTTP.Addr {
+			errs = append(errs, fmt.Errorf("grpc.addr must differ from http.addr, both are %q", c.GRPC.Addr))
+		}
+	}
+	for _, t := range []struct {
+		name string
+		d    time.Duration
+	}{
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"http.request_timeout", c.HTTP.RequestTimeout},
+		{"http.shutdown_timeout", c.HTTP.ShutdownTimeout},
+		{"db.connect_timeout", c.DB.ConnectTimeout},
+		{"absences.check_interval", c.Absences.CheckInterval},
+	} {
+		if t.d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", t.name, t.d))
+		}
+	}
+
+	if c.DB.DSN == "" {
+		errs = append(errs, errors.New("db.dsn must not be empty"))
+	}
+	if c.DB.MaxConns < 1 {
+		errs = append(errs, fmt.Errorf("db.max_conns must be at least 1, got %d", c.DB.MaxConns))
+	}
+	if c.DB.MinConns < 0 || c.DB.MinConns > c.DB.MaxConns {
+		errs = append(errs, fmt.Errorf("db.min_conns must be between 0 and db.max_conns, got %d", c.DB.MinConns))
+	}
+
+	if _, err := c.Log.SlogLevel(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Log.Format != "text" && c.Log.Format != "json" {
+		errs = append(errs, fmt.Errorf("log.format must be text or json, got %q", c.Log.Format))
+	}
+
+	if c.Reviewers.Count < 1 || c.Reviewers.Count > 2 {
+		errs = append(errs, fmt.Errorf("reviewers.count must be 1 or 2, got %d", c.Reviewers.Count))
+	}
+
+	if c.RateLimit.Backend != "memory" && c.RateLimit.Backend != "postgres" {
+		errs = append(errs, fmt.Errorf("rate_limit.backend must be memory or postgres, got %q", c.RateLimit.Backend))
+	}
+	if c.RateLimit.Backend == "postgres" && c.RateLimit.PostgresMaxConns < 1 {
+		errs = append(errs, fmt.Errorf("rate_limit.postgres_max_conns must be at least 1, got %d", c.RateLimit.PostgresMaxConns))
+	}
+	if slices.Contains(c.RateLimit.APIKeys, "") {
+		errs = append(errs, errors.New("rate_limit.api_keys must not contain empty keys"))
+	}
+	errs = append(errs, validateLimit("rate_limit.default", c.RateLimit.Default)...)
+	for group, l := range c.RateLimit.Groups {
+		if !slices.Contains(RouteGroups, group) {
+			errs = append(errs, fmt.Errorf("rate_limit.groups: unknown group %q, expected one of %v", group, RouteGroups))
+		}
+		errs = append(errs, validateLimit("rate_limit.groups."+group, l)...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func validateLimit(name string, l ratelimit.Limit) []error {
+	var errs []error
+	if l.RPS <= 0 {
+		errs = append(errs, fmt.Errorf("%s.rps must be positive, got %v", name, l.RPS))
+	}
+	if l.Burst < 1 {
+		errs = append(errs, fmt.Errorf("%s.burst must be at least 1, got %d", name, l.Burst))
+	}
+
+	return errs
+}
+
+// SlogLevel converts the configured level to a slog.Level.
+func (l LogConfig) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return 0, fmt.Errorf("log.level must be debug, info, warn or error, got %q", l.Level)
+	}
+
+	return level, nil
+}
+
+// NewLogger creates a logger according to the configuration.
+func (l LogConfig) NewLogger(w io.Writer) *slog.Logger {
+	level, _ := l.SlogLevel()
+	opts := &slog.HandlerOptions{Level: level}
+
+	if l.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, opts))
+	}
+
+	return slog.New(slog.NewTextHandler(w, opts))
+}
+
+// int32Var defines an int32 flag, the flag package has no such helper.
+func int32Var(fs *flag.FlagSet, p *int32, name, usage string) {
+	fs.Func(name, fmt.Sprintf("%s (default %d)", usage, *p), func(s string) error {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid int32 value %q", s)
+		}
+		*p = int32(v)
+		return nil
+	})
+}
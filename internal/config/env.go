@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/env"
+)
+
+// loadEnv applies environment variable overrides.
+// Unlike the env package helpers, malformed values are reported as errors.
+func (c *Config) loadEnv() error {
+	var errs []error
+
+	c.HTTP.Addr = env.GetString("HTTP_ADDR", c.HTTP.Addr)
+	errs = append(errs,
+		envDuration("HTTP_READ_TIMEOUT", &c.HTTP.ReadTimeout),
+		envDuration("HTTP_WRITE_TIMEOUT", &c.HTTP.WriteTimeout),
+		envDuration("HTTP_IDLE_TIMEOUT", &c.HTTP.IdleTimeout),
+		envDuration("HTTP_REQUEST_TIMEOUT", &c.HTTP.RequestTimeout),
+		envDuration("SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout),
+		envInt64("HTTP_MAX_BODY_BYTES", &c.HTTP.MaxBodyBytes),
+	)
+	envList("HTTP_TRUSTED_PROXIES", &c.HTTP.TrustedProxies)
+
+	c.GRPC.Addr = env.GetString("GRPC_ADDR", c.GRPC.Addr)
+	errs = append(errs, envBool("GRPC_ENABLED", &c.GRPC.Enabled))
+
+	c.DB.DSN = env.GetString("DATABASE_URL", env.GetString("GOOSE_DBSTRING", c.DB.DSN))
+	errs = append(errs,
+		envDuration("DB_CONNECT_TIMEOUT", &c.DB.ConnectTimeout),
+		envInt32("DB_MAX_CONNS", &c.DB.MaxConns),
+		envInt32("DB_MIN_CONNS", &c.DB.MinConns),
+		envBool("MIGRATE_ON_START", &c.DB.MigrateOnStart),
+	)
+
+	c.Log.Level = env.GetString("LOG_LEVEL", c.Log.Level)
+	c.Log.Format = env.GetString("LOG_FORMAT", c.Log.Format)
+
+	errs = append(errs,
+		envInt("REVIEWERS_COUNT", &c.Reviewers.Count),
+		envBool("ABSENCES_REASSIGN_REVIEWS", &c.Absences.ReassignReviews),
+		envDuration("ABSENCES_CHECK_INTERVAL", &c.Absences.CheckInterval),
+		envBool("FEATURE_STATS", &c.Features.Stats),
+		envBool("FEATURE_MASS_DEACTIVATION", &c.Features.MassDeactivation),
+		envBool("FEATURE_GRAPHQL", &c.Features.GraphQL),
+		envBool("OPENAPI_VALIDATE_REQUESTS", &c.OpenAPI.ValidateRequests),
+		envBool("OPENAPI_VALIDATE_RESPONSES", &c.OpenAPI.ValidateResponses),
+		envBool("RATE_LIMIT_ENABLED", &c.RateLimit.Enabled),
+		envFloat("RATE_LIMIT_RPS", &c.RateLimit.Default.RPS),
+		envInt("RATE_LIMIT_BURST", &c.RateLimit.Default.Burst),
+		envInt32("RATE_LIMIT_POSTGRES_MAX_CONNS", &c.RateLimit.PostgresMaxConns),
+	)
+	c.RateLimit.Backend = env.GetString("RATE_LIMIT_BACKEND", c.RateLimit.Backend)
+	c.RateLimit.KeyHeader = env.GetString("RATE_LIMIT_KEY_HEADER", c.RateLimit.KeyHeader)
+	envList("RATE_LIMIT_API_KEYS", &c.RateLimit.APIKeys)
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid environment: %w", err)
+	}
+
+	return nil
+}
+
+func envDuration(key string, p *time.Duration) error {
+	val := os.Getenv(key)
+	if val == "" {
+		return nil
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return fmt.Errorf("%s: invalid duration %q", key, val)
+	}
+	*p = d
+
+	return nil
+}
+
+func envInt(key string, p *int) error {
+	val := os.Getenv(key)
+	if val == "" {
+		return nil
+	}
+
+	v, err := strconv.Atoi(val)
+	if err != nil {
+		return fmt.Errorf("%s: invalid integer %q", key, val)
+	}
+	*p = v
+
+	return nil
+}
+
+func envFloat(key string, p *float64) error {
+	val := os.Getenv(key)
+	if val == "" {
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return fmt.Errorf("%s: invalid number %q", key, val)
+	}
+	*p = v
+
+	return nil
+}
+
+func envInt64(key string, p *int64) error {
+	val := os.Getenv(key)
+	if val == "" {
+		return nil
+	}
+
+	v, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s: invalid integer %q", key, val)
+	}
+	*p = v
+
+	return nil
+}
+
+func envInt32(key string, p *int32) error {
+	val := os.Getenv(key)
+	if val == "" {
+		return nil
+	}
+
+	v, err := strconv.ParseInt(val, 10, 32)
+	if err != nil {
+		return fmt.Errorf("%s: invalid integer %q", key, val)
+	}
+	*p = int32(v)
+
+	return nil
+}
+
+func envBool(key string, p *bool) error {
+	val := os.Getenv(key)
+	if val == "" {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return fmt.Errorf("%s: invalid bool %q", key, val)
+	}
+	*p = b
+
+	return nil
+}
+
+// envList reads a comma-separated list, empty items are dropped.
+func envList(key string, p *[]string) {
+	val := os.Getenv(key)
+	if val == "" {
+		return
+	}
+
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*p = items
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "REDACTED"
+
+var dsnPasswordRe = regexp.MustCompile(`(password\s*=\s*)('[^']*'|\S+)`)
+
+// Print writes the configuration as YAML with secrets redacted.
+func (c Config) Print(w io.Writer) error {
+	c.DB.DSN = redactDSN(c.DB.DSN)
+	if len(c.RateLimit.APIKeys) > 0 {
+		keys := make([]string, len(c.RateLimit.APIKeys))
+		for i := range keys {
+			keys[i] = redacted
+		}
+		c.RateLimit.APIKeys = keys
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+// redactDSN hides the password in both URL and key=value connection strings,
+// a URL may carry it in the user info or as the password query parameter.
+func redactDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+		if q := u.Query(); q.Has("password") {
+			q.Set("password", redacted)
+			u.RawQuery = q.Encode()
+		}
+		return u.String()
+	}
+
+	return dsnPasswordRe.ReplaceAllString(dsn, "${1}"+redacted)
+}
@@ -7,5 +7,48 @@ type PRWithReviewers struct {
 	PullRequestName   string   `json:"pull_request_name"`
 	AuthorID          string   `json:"author_id"`
 	Status            string   `json:"status"`
+	Repository        string   `json:"repository,omitempty"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
+	// MissingSenior is set when the team of the author requires a senior
+	// reviewer and none of the assigned reviewers is one.
+	MissingSenior bool `json:"missing_senior,omitempty"`
+}
+
+// PRKey identifies a PR, PR ids are unique within their repository.
+// Repository is empty for the PRs without one.
+type PRKey struct {
+	Repository    string
+	PullRequestID string
+}
+
+// Reviewer levels, senior and maintainer reviewers satisfy the required senior rule of a team.
+const (
+	LevelRegular    = "regular"
+	LevelSenior     = "senior"
+	LevelMaintainer = "maintainer"
+)
+
+// IsSenior reports whether a reviewer of the level counts as senior.
+func IsSenior(level string) bool {
+	return level == LevelSenior || level == LevelMaintainer
+}
+
+// Kinds of pair rules of a team: never assign the reviewer to PRs by the author,
+// or prefer the reviewer for them.
+const (
+	PairNever  = "never"
+	PairPrefer = "prefer"
+)
+
+// PairRules maps reviewers to the kind of their pair rule with one author.
+type PairRules map[string]string
+
+// Never reports whether the reviewer must not review the author's PRs.
+func (r PairRules) Never(reviewerID string) bool {
+	return r[reviewerID] == PairNever
+}
+
+// Prefers reports whether the reviewer is preferred for the author's PRs.
+func (r PairRules) Prefers(reviewerID string) bool {
+	return r[reviewerID] == PairPrefer
 }
@@ -5,19 +5,46 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // AppError represents an application error with a code, message, and HTTP status.
 type AppError struct {
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	HTTPStatus int    `json:"-"`
+	Code       string       `json:"code"`
+	Message    string       `json:"message"`
+	Details    []FieldError `json:"details,omitempty"`
+	RequestID  string       `json:"request_id,omitempty"`
+	HTTPStatus int          `json:"-"`
+}
+
+// FieldError describes why a single input field was rejected.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
 }
 
 func (e *AppError) Error() string {
-	return e.Message
+	if len(e.Details) == 0 {
+		return e.Message
+	}
+
+	reasons := make([]string, len(e.Details))
+	for i, d := range e.Details {
+		reasons[i] = d.Field + ": " + d.Reason
+	}
+
+	return e.Message + ": " + strings.Join(reasons, "; ")
+}
+
+// Is matches copies made by WithMessage and WithDetails, errors.Is(err, ErrNoCandidate)
+// holds whatever details are attached.
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code == e.Code
 }
 
 // NewAppError creates a new AppError.
@@ -29,6 +56,36 @@ func NewAppError(code, message string, status int) *AppError {
 	}
 }
 
+// WithMessage returns a copy of the error with a more specific message.
+func (e *AppError) WithMessage(message string) *AppError {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// WithDetails returns a copy of the error with the given field errors appended.
+func (e *AppError) WithDetails(details ...FieldError) *AppError {
+	c := *e
+	c.Details = append(append([]FieldError(nil), e.Details...), details...)
+	return &c
+}
+
+// InvalidField is a shortcut for ErrInvalidInput with a single field error.
+func InvalidField(field, reason string) *AppError {
+	return ErrInvalidInput.WithDetails(FieldError{Field: field, Reason: reason})
+}
+
+// InvalidJSON converts an error returned by json.Read into ErrInvalidInput
+// naming the field that could not be decoded.
+func InvalidJSON(err error) *AppError {
+	var decodeErr *json.DecodeError
+	if errors.As(err, &decodeErr) {
+		return InvalidField(decodeErr.Field, decodeErr.Reason)
+	}
+
+	return InvalidField("body", err.Error())
+}
+
 // ErrorResponse represents the JSON response for an error.
 type ErrorResponse struct {
 	Error *AppError `json:"error"`
@@ -48,25 +105,47 @@ var (
 	// ErrNotFound indicates that the requested resource was not found.
 	ErrNotFound = NewAppError("NOT_FOUND", "resource not found", http.StatusNotFound)
 
+	// ErrRateLimited indicates that the client sent too many requests.
+	ErrRateLimited = NewAppError("RATE_LIMITED", "too many requests", http.StatusTooManyRequests)
+
 	// ErrInvalidInput indicates that the input data is invalid.
 	ErrInvalidInput = NewAppError("INVALID_INPUT", "input data is invalid", http.StatusBadRequest)
 	// InternalError indicates an internal server error.
 	InternalError = NewAppError("INTERNAL_ERROR", "internal server error", http.StatusInternalServerError)
 )
 
-// WriteAppError writes an error response to the ResponseWriter.
-func WriteAppError(w http.ResponseWriter, logMsg string, err error) {
+// From returns the AppError an error should be reported as.
+// PostgreSQL data exceptions (SQLSTATE class 22, e.g. a NUL byte in a text
+// value) are caused by the input and become ErrInvalidInput, any other
+// error that is not an AppError is an InternalError.
+func From(err error) *AppError {
 	var appErr *AppError
 	if errors.As(err, &appErr) {
-		slog.Error(logMsg, "error", err)
-		json.Write(w, appErr.HTTPStatus, ErrorResponse{
-			Error: appErr,
-		})
-		return
+		return appErr
 	}
 
-	slog.Error(logMsg, "error", err)
-	json.Write(w, http.StatusInternalServerError, ErrorResponse{
-		Error: InternalError,
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, "22") {
+		field := pgErr.ColumnName
+		if field == "" {
+			field = "request"
+		}
+		return InvalidField(field, pgErr.Message)
+	}
+
+	return InternalError
+}
+
+// WriteAppError writes an error response to the ResponseWriter.
+// The response carries the request id, so clients can refer to it in bug reports.
+func WriteAppError(w http.ResponseWriter, r *http.Request, logMsg string, err error) {
+	requestID := middleware.GetReqID(r.Context())
+	slog.Error(logMsg, "error", err, "request_id", requestID)
+
+	appErr := From(err)
+	resp := *appErr
+	resp.RequestID = requestID
+	json.Write(w, appErr.HTTPStatus, ErrorResponse{
+		Error: &resp,
 	})
 }
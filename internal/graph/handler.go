@@ -0,0 +1,69 @@
+// Package graph serves the /graphql endpoint over teams, users, pull requests and review assignments.
+package graph
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+const (
+	// maxDepth stops queries that walk the graph in circles (team → members → team → ...).
+	maxDepth = 10
+	// maxParallelism is how many resolvers of one request run concurrently,
+	// it bounds the size of a batch, so it is kept well above a team size.
+	maxParallelism = 500
+)
+
+// Handler executes GraphQL queries.
+type Handler struct {
+	schema *graphql.Schema
+	repo   repo.Querier
+}
+
+// NewHandler binds the embedded schema to the resolvers.
+// It panics if the schema does not match them.
+func NewHandler(repo repo.Querier) *Handler {
+	return &Handler{
+		schema: graphql.MustParseSchema(schema, &rootResolver{},
+			graphql.MaxDepth(maxDepth),
+			graphql.MaxParallelism(maxParallelism),
+		),
+		repo: repo,
+	}
+}
+
+// Request is the body of a GraphQL request.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	Extensions    map[string]any `json:"extensions"`
+}
+
+// ServeHTTP handles POST /graphql.
+// Errors of the query itself are reported in the GraphQL response with status 200.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in GraphQL request", errors.InvalidJSON(err))
+		return
+	}
+
+	if req.Query == "" {
+		errors.WriteAppError(w, r, "empty GraphQL query", errors.InvalidField("query", "must not be empty"))
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.repo))
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	json.Write(w, http.StatusOK, response)
+}
@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo serves the batch queries from memory and counts the calls.
+type fakeRepo struct {
+	repo.Querier
+
+	users       []repo.User
+	prs         []repo.PullRequest
+	assignments map[domain.PRKey][]string // pr -> reviewer ids
+
+	mu    sync.Mutex
+	calls map[string][][]string
+}
+
+func (f *fakeRepo) record(name string, keys []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string][][]string)
+	}
+	f.calls[name] = append(f.calls[name], slices.Sorted(slices.Values(keys)))
+}
+
+func (f *fakeRepo) GetUsersByIDs(_ context.Context, ids []string) ([]repo.User, error) {
+	f.record("GetUsersByIDs", ids)
+	var out []repo.User
+	for _, u := range f.users {
+		if slices.Contains(ids, u.UserID) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) GetUsersByTeams(_ context.Context, teams []string) ([]repo.User, error) {
+	f.record("GetUsersByTeams", teams)
+	var out []repo.User
+	for _, u := range f.users {
+		if slices.Contains(teams, u.TeamName) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) GetPRsByIDs(_ context.Context, arg repo.GetPRsByIDsParams) ([]repo.PullRequest, error) {
+	f.record("GetPRsByIDs", arg.PrIds)
+	var out []repo.PullRequest
+	for _, p := range f.prs {
+		for i := range arg.PrIds {
+			if arg.PrRepositories[i] == p.RepositoryKey && arg.PrIds[i] == p.PullRequestID {
+				out = append(out, p)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) GetPRsByReviewers(_ context.Context, ids []string) ([]repo.GetPRsByReviewersRow, error) {
+	f.record("GetPRsByReviewers", ids)
+	var out []repo.GetPRsByReviewersRow
+	for _, p := range f.prs {
+		for _, r := range f.assignments[domain.PRKey{Repository: p.RepositoryKey, PullRequestID: p.PullRequestID}] {
+			if slices.Contains(ids, r) {
+				out = append(out, repo.GetPRsByReviewersRow{
+					ReviewerID:      r,
+					PullRequestID:   p.PullRequestID,
+					PullRequestName: p.PullRequestName,
+					AuthorID:        p.AuthorID,
+					Status:          p.Status,
+					Repository:      p.Repository,
+					RepositoryKey:   p.RepositoryKey,
+				})
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) GetReviewersByPRs(_ context.Context, arg repo.GetReviewersByPRsParams) ([]repo.GetReviewersByPRsRow, error) {
+	f.record("GetReviewersByPRs", arg.PrIds)
+	var out []repo.GetReviewersByPRsRow
+	for i, id := range arg.PrIds {
+		for _, r := range f.assignments[domain.PRKey{Repository: arg.PrRepositories[i], PullRequestID: id}] {
+			out = append(out, repo.GetReviewersByPRsRow{PrRepository: arg.PrRepositories[i], PrID: id, ReviewerID: r})
+		}
+	}
+	return out, nil
+}
+
+func status(s repo.PrStatusEnum) repo.NullPrStatusEnum {
+	return repo.NullPrStatusEnum{PrStatusEnum: s, Valid: true}
+}
+
+func TestHandler_BatchesTeamReviewGraph(t *testing.T) {
+	f := &fakeRepo{
+		users: []repo.User{
+			{UserID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+			{UserID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+			{UserID: "u3", Username: "Carol", IsActive: true, TeamName: "backend"},
+			{UserID: "u4", Username: "Dave", IsActive: true, TeamName: "frontend"},
+		},
+		prs: []repo.PullRequest{
+			{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1", Status: status(repo.PrStatusEnumOPEN), Repository: pgtype.Text{String: "search-api", Valid: true}, RepositoryKey: "search-api"},
+			{PullRequestID: "pr-2", PullRequestName: "Fix login", AuthorID: "u4", Status: status(repo.PrStatusEnumOPEN)},
+			{PullRequestID: "pr-3", PullRequestName: "Old one", AuthorID: "u4", Status: status(repo.PrStatusEnumMERGED)},
+		},
+		assignments: map[domain.PRKey][]string{
+			{Repository: "search-api", PullRequestID: "pr-1"}: {"u2", "u3"},
+			{PullRequestID: "pr-2"}:                           {"u1", "u4"},
+			{PullRequestID: "pr-3"}:                           {"u2"},
+		},
+	}
+
+	query := `{
+		team(name: "backend") {
+			name
+			members {
+				id
+				reviews(status: OPEN) {
+					id
+					repository
+					author { username }
+					reviewers { id username }
+				}
+			}
+		}
+	}`
+	body, err := json.Marshal(Request{Query: query})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	NewHandler(f).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data": {"team": {
+		"name": "backend",
+		"members": [
+			{"id": "u1", "reviews": [{"id": "pr-2", "repository": null, "author": {"username": "Dave"}, "reviewers": [{"id": "u1", "username": "Alice"}, {"id": "u4", "username": "Dave"}]}]},
+			{"id": "u2", "reviews": [{"id": "pr-1", "repository": "search-api", "author": {"username": "Alice"}, "reviewers": [{"id": "u2", "username": "Bob"}, {"id": "u3", "username": "Carol"}]}]},
+			{"id": "u3", "reviews": [{"id": "pr-1", "repository": "search-api", "author": {"username": "Alice"}, "reviewers": [{"id": "u2", "username": "Bob"}, {"id": "u3", "username": "Carol"}]}]}
+		]
+	}}}`, rec.Body.String())
+
+	// one query per level, not per member or per PR
+	assert.Equal(t, [][]string{{"backend"}}, f.calls["GetUsersByTeams"])
+	assert.Equal(t, [][]string{{"u1", "u2", "u3"}}, f.calls["GetPRsByReviewers"])
+	assert.Equal(t, [][]string{{"pr-1", "pr-2"}}, f.calls["GetReviewersByPRs"])
+	// authors and reviewers are resolved at different depths, so users take one batch each
+	assert.Len(t, f.calls["GetUsersByIDs"], 2)
+	assert.ElementsMatch(t, []string{"u1", "u2", "u3", "u4"}, slices.Concat(f.calls["GetUsersByIDs"]...))
+}
+
+func TestHandler_PullRequestByRepository(t *testing.T) {
+	f := &fakeRepo{
+		users: []repo.User{
+			{UserID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"},
+			{UserID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"},
+			{UserID: "u3", Username: "Carol", IsActive: true, TeamName: "backend"},
+		},
+		prs: []repo.PullRequest{
+			{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1", Status: status(repo.PrStatusEnumOPEN), Repository: pgtype.Text{String: "search-api", Valid: true}, RepositoryKey: "search-api"},
+			{PullRequestID: "pr-1", PullRequestName: "Fix header", AuthorID: "u1", Status: status(repo.PrStatusEnumOPEN), Repository: pgtype.Text{String: "web", Valid: true}, RepositoryKey: "web"},
+			{PullRequestID: "pr-1", PullRequestName: "No repository", AuthorID: "u1", Status: status(repo.PrStatusEnumOPEN)},
+		},
+		assignments: map[domain.PRKey][]string{
+			{Repository: "search-api", PullRequestID: "pr-1"}: {"u2"},
+			{Repository: "web", PullRequestID: "pr-1"}:        {"u3"},
+		},
+	}
+
+	query := `{
+		web: pullRequest(id: "pr-1", repository: "web") { name repository reviewers { id } }
+		bare: pullRequest(id: "pr-1") { name repository reviewers { id } }
+		ghost: pullRequest(id: "pr-1", repository: "ghost") { name }
+	}`
+	body, err := json.Marshal(Request{Query: query})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	NewHandler(f).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"data": {
+		"web": {"name": "Fix header", "repository": "web", "reviewers": [{"id": "u3"}]},
+		"bare": {"name": "No repository", "repository": null, "reviewers": []},
+		"ghost": null
+	}}`, rec.Body.String())
+}
+
+func TestHandler_RejectsEmptyQuery(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": ""}`))
+	NewHandler(&fakeRepo{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"field":"query"`)
+}
@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// batchWait is how long a loader collects keys before fetching them.
+// Resolvers of list items run concurrently, so siblings land in one batch.
+const batchWait = 2 * time.Millisecond
+
+// loader batches and caches lookups by key, the same way dataloader does.
+// It lives for a single request, so cached values are never stale.
+type loader[K comparable, V any] struct {
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending map[K]*result[V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		fetch: fetch,
+		cache: make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value of key, a missing key yields the zero value.
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+		if l.pending == nil {
+			l.pending = make(map[K]*result[V])
+			time.AfterFunc(batchWait, func() { l.dispatch(ctx) })
+		}
+		l.pending[key] = res
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// LoadMany loads every key, the values keep the order of keys.
+func (l *loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(ctx, key)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func (l *loader[K, V]) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	keys := make([]K, 0, len(batch))
+	for key := range batch {
+		keys = append(keys, key)
+	}
+
+	values, err := l.fetch(ctx, keys)
+	for key, res := range batch {
+		res.value, res.err = values[key], err
+		close(res.done)
+	}
+}
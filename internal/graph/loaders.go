@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+)
+
+// loaders are created per request, each one issues a single query per batch.
+type loaders struct {
+	users     *loader[string, *repo.User]
+	members   *loader[string, []repo.User]
+	prs       *loader[domain.PRKey, *repo.PullRequest]
+	reviews   *loader[string, []repo.PullRequest]
+	reviewers *loader[domain.PRKey, []string]
+}
+
+type loadersKey struct{}
+
+func newLoaders(q repo.Querier) *loaders {
+	return &loaders{
+		users: newLoader(func(ctx context.Context, ids []string) (map[string]*repo.User, error) {
+			rows, err := q.GetUsersByIDs(ctx, ids)
+			if err != nil {
+				return nil, internal("failed to load users", err)
+			}
+			users := make(map[string]*repo.User, len(rows))
+			for i := range rows {
+				users[rows[i].UserID] = &rows[i]
+			}
+			return users, nil
+		}),
+		members: newLoader(func(ctx context.Context, teams []string) (map[string][]repo.User, error) {
+			rows, err := q.GetUsersByTeams(ctx, teams)
+			if err != nil {
+				return nil, internal("failed to load team members", err)
+			}
+			members := make(map[string][]repo.User)
+			for _, u := range rows {
+				members[u.TeamName] = append(members[u.TeamName], u)
+			}
+			return members, nil
+		}),
+		prs: newLoader(func(ctx context.Context, keys []domain.PRKey) (map[domain.PRKey]*repo.PullRequest, error) {
+			var arg repo.GetPRsByIDsParams
+			for _, k := range keys {
+				arg.PrRepositories = append(arg.PrRepositories, k.Repository)
+				arg.PrIds = append(arg.PrIds, k.PullRequestID)
+			}
+			rows, err := q.GetPRsByIDs(ctx, arg)
+			if err != nil {
+				return nil, internal("failed to load pull requests", err)
+			}
+			prs := make(map[domain.PRKey]*repo.PullRequest, len(rows))
+			for i := range rows {
+				prs[domain.PRKey{Repository: rows[i].RepositoryKey, PullRequestID: rows[i].PullRequestID}] = &rows[i]
+			}
+			return prs, nil
+		}),
+		reviews: newLoader(func(ctx context.Context, reviewerIDs []string) (map[string][]repo.PullRequest, error) {
+			rows, err := q.GetPRsByReviewers(ctx, reviewerIDs)
+			if err != nil {
+				return nil, internal("failed to load reviews", err)
+			}
+			reviews := make(map[string][]repo.PullRequest)
+			for _, r := range rows {
+				reviews[r.ReviewerID] = append(reviews[r.ReviewerID], repo.PullRequest{
+					PullRequestID:   r.PullRequestID,
+					PullRequestName: r.PullRequestName,
+					AuthorID:        r.AuthorID,
+					Status:          r.Status,
+					MergedAt:        r.MergedAt,
+					Repository:      r.Repository,
+					RepositoryKey:   r.RepositoryKey,
+					MissingSenior:   r.MissingSenior,
+				})
+			}
+			return reviews, nil
+		}),
+		reviewers: newLoader(func(ctx context.Context, keys []domain.PRKey) (map[domain.PRKey][]string, error) {
+			var arg repo.GetReviewersByPRsParams
+			for _, k := range keys {
+				arg.PrRepositories = append(arg.PrRepositories, k.Repository)
+				arg.PrIds = append(arg.PrIds, k.PullRequestID)
+			}
+			rows, err := q.GetReviewersByPRs(ctx, arg)
+			if err != nil {
+				return nil, internal("failed to load reviewers", err)
+			}
+			reviewers := make(map[domain.PRKey][]string)
+			for _, r := range rows {
+				key := domain.PRKey{Repository: r.PrRepository, PullRequestID: r.PrID}
+				reviewers[key] = append(reviewers[key], r.ReviewerID)
+			}
+			return reviewers, nil
+		}),
+	}
+}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// internal logs a repository error and hides it from the client.
+func internal(msg string, err error) error {
+	slog.Error(msg, "error", err)
+	return errors.InternalError
+}
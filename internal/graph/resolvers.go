@@ -0,0 +1,182 @@
+package graph
+
+import (
+	"context"
+	"slices"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/graph-gophers/graphql-go"
+)
+
+type rootResolver struct{}
+
+func (*rootResolver) Team(ctx context.Context, args struct{ Name string }) (*teamResolver, error) {
+	members, err := loadersFrom(ctx).members.Load(ctx, args.Name)
+	if err != nil || len(members) == 0 {
+		return nil, err
+	}
+
+	return &teamResolver{name: args.Name}, nil
+}
+
+func (*rootResolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	user, err := loadersFrom(ctx).users.Load(ctx, string(args.ID))
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	return &userResolver{user: *user}, nil
+}
+
+func (*rootResolver) PullRequest(ctx context.Context, args struct {
+	ID         graphql.ID
+	Repository *string
+}) (*pullRequestResolver, error) {
+	key := domain.PRKey{PullRequestID: string(args.ID)}
+	if args.Repository != nil {
+		key.Repository = *args.Repository
+	}
+	pr, err := loadersFrom(ctx).prs.Load(ctx, key)
+	if err != nil || pr == nil {
+		return nil, err
+	}
+
+	return &pullRequestResolver{pr: *pr}, nil
+}
+
+type teamResolver struct {
+	name string
+}
+
+func (t *teamResolver) Name() string {
+	return t.name
+}
+
+func (t *teamResolver) Members(ctx context.Context) ([]*userResolver, error) {
+	members, err := loadersFrom(ctx).members.Load(ctx, t.name)
+	if err != nil {
+		return nil, err
+	}
+
+	return userResolvers(members), nil
+}
+
+type userResolver struct {
+	user repo.User
+}
+
+func userResolvers(users []repo.User) []*userResolver {
+	resolvers := make([]*userResolver, len(users))
+	for i, u := range users {
+		resolvers[i] = &userResolver{user: u}
+	}
+
+	return resolvers
+}
+
+func (u *userResolver) ID() graphql.ID {
+	return graphql.ID(u.user.UserID)
+}
+
+func (u *userResolver) Username() string {
+	return u.user.Username
+}
+
+func (u *userResolver) IsActive() bool {
+	return u.user.IsActive
+}
+
+func (u *userResolver) Team() *teamResolver {
+	return &teamResolver{name: u.user.TeamName}
+}
+
+func (u *userResolver) Reviews(ctx context.Context, args struct{ Status *string }) ([]*pullRequestResolver, error) {
+	prs, err := loadersFrom(ctx).reviews.Load(ctx, u.user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*pullRequestResolver, 0, len(prs))
+	for _, pr := range prs {
+		r := &pullRequestResolver{pr: pr}
+		if args.Status != nil && r.Status() != *args.Status {
+			continue
+		}
+		resolvers = append(resolvers, r)
+	}
+
+	return resolvers, nil
+}
+
+type pullRequestResolver struct {
+	pr repo.PullRequest
+}
+
+func (p *pullRequestResolver) ID() graphql.ID {
+	return graphql.ID(p.pr.PullRequestID)
+}
+
+func (p *pullRequestResolver) Name() string {
+	return p.pr.PullRequestName
+}
+
+func (p *pullRequestResolver) Status() string {
+	if !p.pr.Status.Valid {
+		return string(repo.PrStatusEnumOPEN)
+	}
+
+	return string(p.pr.Status.PrStatusEnum)
+}
+
+func (p *pullRequestResolver) Repository() *string {
+	if !p.pr.Repository.Valid {
+		return nil
+	}
+
+	return &p.pr.Repository.String
+}
+
+func (p *pullRequestResolver) MissingSenior() bool {
+	return p.pr.MissingSenior
+}
+
+func (p *pullRequestResolver) Author(ctx context.Context) (*userResolver, error) {
+	author, err := loadersFrom(ctx).users.Load(ctx, p.pr.AuthorID)
+	if err != nil || author == nil {
+		return nil, err
+	}
+
+	return &userResolver{user: *author}, nil
+}
+
+func (p *pullRequestResolver) Reviewers(ctx context.Context) ([]*userResolver, error) {
+	l := loadersFrom(ctx)
+
+	ids, err := l.reviewers.Load(ctx, domain.PRKey{Repository: p.pr.RepositoryKey, PullRequestID: p.pr.PullRequestID})
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := l.users.LoadMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// a reviewer removed from the users table is skipped
+	users = slices.DeleteFunc(users, func(u *repo.User) bool { return u == nil })
+	resolvers := make([]*userResolver, len(users))
+	for i, u := range users {
+		resolvers[i] = &userResolver{user: *u}
+	}
+
+	return resolvers, nil
+}
+
+func (p *pullRequestResolver) MergedAt() *graphql.Time {
+	if !p.pr.MergedAt.Valid {
+		return nil
+	}
+
+	return &graphql.Time{Time: p.pr.MergedAt.Time}
+}
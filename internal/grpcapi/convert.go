@@ -0,0 +1,43 @@
+package grpcapi
+
+import (
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+)
+
+func teamToProto(teamName string, users []repo.User) *reviewerv1.Team {
+	members := make([]*reviewerv1.TeamMember, len(users))
+	for i, u := range users {
+		members[i] = &reviewerv1.TeamMember{
+			UserId:   u.UserID,
+			Username: u.Username,
+			IsActive: u.IsActive,
+		}
+	}
+
+	return &reviewerv1.Team{TeamName: teamName, Members: members}
+}
+
+func prToProto(p domain.PRWithReviewers) *reviewerv1.PullRequest {
+	return &reviewerv1.PullRequest{
+		PullRequestId:     p.PullRequestID,
+		PullRequestName:   p.PullRequestName,
+		AuthorId:          p.AuthorID,
+		Status:            statusToProto(p.Status),
+		AssignedReviewers: p.AssignedReviewers,
+		Repository:        p.Repository,
+		MissingSenior:     p.MissingSenior,
+	}
+}
+
+func statusToProto(s string) reviewerv1.PullRequestStatus {
+	switch s {
+	case "OPEN":
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case "MERGED":
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
@@ -0,0 +1,59 @@
+package grpcapi
+
+import (
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain is the ErrorInfo domain of application errors.
+const errorDomain = "reviewer.v1"
+
+// statusCodes maps AppError codes to gRPC status codes.
+var statusCodes = map[string]codes.Code{
+	errors.ErrTeamExists.Code:   codes.AlreadyExists,
+	errors.ErrPRExists.Code:     codes.AlreadyExists,
+	errors.ErrPRMerged.Code:     codes.FailedPrecondition,
+	errors.ErrNotAssigned.Code:  codes.FailedPrecondition,
+	errors.ErrNoCandidate.Code:  codes.FailedPrecondition,
+	errors.ErrNotFound.Code:     codes.NotFound,
+	errors.ErrRateLimited.Code:  codes.ResourceExhausted,
+	errors.ErrInvalidInput.Code: codes.InvalidArgument,
+	errors.InternalError.Code:   codes.Internal,
+}
+
+// toStatus converts an error into a gRPC status error.
+// The AppError code is kept as ErrorInfo.Reason, so clients can tell
+// e.g. PR_MERGED from NOT_ASSIGNED, and field errors become a BadRequest.
+// Other errors are converted with errors.From.
+func toStatus(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	appErr := errors.From(err)
+
+	code, ok := statusCodes[appErr.Code]
+	if !ok {
+		code = codes.Internal
+	}
+
+	st := status.New(code, appErr.Message)
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: appErr.Code, Domain: errorDomain}}
+	if len(appErr.Details) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(appErr.Details))
+		for i, d := range appErr.Details {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: d.Field, Description: d.Reason}
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
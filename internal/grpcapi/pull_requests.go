@@ -0,0 +1,79 @@
+package grpcapi
+
+import (
+	"context"
+
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/pr"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+)
+
+type pullRequestsServer struct {
+	reviewerv1.UnimplementedPullRequestsServiceServer
+
+	service pr.Service
+}
+
+func (s *pullRequestsServer) CreatePullRequest(ctx context.Context, req *reviewerv1.CreatePullRequestRequest) (*reviewerv1.CreatePullRequestResponse, error) {
+	response, err := s.service.CreatePR(ctx, pr.CreatePRRequest{
+		CreatePRParams: repo.CreatePRParams{
+			PullRequestID:   req.GetPullRequestId(),
+			PullRequestName: req.GetPullRequestName(),
+			AuthorID:        req.GetAuthorId(),
+		},
+		Repository:   req.GetRepository(),
+		ChangedFiles: req.GetChangedFiles(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reviewerv1.CreatePullRequestResponse{
+		Pr:                prToProto(response.PR),
+		OwnerReviewers:    response.OwnerReviewers,
+		FallbackReviewers: response.FallbackReviewers,
+	}, nil
+}
+
+func (s *pullRequestsServer) MergePullRequest(ctx context.Context, req *reviewerv1.MergePullRequestRequest) (*reviewerv1.MergePullRequestResponse, error) {
+	response, err := s.service.MergePR(ctx, domain.PRKey{Repository: req.GetRepository(), PullRequestID: req.GetPullRequestId()})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reviewerv1.MergePullRequestResponse{Pr: prToProto(response.PR)}, nil
+}
+
+func (s *pullRequestsServer) ReassignReviewer(ctx context.Context, req *reviewerv1.ReassignReviewerRequest) (*reviewerv1.ReassignReviewerResponse, error) {
+	response, err := s.service.ReassignReviewer(ctx, domain.PRKey{Repository: req.GetRepository(), PullRequestID: req.GetPullRequestId()}, req.GetOldUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	return &reviewerv1.ReassignReviewerResponse{
+		Pr:         prToProto(response.PR),
+		ReplacedBy: response.ReplacedBy,
+	}, nil
+}
+
+func (s *pullRequestsServer) GetUserReviews(ctx context.Context, req *reviewerv1.GetUserReviewsRequest) (*reviewerv1.GetUserReviewsResponse, error) {
+	response, err := s.service.GetUserReviews(ctx, req.GetUserId(), req.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]*reviewerv1.PullRequestShort, len(response.PullRequests))
+	for i, p := range response.PullRequests {
+		prs[i] = &reviewerv1.PullRequestShort{
+			PullRequestId:   p.PullRequestID,
+			PullRequestName: p.PullRequestName,
+			AuthorId:        p.AuthorID,
+			Status:          statusToProto(p.Status),
+			Repository:      p.Repository,
+			MissingSenior:   p.MissingSenior,
+		}
+	}
+
+	return &reviewerv1.GetUserReviewsResponse{UserId: response.UserID, PullRequests: prs}, nil
+}
@@ -0,0 +1,109 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RateLimit limits calls with the limiter of the HTTP API. A service shares the
+// buckets of its HTTP route group, so a client gets the same quota on both ports.
+type RateLimit struct {
+	Limiter ratelimit.Limiter
+	Clients *ratelimit.Clients
+	// LimitFor returns the limit of a route group.
+	LimitFor func(group string) ratelimit.Limit
+}
+
+// serviceGroups maps the services to their HTTP route groups.
+var serviceGroups = map[string]string{
+	reviewerv1.TeamsService_ServiceDesc.ServiceName:        "team",
+	reviewerv1.UsersService_ServiceDesc.ServiceName:        "users",
+	reviewerv1.PullRequestsService_ServiceDesc.ServiceName: "pullRequest",
+	reviewerv1.StatsService_ServiceDesc.ServiceName:        "stats",
+}
+
+// interceptor answers RESOURCE_EXHAUSTED with a RetryInfo and a retry-after
+// header when the client is over the limit of the service's group. Calls of
+// other services (health, reflection) are not limited. Errors of the limiter
+// backend are logged and the call is let through, as over HTTP.
+func (rl *RateLimit) interceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	service, _, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+	group, ok := serviceGroups[service]
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	key := group + ":" + rl.clientKey(ctx)
+	decision, err := rl.Limiter.Allow(ctx, key, rl.LimitFor(group))
+	if err != nil {
+		slog.Error("rate limiter failed, letting the call through", "group", group, "error", err)
+		return handler(ctx, req)
+	}
+	if !decision.Allowed {
+		retryAfter := ratelimit.RetryAfterSeconds(decision.RetryAfter)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+		return nil, rateLimited(time.Duration(retryAfter) * time.Second)
+	}
+
+	return handler(ctx, req)
+}
+
+// clientKey identifies the caller like the HTTP middleware does: by a known API
+// key from the metadata, otherwise by the peer IP, forwarded metadata is only
+// trusted from the configured proxies.
+func (rl *RateLimit) clientKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+	ip := rl.Clients.ClientIP(remoteAddr, md.Get("x-forwarded-for"), first("x-real-ip"))
+
+	apiKey := ""
+	if header := rl.Clients.KeyHeader(); header != "" {
+		apiKey = first(strings.ToLower(header))
+	}
+
+	return rl.Clients.Key(apiKey, ip)
+}
+
+// rateLimited is the RATE_LIMITED status with a RetryInfo.
+func rateLimited(retryAfter time.Duration) error {
+	st := status.Convert(toStatus(errors.ErrRateLimited))
+	withRetry, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)})
+	if err != nil {
+		return st.Err()
+	}
+
+	return withRetry.Err()
+}
+
+// interceptors returns the unary interceptors of the server.
+func (o Options) interceptors() []grpc.UnaryServerInterceptor {
+	chain := []grpc.UnaryServerInterceptor{recoverer, errorMapper}
+	if o.RateLimit != nil && o.RateLimit.Limiter != nil {
+		chain = append(chain, o.RateLimit.interceptor)
+	}
+
+	return chain
+}
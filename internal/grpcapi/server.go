@@ -0,0 +1,139 @@
+// Package grpcapi serves the API over gRPC on top of the same services as the HTTP handlers.
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/pr"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/stats"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/users"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Services are the application services exposed over gRPC.
+type Services struct {
+	Teams        teams.Service
+	Users        users.Service
+	PullRequests pr.Service
+	// Stats is nil when the stats feature is disabled, StatsService is not registered then.
+	Stats stats.Service
+	// MassDeactivation enables TeamsService.DeactivateUsers.
+	MassDeactivation bool
+}
+
+// Options are the limits the HTTP API also applies to its requests.
+type Options struct {
+	// MaxMessageBytes limits the size of a request message, like http.max_body_bytes.
+	// Zero keeps the gRPC default of 4 MiB.
+	MaxMessageBytes int
+	// RateLimit is nil when rate limiting is disabled.
+	RateLimit *RateLimit
+}
+
+// Server is a gRPC server with health checking and reflection.
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+// NewServer registers every service on a new gRPC server.
+func NewServer(s Services, opts Options) *Server {
+	serverOpts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(opts.interceptors()...)}
+	if opts.MaxMessageBytes > 0 {
+		serverOpts = append(serverOpts, grpc.MaxRecvMsgSize(opts.MaxMessageBytes))
+	}
+	srv := grpc.NewServer(serverOpts...)
+
+	names := []string{
+		reviewerv1.TeamsService_ServiceDesc.ServiceName,
+		reviewerv1.UsersService_ServiceDesc.ServiceName,
+		reviewerv1.PullRequestsService_ServiceDesc.ServiceName,
+	}
+	reviewerv1.RegisterTeamsServiceServer(srv, &teamsServer{service: s.Teams, massDeactivation: s.MassDeactivation})
+	reviewerv1.RegisterUsersServiceServer(srv, &usersServer{service: s.Users})
+	reviewerv1.RegisterPullRequestsServiceServer(srv, &pullRequestsServer{service: s.PullRequests})
+	if s.Stats != nil {
+		reviewerv1.RegisterStatsServiceServer(srv, &statsServer{service: s.Stats})
+		names = append(names, reviewerv1.StatsService_ServiceDesc.ServiceName)
+	}
+
+	// the empty name is the status of the whole server
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	for _, name := range names {
+		hs.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(srv, hs)
+
+	reflection.Register(srv)
+
+	return &Server{grpc: srv, health: hs}
+}
+
+// Serve accepts connections on ln until Stop is called.
+func (s *Server) Serve(ln net.Listener) error {
+	return s.grpc.Serve(ln)
+}
+
+// Stop reports NOT_SERVING to health checks and waits for in-flight calls
+// to finish. Calls still running when ctx is done are cancelled.
+// It reports whether every call finished in time.
+func (s *Server) Stop(ctx context.Context) bool {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		s.grpc.Stop()
+		<-done
+		return false
+	}
+}
+
+// Close stops the server immediately, cancelling in-flight calls.
+func (s *Server) Close() {
+	s.health.Shutdown()
+	s.grpc.Stop()
+}
+
+// recoverer turns a panic in a handler into an internal error.
+func recoverer(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("panic in gRPC handler", "method", info.FullMethod, "panic", p)
+			err = toStatus(errors.InternalError)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// errorMapper logs the call and converts application errors to gRPC statuses.
+func errorMapper(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	started := time.Now()
+	resp, err := handler(ctx, req)
+	if err != nil {
+		err = toStatus(err)
+		slog.Error("gRPC call failed", "method", info.FullMethod, "error", err, "duration", time.Since(started))
+		return nil, err
+	}
+
+	slog.Debug("gRPC call", "method", info.FullMethod, "duration", time.Since(started))
+	return resp, nil
+}
@@ -0,0 +1,265 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/pr"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/ratelimit"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakePRService struct {
+	pr.Service
+}
+
+func (fakePRService) CreatePR(_ context.Context, p pr.CreatePRRequest) (pr.CreatePRResponse, error) {
+	if p.AuthorID == "" {
+		return pr.CreatePRResponse{}, errors.InvalidField("author_id", "must not be empty")
+	}
+
+	return pr.CreatePRResponse{PR: domain.PRWithReviewers{
+		PullRequestID:     p.PullRequestID,
+		PullRequestName:   p.PullRequestName,
+		AuthorID:          p.AuthorID,
+		Status:            "OPEN",
+		AssignedReviewers: []string{"u2", "u3"},
+	}}, nil
+}
+
+func (fakePRService) ReassignReviewer(context.Context, domain.PRKey, string) (pr.ReassignResponse, error) {
+	return pr.ReassignResponse{}, errors.ErrPRMerged
+}
+
+func dial(t *testing.T, s Services, opts Options) *grpc.ClientConn {
+	t.Helper()
+
+	ln := bufconn.Listen(1 << 20)
+	srv := NewServer(s, opts)
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Close)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func TestPullRequests_MapsAppErrorsToStatus(t *testing.T) {
+	client := reviewerv1.NewPullRequestsServiceClient(dial(t, Services{PullRequests: fakePRService{}}, Options{}))
+	ctx := context.Background()
+
+	resp, err := client.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Add search",
+		AuthorId:        "u1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN, resp.GetPr().GetStatus())
+	assert.Equal(t, []string{"u2", "u3"}, resp.GetPr().GetAssignedReviewers())
+
+	_, err = client.ReassignReviewer(ctx, &reviewerv1.ReassignReviewerRequest{PullRequestId: "pr-1", OldUserId: "u2"})
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, "PR_MERGED", st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+
+	_, err = client.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{PullRequestId: "pr-2"})
+	st = status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 2)
+	violations := st.Details()[1].(*errdetails.BadRequest).GetFieldViolations()
+	require.Len(t, violations, 1)
+	assert.Equal(t, "author_id", violations[0].GetField())
+}
+
+// echoPRService answers with the repository and files it was asked about.
+type echoPRService struct {
+	pr.Service
+}
+
+func (echoPRService) CreatePR(_ context.Context, p pr.CreatePRRequest) (pr.CreatePRResponse, error) {
+	return pr.CreatePRResponse{
+		PR: domain.PRWithReviewers{
+			PullRequestID:     p.PullRequestID,
+			Status:            "OPEN",
+			Repository:        p.Repository,
+			AssignedReviewers: []string{"owner", "helper"},
+			MissingSenior:     true,
+		},
+		OwnerReviewers:    p.ChangedFiles[:1],
+		FallbackReviewers: []string{"helper"},
+	}, nil
+}
+
+func (echoPRService) GetUserReviews(_ context.Context, userID, repository string) (pr.UserReviewsResponse, error) {
+	return pr.UserReviewsResponse{UserID: userID, PullRequests: []pr.Short{
+		{PullRequestID: "pr-1", Status: "OPEN", Repository: repository, MissingSenior: true},
+	}}, nil
+}
+
+func TestPullRequests_Repositories(t *testing.T) {
+	client := reviewerv1.NewPullRequestsServiceClient(dial(t, Services{PullRequests: echoPRService{}}, Options{}))
+	ctx := context.Background()
+
+	created, err := client.CreatePullRequest(ctx, &reviewerv1.CreatePullRequestRequest{
+		PullRequestId: "pr-1",
+		AuthorId:      "u1",
+		Repository:    "backend",
+		ChangedFiles:  []string{"owner", "README.md"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "backend", created.GetPr().GetRepository())
+	assert.True(t, created.GetPr().GetMissingSenior())
+	assert.Equal(t, []string{"owner"}, created.GetOwnerReviewers())
+	assert.Equal(t, []string{"helper"}, created.GetFallbackReviewers())
+
+	reviews, err := client.GetUserReviews(ctx, &reviewerv1.GetUserReviewsRequest{UserId: "u2", Repository: "backend"})
+	require.NoError(t, err)
+	require.Len(t, reviews.GetPullRequests(), 1)
+	assert.Equal(t, "backend", reviews.GetPullRequests()[0].GetRepository())
+	assert.True(t, reviews.GetPullRequests()[0].GetMissingSenior())
+}
+
+// reportTeamsService reports a deactivation plan and echoes dryRun.
+type reportTeamsService struct {
+	teams.Service
+}
+
+func (reportTeamsService) DeactivateUsers(_ context.Context, userIDs []string, dryRun bool) (teams.DeactivateUsersResponse, error) {
+	return teams.DeactivateUsersResponse{
+		UpdatedPRs: []domain.PRWithReviewers{{PullRequestID: "pr-1", Status: "OPEN", AssignedReviewers: []string{"u3"}}},
+		Reassignments: []teams.Reassignment{
+			{PullRequestID: "pr-1", OldReviewerID: userIDs[0], NewReviewerID: "u3"},
+			{PullRequestID: "pr-2", OldReviewerID: userIDs[0]},
+		},
+		Users: []*teams.UserReport{{
+			UserID:       userIDs[0],
+			Reassigned:   []string{"pr-1"},
+			LostReviewer: []string{"pr-2"},
+			Skipped:      []string{"pr-3"},
+		}},
+		DryRun: dryRun,
+	}, nil
+}
+
+func TestTeams_DeactivateUsersReport(t *testing.T) {
+	client := reviewerv1.NewTeamsServiceClient(dial(t, Services{Teams: reportTeamsService{}, MassDeactivation: true}, Options{}))
+
+	for _, dryRun := range []bool{true, false} {
+		resp, err := client.DeactivateUsers(context.Background(), &reviewerv1.DeactivateUsersRequest{Users: []string{"u2"}, DryRun: dryRun})
+		require.NoError(t, err)
+		assert.Equal(t, dryRun, resp.GetDryRun())
+		require.Len(t, resp.GetUpdatedPrs(), 1)
+		assert.Equal(t, []string{"u3"}, resp.GetUpdatedPrs()[0].GetAssignedReviewers())
+
+		require.Len(t, resp.GetReassignments(), 2)
+		assert.Equal(t, "u3", resp.GetReassignments()[0].GetNewReviewerId())
+		assert.Equal(t, "pr-2", resp.GetReassignments()[1].GetPullRequestId())
+		assert.Empty(t, resp.GetReassignments()[1].GetNewReviewerId())
+
+		require.Len(t, resp.GetUsers(), 1)
+		report := resp.GetUsers()[0]
+		assert.Equal(t, "u2", report.GetUserId())
+		assert.Equal(t, []string{"pr-1"}, report.GetReassigned())
+		assert.Equal(t, []string{"pr-2"}, report.GetLostReviewer())
+		assert.Equal(t, []string{"pr-3"}, report.GetSkipped())
+	}
+}
+
+func TestServer_HealthAndDisabledFeatures(t *testing.T) {
+	conn := dial(t, Services{PullRequests: fakePRService{}}, Options{})
+	ctx := context.Background()
+
+	health := healthpb.NewHealthClient(conn)
+	resp, err := health.Check(ctx, &healthpb.HealthCheckRequest{Service: reviewerv1.PullRequestsService_ServiceDesc.ServiceName})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+
+	// stats is disabled, so the service is neither registered nor reported
+	_, err = health.Check(ctx, &healthpb.HealthCheckRequest{Service: reviewerv1.StatsService_ServiceDesc.ServiceName})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = reviewerv1.NewStatsServiceClient(conn).GetStats(ctx, &reviewerv1.GetStatsRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = reviewerv1.NewTeamsServiceClient(conn).DeactivateUsers(ctx, &reviewerv1.DeactivateUsersRequest{Users: []string{"u1"}})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	clients, err := ratelimit.NewClients("X-API-Key", []string{"known"}, nil)
+	require.NoError(t, err)
+	conn := dial(t, Services{PullRequests: fakePRService{}}, Options{RateLimit: &RateLimit{
+		Limiter:  ratelimit.NewMemory(),
+		Clients:  clients,
+		LimitFor: func(string) ratelimit.Limit { return ratelimit.Limit{RPS: 0.1, Burst: 1} },
+	}})
+	client := reviewerv1.NewPullRequestsServiceClient(conn)
+	req := &reviewerv1.CreatePullRequestRequest{PullRequestId: "pr-1", PullRequestName: "Add search", AuthorId: "u1"}
+	ctx := context.Background()
+
+	_, err = client.CreatePullRequest(ctx, req)
+	require.NoError(t, err)
+
+	var header metadata.MD
+	_, err = client.CreatePullRequest(ctx, req, grpc.Header(&header))
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 2)
+	assert.Equal(t, "RATE_LIMITED", st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+	assert.Equal(t, 10*time.Second, st.Details()[1].(*errdetails.RetryInfo).GetRetryDelay().AsDuration())
+	assert.Equal(t, []string{"10"}, header.Get("retry-after"))
+
+	// an unknown API key does not get a fresh bucket, a known one does
+	unknown := metadata.AppendToOutgoingContext(ctx, "x-api-key", "bot")
+	_, err = client.CreatePullRequest(unknown, req)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	known := metadata.AppendToOutgoingContext(ctx, "x-api-key", "known")
+	_, err = client.CreatePullRequest(known, req)
+	require.NoError(t, err)
+
+	// health checks are not limited
+	for range 3 {
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+	}
+}
+
+func TestServer_MaxMessageBytes(t *testing.T) {
+	client := reviewerv1.NewPullRequestsServiceClient(dial(t, Services{PullRequests: fakePRService{}}, Options{MaxMessageBytes: 64}))
+
+	_, err := client.CreatePullRequest(context.Background(), &reviewerv1.CreatePullRequestRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: strings.Repeat("x", 100),
+		AuthorId:        "u1",
+	})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestToStatus_UnknownErrorIsInternal(t *testing.T) {
+	st := status.Convert(toStatus(assert.AnError))
+	assert.Equal(t, codes.Internal, st.Code())
+	assert.Equal(t, errors.InternalError.Message, st.Message())
+}
@@ -0,0 +1,36 @@
+package grpcapi
+
+import (
+	"context"
+
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/stats"
+)
+
+type statsServer struct {
+	reviewerv1.UnimplementedStatsServiceServer
+
+	service stats.Service
+}
+
+func (s *statsServer) GetStats(ctx context.Context, req *reviewerv1.GetStatsRequest) (*reviewerv1.GetStatsResponse, error) {
+	response, err := s.service.GetStats(ctx, req.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := make([]*reviewerv1.ReviewerStat, len(response.TopReviewers))
+	for i, r := range response.TopReviewers {
+		reviewers[i] = &reviewerv1.ReviewerStat{ReviewerId: r.ReviewerID, AssignmentCount: r.AssignmentCount}
+	}
+	statuses := make([]*reviewerv1.PRStatusStat, len(response.PRStatus))
+	for i, st := range response.PRStatus {
+		statuses[i] = &reviewerv1.PRStatusStat{Status: st.Status, Count: st.Count}
+	}
+
+	return &reviewerv1.GetStatsResponse{
+		TopReviewers:         reviewers,
+		PrStatusDistribution: statuses,
+		TotalActiveUsers:     response.TotalActiveUsers,
+	}, nil
+}
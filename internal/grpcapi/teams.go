@@ -0,0 +1,112 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type teamsServer struct {
+	reviewerv1.UnimplementedTeamsServiceServer
+
+	service          teams.Service
+	massDeactivation bool
+}
+
+func (s *teamsServer) GetTeam(ctx context.Context, req *reviewerv1.GetTeamRequest) (*reviewerv1.GetTeamResponse, error) {
+	if req.GetTeamName() == "" {
+		return nil, errors.InvalidField("team_name", "must not be empty")
+	}
+
+	users, err := s.service.GetTeamByName(ctx, req.GetTeamName())
+	if err != nil || len(users) == 0 {
+		return nil, errors.ErrNotFound
+	}
+
+	return &reviewerv1.GetTeamResponse{Team: teamToProto(req.GetTeamName(), users)}, nil
+}
+
+func (s *teamsServer) CreateTeam(ctx context.Context, req *reviewerv1.CreateTeamRequest) (*reviewerv1.CreateTeamResponse, error) {
+	if req.GetTeam() == nil {
+		return nil, errors.InvalidField("team", "is required")
+	}
+
+	params := teams.CreateTeamParams{
+		TeamName: req.GetTeam().GetTeamName(),
+		Members:  make([]teams.MemberParams, len(req.GetTeam().GetMembers())),
+	}
+	for i, m := range req.GetTeam().GetMembers() {
+		params.Members[i] = teams.MemberParams{
+			UserID:   m.GetUserId(),
+			Username: m.GetUsername(),
+			IsActive: m.GetIsActive(),
+		}
+	}
+
+	users, err := s.service.CreateTeam(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reviewerv1.CreateTeamResponse{Team: teamToProto(params.TeamName, users)}, nil
+}
+
+func (s *teamsServer) DeactivateUsers(ctx context.Context, req *reviewerv1.DeactivateUsersRequest) (*reviewerv1.DeactivateUsersResponse, error) {
+	if !s.massDeactivation {
+		return nil, status.Error(codes.Unimplemented, "mass deactivation is disabled")
+	}
+
+	if len(req.GetUsers()) == 0 {
+		return nil, errors.InvalidField("users", "must contain at least one user id")
+	}
+	if len(req.GetUsers()) > teams.MaxDeactivateUsers {
+		return nil, errors.InvalidField("users", fmt.Sprintf("must contain at most %d user ids", teams.MaxDeactivateUsers))
+	}
+	for i, uid := range req.GetUsers() {
+		if uid == "" {
+			return nil, errors.InvalidField(fmt.Sprintf("users[%d]", i), "must not be empty")
+		}
+	}
+
+	response, err := s.service.DeactivateUsers(ctx, req.GetUsers(), req.GetDryRun())
+	if err != nil {
+		return nil, err
+	}
+
+	updated := make([]*reviewerv1.PullRequest, len(response.UpdatedPRs))
+	for i, p := range response.UpdatedPRs {
+		updated[i] = prToProto(p)
+	}
+
+	reassignments := make([]*reviewerv1.Reassignment, len(response.Reassignments))
+	for i, r := range response.Reassignments {
+		reassignments[i] = &reviewerv1.Reassignment{
+			PullRequestId: r.PullRequestID,
+			OldReviewerId: r.OldReviewerID,
+			NewReviewerId: r.NewReviewerID,
+			Repository:    r.Repository,
+		}
+	}
+
+	users := make([]*reviewerv1.DeactivatedUser, len(response.Users))
+	for i, u := range response.Users {
+		users[i] = &reviewerv1.DeactivatedUser{
+			UserId:       u.UserID,
+			Reassigned:   u.Reassigned,
+			LostReviewer: u.LostReviewer,
+			Skipped:      u.Skipped,
+		}
+	}
+
+	return &reviewerv1.DeactivateUsersResponse{
+		UpdatedPrs:    updated,
+		Reassignments: reassignments,
+		Users:         users,
+		DryRun:        response.DryRun,
+	}, nil
+}
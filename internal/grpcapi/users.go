@@ -0,0 +1,37 @@
+package grpcapi
+
+import (
+	"context"
+
+	reviewerv1 "github.com/Joskmo/avito-trainee-assignment-api/api/reviewer/v1"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/users"
+)
+
+type usersServer struct {
+	reviewerv1.UnimplementedUsersServiceServer
+
+	service users.Service
+}
+
+func (s *usersServer) SetIsActive(ctx context.Context, req *reviewerv1.SetIsActiveRequest) (*reviewerv1.SetIsActiveResponse, error) {
+	if req.IsActive == nil {
+		return nil, errors.InvalidField("is_active", "is required")
+	}
+
+	user, err := s.service.SetUserActivity(ctx, repo.SetUserActivityParams{
+		UserID:   req.GetUserId(),
+		IsActive: req.GetIsActive(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reviewerv1.SetIsActiveResponse{User: &reviewerv1.User{
+		UserId:   user.UserID,
+		Username: user.Username,
+		TeamName: user.TeamName,
+		IsActive: user.IsActive,
+	}}, nil
+}
@@ -3,7 +3,13 @@ package json
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
 // Write sends a JSON response with the given status code and data.
@@ -14,9 +20,179 @@ func Write(w http.ResponseWriter, status int, data any) {
 }
 
 // Read decodes the JSON body of the request into the given data structure.
+// The body must contain a single JSON value, the size limit is set by the
+// caller with http.MaxBytesReader. Decoding failures are returned as *DecodeError.
 func Read(r *http.Request, data any) error {
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
 
-	return decoder.Decode(data)
+	if err := decoder.Decode(data); err != nil {
+		return newDecodeError(err)
+	}
+
+	// the body must hold exactly one JSON value
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return newDecodeError(err)
+		}
+		return &DecodeError{Field: "body", Reason: "unexpected data after the JSON value", Err: err}
+	}
+
+	// "\u0000" is valid JSON, but PostgreSQL cannot store it
+	if path, ok := findNUL(reflect.ValueOf(data), nil); ok {
+		return &DecodeError{Field: FieldPath(path), Reason: "must not contain NUL characters"}
+	}
+
+	return nil
+}
+
+// findNUL returns the path of the first string in v that contains a NUL byte.
+func findNUL(v reflect.Value, path []string) ([]string, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil, false
+		}
+		return findNUL(v.Elem(), path)
+	case reflect.String:
+		return path, strings.IndexByte(v.String(), 0) >= 0
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if p, ok := findNUL(v.Index(i), append(path, strconv.Itoa(i))); ok {
+				return p, true
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			if strings.IndexByte(key, 0) >= 0 {
+				return append(path, key), true
+			}
+			if p, ok := findNUL(iter.Value(), append(path, key)); ok {
+				return p, true
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if name == "" {
+				name = t.Field(i).Name
+			}
+			if p, ok := findNUL(v.Field(i), append(path, name)); ok {
+				return p, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// DecodeError describes which field of the request body could not be decoded and why.
+type DecodeError struct {
+	Field  string
+	Reason string
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func newDecodeError(err error) *DecodeError {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		tooLarge  *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &tooLarge):
+		return &DecodeError{Field: "body", Reason: fmt.Sprintf("must not exceed %d bytes", tooLarge.Limit), Err: err}
+	case errors.Is(err, io.EOF):
+		return &DecodeError{Field: "body", Reason: "request body is empty", Err: err}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &DecodeError{Field: "body", Reason: "unexpected end of JSON", Err: err}
+	case errors.As(err, &syntaxErr):
+		return &DecodeError{
+			Field:  "body",
+			Reason: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset),
+			Err:    err,
+		}
+	case errors.As(err, &typeErr):
+		var path []string
+		if typeErr.Field != "" {
+			path = strings.Split(typeErr.Field, ".")
+		}
+		return &DecodeError{
+			Field:  FieldPath(path),
+			Reason: fmt.Sprintf("must be %s, got %s", describeType(typeErr.Type), typeErr.Value),
+			Err:    err,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for DisallowUnknownFields
+		field, uerr := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		if uerr != nil || field == "" {
+			return &DecodeError{Field: "body", Reason: "unknown field " + strconv.Quote(field), Err: err}
+		}
+		return &DecodeError{Field: field, Reason: "unknown field", Err: err}
+	default:
+		return &DecodeError{Field: "body", Reason: err.Error(), Err: err}
+	}
+}
+
+// FieldPath joins the path to a field of the request body using the notation
+// of the API errors: ["members", "0", "username"] -> "members[0].username".
+// An empty path refers to the whole body.
+func FieldPath(path []string) string {
+	if len(path) == 0 {
+		return "body"
+	}
+
+	var b strings.Builder
+	for _, p := range path {
+		if _, err := strconv.Atoi(p); err == nil {
+			b.WriteString("[" + p + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(p)
+	}
+
+	return b.String()
+}
+
+// describeType names a Go type the way a JSON client sees it.
+func describeType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	default:
+		return t.String()
+	}
 }
@@ -0,0 +1,173 @@
+package json
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type member struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+type team struct {
+	TeamName string   `json:"team_name"`
+	Members  []member `json:"members"`
+	Labels   []string `json:"labels"`
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		limit    int64
+		want     team
+		field    string
+		reason   string
+		tooLarge bool
+	}{
+		{
+			name: "valid body",
+			body: `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}],"labels":["go"]}`,
+			want: team{
+				TeamName: "backend",
+				Members:  []member{{UserID: "u1", Username: "Alice", IsActive: true}},
+				Labels:   []string{"go"},
+			},
+		},
+		{
+			name:   "empty body",
+			body:   "",
+			field:  "body",
+			reason: "request body is empty",
+		},
+		{
+			name:   "truncated body",
+			body:   `{"team_name":"backend"`,
+			field:  "body",
+			reason: "unexpected end of JSON",
+		},
+		{
+			name:   "malformed body",
+			body:   `{"team_name":backend}`,
+			field:  "body",
+			reason: "malformed JSON at offset 14",
+		},
+		{
+			name:   "trailing JSON value",
+			body:   `{"team_name":"backend"}{"team_name":"frontend"}`,
+			field:  "body",
+			reason: "unexpected data after the JSON value",
+		},
+		{
+			name:   "trailing garbage",
+			body:   `{"team_name":"backend"} x`,
+			field:  "body",
+			reason: "unexpected data after the JSON value",
+		},
+		{
+			name: "trailing whitespace is allowed",
+			body: "{\"team_name\":\"backend\"}\n\t ",
+			want: team{TeamName: "backend"},
+		},
+		{
+			name:   "wrong type in a nested field",
+			body:   `{"team_name":"backend","members":[{"user_id":"u1","is_active":"yes"}]}`,
+			field:  "members[0].is_active",
+			reason: "must be a boolean, got string",
+		},
+		{
+			name:   "unknown top-level field",
+			body:   `{"team_name":"backend","owner":"u1"}`,
+			field:  "owner",
+			reason: "unknown field",
+		},
+		{
+			name:   "unknown nested field",
+			body:   `{"team_name":"backend","members":[{"user_id":"u1","role":"lead"}]}`,
+			field:  "role",
+			reason: "unknown field",
+		},
+		{
+			name:   "NUL at the top level",
+			body:   `{"team_name":"back\u0000end"}`,
+			field:  "team_name",
+			reason: "must not contain NUL characters",
+		},
+		{
+			name:   "NUL in a nested string",
+			body:   `{"team_name":"backend","members":[{"user_id":"u1"},{"user_id":"u2","username":"B\u0000b"}]}`,
+			field:  "members[1].username",
+			reason: "must not contain NUL characters",
+		},
+		{
+			name:   "NUL in a slice of strings",
+			body:   `{"team_name":"backend","labels":["go","\u0000"]}`,
+			field:  "labels[1]",
+			reason: "must not contain NUL characters",
+		},
+		{
+			name:     "body over the limit",
+			body:     `{"team_name":"` + strings.Repeat("a", 64) + `"}`,
+			limit:    32,
+			field:    "body",
+			reason:   "must not exceed 32 bytes",
+			tooLarge: true,
+		},
+		{
+			name:     "trailing data over the limit",
+			body:     `{"team_name":"backend"}` + strings.Repeat(" ", 64) + "x",
+			limit:    32,
+			field:    "body",
+			reason:   "must not exceed 32 bytes",
+			tooLarge: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/team/add", strings.NewReader(tt.body))
+			if tt.limit > 0 {
+				r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, tt.limit)
+			}
+
+			var got team
+			err := Read(r, &got)
+			if tt.reason == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				return
+			}
+
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, tt.field, decodeErr.Field)
+			assert.Equal(t, tt.reason, decodeErr.Reason)
+			var tooLarge *http.MaxBytesError
+			assert.Equal(t, tt.tooLarge, errors.As(err, &tooLarge))
+		})
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{path: nil, want: "body"},
+		{path: []string{"team_name"}, want: "team_name"},
+		{path: []string{"members", "0", "username"}, want: "members[0].username"},
+		{path: []string{"labels", "1"}, want: "labels[1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, FieldPath(tt.path))
+		})
+	}
+}
@@ -0,0 +1,200 @@
+// Package openapi validates HTTP traffic against the OpenAPI specification in docs/openapi.yml.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/docs"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Validator checks requests and, optionally, responses against the spec.
+type Validator struct {
+	router            routers.Router
+	validateResponses bool
+}
+
+// NewValidator loads and validates the embedded specification.
+// When validateResponses is set (test mode), every response is checked too
+// and a response that does not match the spec is replaced by INTERNAL_ERROR.
+func NewValidator(validateResponses bool) (*Validator, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(docs.OpenAPI)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("build openapi router: %w", err)
+	}
+
+	return &Validator{
+		router:            router,
+		validateResponses: validateResponses,
+	}, nil
+}
+
+// Middleware validates requests of the routes described in the spec.
+// Routes that are not in the spec are passed through untouched.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options: &openapi3filter.Options{
+				MultiError:         true,
+				AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+			},
+		}
+
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			errors.WriteAppError(w, r, "request does not match the API spec", invalidInput(err))
+			return
+		}
+
+		if !v.validateResponses {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 rec.status,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+			Options:                &openapi3filter.Options{MultiError: true},
+		})
+		if err != nil {
+			slog.Error("response does not match the API spec",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.status,
+				"error", err,
+			)
+			errors.WriteAppError(w, r, "invalid response", errors.InternalError)
+			return
+		}
+
+		w.WriteHeader(rec.status)
+		_, _ = w.Write(rec.body.Bytes())
+	})
+}
+
+// invalidInput converts validation errors into an INVALID_INPUT error
+// naming the fields that failed.
+func invalidInput(err error) *errors.AppError {
+	var details []errors.FieldError
+	for _, e := range flatten(err) {
+		details = append(details, describe(e)...)
+	}
+
+	return errors.ErrInvalidInput.WithDetails(details...)
+}
+
+// flatten expands (nested) MultiErrors without unwrapping other error types.
+func flatten(err error) []error {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var errs []error
+		for _, e := range multi {
+			errs = append(errs, flatten(e)...)
+		}
+		return errs
+	}
+
+	return []error{err}
+}
+
+// describe returns the field errors contained in a single validation error.
+func describe(err error) []errors.FieldError {
+	var reqErr *openapi3filter.RequestError
+	if !stderrors.As(err, &reqErr) {
+		return []errors.FieldError{{Field: "request", Reason: err.Error()}}
+	}
+
+	// the body was cut by http.MaxBytesReader while being read
+	var tooLarge *http.MaxBytesError
+	if stderrors.As(reqErr.Err, &tooLarge) {
+		return []errors.FieldError{{Field: "body", Reason: fmt.Sprintf("must not exceed %d bytes", tooLarge.Limit)}}
+	}
+
+	var details []errors.FieldError
+	for _, e := range flatten(reqErr.Err) {
+		var schemaErr *openapi3.SchemaError
+		if e == nil || !stderrors.As(e, &schemaErr) {
+			continue
+		}
+
+		field := json.FieldPath(schemaErr.JSONPointer())
+		if reqErr.Parameter != nil {
+			field = reqErr.Parameter.Name
+		}
+		details = append(details, errors.FieldError{Field: field, Reason: schemaErr.Reason})
+	}
+	if len(details) > 0 {
+		return details
+	}
+
+	switch {
+	case reqErr.Parameter != nil:
+		return []errors.FieldError{{Field: reqErr.Parameter.Name, Reason: reason(reqErr)}}
+	case reqErr.RequestBody != nil:
+		return []errors.FieldError{{Field: "body", Reason: reason(reqErr)}}
+	default:
+		return []errors.FieldError{{Field: "request", Reason: reqErr.Error()}}
+	}
+}
+
+// reason is the human readable cause of reqErr, never empty.
+func reason(reqErr *openapi3filter.RequestError) string {
+	switch {
+	case reqErr.Reason != "":
+		return reqErr.Reason
+	case stderrors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired):
+		return "is required"
+	case reqErr.Err != nil:
+		return reqErr.Err.Error()
+	default:
+		return "is invalid"
+	}
+}
+
+// responseRecorder buffers the response so it can be validated before sending.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
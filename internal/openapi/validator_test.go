@@ -0,0 +1,141 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// handler answers with the given status and body and records whether it ran.
+type handler struct {
+	status int
+	body   string
+	called bool
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	h.called = true
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(h.status)
+	_, _ = w.Write([]byte(h.body))
+}
+
+const validUser = `{"user":{"user_id":"u2","username":"Bob","team_name":"backend","is_active":false}}`
+
+func serve(t *testing.T, validateResponses bool, next http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	v, err := NewValidator(validateResponses)
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	v.Middleware(next).ServeHTTP(w, r)
+
+	return w
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) *errors.AppError {
+	t.Helper()
+
+	var body errors.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	require.NotNil(t, body.Error)
+
+	return body.Error
+}
+
+func TestValidator_RejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    string
+		details []errors.FieldError
+	}{
+		{
+			name:    "missing property",
+			method:  http.MethodPost,
+			target:  "/users/setIsActive",
+			body:    `{"user_id":"u2"}`,
+			details: []errors.FieldError{{Field: "is_active", Reason: `property "is_active" is missing`}},
+		},
+		{
+			name:    "wrong type",
+			method:  http.MethodPost,
+			target:  "/users/setIsActive",
+			body:    `{"user_id":"u2","is_active":"no"}`,
+			details: []errors.FieldError{{Field: "is_active", Reason: "value must be a boolean"}},
+		},
+		{
+			name:   "every field is reported",
+			method: http.MethodPost,
+			target: "/users/setIsActive",
+			body:   `{"user_id":1,"is_active":"no"}`,
+			details: []errors.FieldError{
+				{Field: "user_id", Reason: "value must be a string"},
+				{Field: "is_active", Reason: "value must be a boolean"},
+			},
+		},
+		{
+			name:    "missing query parameter",
+			method:  http.MethodGet,
+			target:  "/team/get",
+			details: []errors.FieldError{{Field: "team_name", Reason: "value is required but missing"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &handler{status: http.StatusOK, body: validUser}
+			w := serve(t, false, next, tt.method, tt.target, tt.body)
+
+			assert.False(t, next.called)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			appErr := decodeError(t, w)
+			assert.Equal(t, "INVALID_INPUT", appErr.Code)
+			assert.ElementsMatch(t, tt.details, appErr.Details)
+		})
+	}
+}
+
+func TestValidator_PassesValidRequests(t *testing.T) {
+	next := &handler{status: http.StatusOK, body: validUser}
+	w := serve(t, false, next, http.MethodPost, "/users/setIsActive", `{"user_id":"u2","is_active":false}`)
+	assert.True(t, next.called)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, validUser, w.Body.String())
+
+	// routes outside the spec are not validated
+	next = &handler{status: http.StatusOK, body: "{}"}
+	w = serve(t, false, next, http.MethodPost, "/internal/anything", "not json")
+	assert.True(t, next.called)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestValidator_Responses(t *testing.T) {
+	invalid := `{"user":{"user_id":"u2","is_active":"no"}}`
+
+	// responses are passed as they are outside of test mode
+	w := serve(t, false, &handler{status: http.StatusOK, body: invalid}, http.MethodPost, "/users/setIsActive", `{"user_id":"u2","is_active":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, invalid, w.Body.String())
+
+	// in test mode a response breaking the spec turns into INTERNAL_ERROR
+	w = serve(t, true, &handler{status: http.StatusOK, body: invalid}, http.MethodPost, "/users/setIsActive", `{"user_id":"u2","is_active":false}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "INTERNAL_ERROR", decodeError(t, w).Code)
+
+	// a valid one is sent unchanged with its status
+	w = serve(t, true, &handler{status: http.StatusOK, body: validUser}, http.MethodPost, "/users/setIsActive", `{"user_id":"u2","is_active":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, validUser, w.Body.String())
+}
@@ -4,6 +4,7 @@ package pr
 import (
 	"net/http"
 
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
@@ -11,15 +12,15 @@ import (
 
 // CreatePR handles the creation of a new pull request.
 func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
-	var req repo.CreatePRParams
+	var req CreatePRRequest
 	if err := json.Read(r, &req); err != nil {
-		errors.WriteAppError(w, "invalid json in CreatePR", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "invalid json in CreatePR", errors.InvalidJSON(err))
 		return
 	}
 
 	response, err := h.service.CreatePR(r.Context(), req)
 	if err != nil {
-		errors.WriteAppError(w, "failed to create PR", err)
+		errors.WriteAppError(w, r, "failed to create PR", err)
 		return
 	}
 
@@ -30,15 +31,16 @@ func (h *Handler) CreatePR(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id"`
+		Repository    string `json:"repository"`
 	}
 	if err := json.Read(r, &req); err != nil {
-		errors.WriteAppError(w, "invalid json in MergePR", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "invalid json in MergePR", errors.InvalidJSON(err))
 		return
 	}
 
-	response, err := h.service.MergePR(r.Context(), req.PullRequestID)
+	response, err := h.service.MergePR(r.Context(), domain.PRKey{Repository: req.Repository, PullRequestID: req.PullRequestID})
 	if err != nil {
-		errors.WriteAppError(w, "failed to merge PR", err)
+		errors.WriteAppError(w, r, "failed to merge PR", err)
 		return
 	}
 
@@ -49,16 +51,34 @@ func (h *Handler) MergePR(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id"`
+		Repository    string `json:"repository"`
 		OldUserID     string `json:"old_user_id"`
 	}
 	if err := json.Read(r, &req); err != nil {
-		errors.WriteAppError(w, "invalid json in ReassignReviewer", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "invalid json in ReassignReviewer", errors.InvalidJSON(err))
 		return
 	}
 
-	response, err := h.service.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	response, err := h.service.ReassignReviewer(r.Context(), domain.PRKey{Repository: req.Repository, PullRequestID: req.PullRequestID}, req.OldUserID)
 	if err != nil {
-		errors.WriteAppError(w, "failed to reassign reviewer", err)
+		errors.WriteAppError(w, r, "failed to reassign reviewer", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// PreviewAssignment handles the preview of the reviewer candidates of a new pull request.
+func (h *Handler) PreviewAssignment(w http.ResponseWriter, r *http.Request) {
+	var req PreviewRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in PreviewAssignment", errors.InvalidJSON(err))
+		return
+	}
+
+	response, err := h.service.PreviewAssignment(r.Context(), req)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to preview assignment", err)
 		return
 	}
 
@@ -67,11 +87,24 @@ func (h *Handler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 
 // GetUserReviews handles the retrieval of pull requests assigned to a user for review.
 func (h *Handler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
-	userID := r.URL.Query().Get("user_id")
+	query := r.URL.Query()
+
+	response, err := h.service.GetUserReviews(r.Context(), query.Get("user_id"), query.Get("repository"))
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to get user reviews", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// ListPRs handles listing pull requests, optionally of one repository or status.
+func (h *Handler) ListPRs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
 
-	response, err := h.service.GetUserReviews(r.Context(), userID)
+	response, err := h.service.ListPRs(r.Context(), repo.ListPRsParams{Repository: query.Get("repository"), Status: query.Get("status")})
 	if err != nil {
-		errors.WriteAppError(w, "failed to get user reviews", err)
+		errors.WriteAppError(w, r, "failed to list pull requests", err)
 		return
 	}
 
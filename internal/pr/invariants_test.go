@@ -0,0 +1,647 @@
+package pr_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	apperrors "github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/pr"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/pgtest"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/users"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	reviewersCount = 2
+	sequences      = 100
+	stepsPerRun    = 80
+)
+
+// TestAssignmentInvariants runs random sequences of operations against the
+// services and checks the assignment rules after every step:
+//
+//   - the author is never a reviewer of their own PR;
+//   - a reviewer is active, not absent and below their review limit when assigned;
+//   - a new review never takes a reviewer over their limit;
+//   - a PR has at most reviewersCount reviewers;
+//   - reviewers belong to the author's team, or to its fallback teams when the team is short;
+//   - a PR of a team requiring a senior gets one if any is eligible, and is flagged otherwise;
+//   - the stored flag is the one of the last response that assigned reviewers;
+//   - the only senior reviewer of such a PR is only replaced by a senior;
+//   - a reviewer with a never rule for the author is never assigned, preferred team mates are picked first;
+//   - the reviewers of a merged PR never change.
+//
+// The generator keeps teams disjoint, a user never moves to another team.
+func TestAssignmentInvariants(t *testing.T) {
+	seed := time.Now().UnixNano()
+	t.Logf("seed %d", seed)
+	rng := rand.New(rand.NewSource(seed))
+
+	for i := range sequences {
+		t.Run(fmt.Sprintf("sequence %d", i), func(t *testing.T) {
+			m := newModel(t, rand.New(rand.NewSource(rng.Int63())))
+			t.Cleanup(func() {
+				if t.Failed() {
+					t.Logf("seed %d, steps:\n%s", seed, strings.Join(m.history, "\n"))
+				}
+			})
+
+			for range stepsPerRun {
+				m.step()
+			}
+		})
+	}
+}
+
+// model is the expected state of the database, kept next to the services under test.
+type model struct {
+	t   *testing.T
+	rng *rand.Rand
+	ctx context.Context
+
+	repo  *repo.Queries
+	teams teams.Service
+	users users.Service
+	prs   pr.Service
+
+	teamOf    map[string]string    // user -> team
+	active    map[string]bool      // user -> is_active
+	absence   map[string]string    // user -> ID of the absence in progress
+	limit     map[string]int32     // user -> max open reviews, missing without a limit
+	fallbacks map[string][]string  // team -> fallback teams in order
+	borrowed  map[string][]string  // team -> every team that has been its fallback
+	senior    map[string]bool      // user -> senior or maintainer
+	strict    map[string]bool      // team -> requires a senior reviewer
+	pairs     map[[2]string]string // (reviewer, author) -> pair rule kind
+	authorOf  map[string]string    // pr -> author
+	merged    map[string][]string  // pr -> reviewers at merge time
+	reviewers map[string][]string  // pr -> reviewers after the last step
+	flagged   map[string]bool      // pr -> missing senior by the last assignment
+	seq       int
+	history   []string
+}
+
+func newModel(t *testing.T, rng *rand.Rand) *model {
+	db := pgtest.Open(t)
+	q := repo.New(db)
+
+	return &model{
+		t:         t,
+		rng:       rng,
+		ctx:       context.Background(),
+		repo:      q,
+		teams:     teams.NewService(q, db),
+		users:     users.NewService(q, db),
+		prs:       pr.NewService(q, db, reviewersCount),
+		teamOf:    make(map[string]string),
+		active:    make(map[string]bool),
+		absence:   make(map[string]string),
+		limit:     make(map[string]int32),
+		fallbacks: make(map[string][]string),
+		borrowed:  make(map[string][]string),
+		senior:    make(map[string]bool),
+		strict:    make(map[string]bool),
+		pairs:     make(map[[2]string]string),
+		authorOf:  make(map[string]string),
+		merged:    make(map[string][]string),
+		reviewers: make(map[string][]string),
+		flagged:   make(map[string]bool),
+	}
+}
+
+// step performs one random operation, checks its outcome and the invariants.
+func (m *model) step() {
+	ops := []struct {
+		name   string
+		weight int
+		run    func()
+	}{
+		{"create team", 2, m.createTeam},
+		{"toggle activity", 3, m.toggleActivity},
+		{"toggle absence", 2, m.toggleAbsence},
+		{"set review limit", 2, m.setReviewLimit},
+		{"set fallback teams", 1, m.setFallbacks},
+		{"set level", 2, m.setLevel},
+		{"toggle senior rule", 1, m.toggleSeniorRule},
+		{"set pair rule", 2, m.setPairRule},
+		{"create PR", 6, m.createPR},
+		{"reassign", 5, m.reassign},
+		{"merge", 2, m.merge},
+		{"deactivate users", 1, m.deactivateUsers},
+		{"plan deactivation", 1, m.planDeactivation},
+		{"release absent reviews", 1, m.releaseAbsentReviews},
+	}
+	if len(m.teamOf) == 0 {
+		ops = ops[:1]
+	}
+
+	total := 0
+	for _, op := range ops {
+		total += op.weight
+	}
+	n := m.rng.Intn(total)
+	for _, op := range ops {
+		if n < op.weight {
+			before := m.reviewers
+			m.history = append(m.history, op.name)
+			op.run()
+			m.checkInvariants(before)
+			return
+		}
+		n -= op.weight
+	}
+
+	panic("unreachable")
+}
+
+func (m *model) createTeam() {
+	m.seq++
+	name := fmt.Sprintf("team-%d", m.seq)
+
+	params := teams.CreateTeamParams{TeamName: name}
+	for i := range 1 + m.rng.Intn(5) {
+		id := fmt.Sprintf("%s-u%d", name, i)
+		params.Members = append(params.Members, teams.MemberParams{
+			UserID:   id,
+			Username: id,
+			IsActive: m.rng.Intn(4) > 0,
+		})
+	}
+
+	_, err := m.teams.CreateTeam(m.ctx, params)
+	require.NoError(m.t, err)
+
+	for _, u := range params.Members {
+		m.teamOf[u.UserID] = name
+		m.active[u.UserID] = u.IsActive
+	}
+}
+
+func (m *model) toggleActivity() {
+	id := m.pick(slices.Sorted(maps.Keys(m.teamOf)))
+	active := !m.active[id]
+
+	user, err := m.users.SetUserActivity(m.ctx, repo.SetUserActivityParams{UserID: id, IsActive: active})
+	require.NoError(m.t, err)
+	require.Equal(m.t, active, user.IsActive)
+
+	m.active[id] = active
+}
+
+func (m *model) toggleAbsence() {
+	id := m.pick(slices.Sorted(maps.Keys(m.teamOf)))
+
+	if absenceID, ok := m.absence[id]; ok {
+		_, err := m.users.DeleteAbsence(m.ctx, absenceID)
+		require.NoError(m.t, err)
+		delete(m.absence, id)
+		return
+	}
+
+	now := time.Now()
+	absence, err := m.users.AddAbsence(m.ctx, repo.CreateAbsenceParams{
+		UserID:   id,
+		StartsAt: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+		EndsAt:   pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+	})
+	require.NoError(m.t, err)
+	m.absence[id] = absence.AbsenceID
+}
+
+func (m *model) setReviewLimit() {
+	id := m.pick(slices.Sorted(maps.Keys(m.teamOf)))
+
+	var limit pgtype.Int4
+	if m.rng.Intn(3) > 0 {
+		limit = pgtype.Int4{Int32: int32(m.rng.Intn(3)), Valid: true}
+	}
+	res, err := m.users.SetMaxOpenReviews(m.ctx, repo.SetMaxOpenReviewsParams{UserID: id, MaxOpenReviews: limit})
+	require.NoError(m.t, err)
+	require.Equal(m.t, int64(m.openReviews(m.reviewers, id)), res.OpenReviews, "open reviews of %s", id)
+
+	if limit.Valid {
+		m.limit[id] = limit.Int32
+	} else {
+		delete(m.limit, id)
+	}
+}
+
+func (m *model) setFallbacks() {
+	all := slices.Sorted(maps.Keys(m.teamSet()))
+	team := m.pick(all)
+
+	var fallbacks []string
+	for range m.rng.Intn(3) {
+		if f := m.pick(all); f != team && !slices.Contains(fallbacks, f) {
+			fallbacks = append(fallbacks, f)
+		}
+	}
+	res, err := m.teams.SetFallbacks(m.ctx, teams.FallbacksParams{TeamName: team, FallbackTeams: fallbacks})
+	require.NoError(m.t, err)
+	require.Equal(m.t, append([]string{}, fallbacks...), res.FallbackTeams)
+
+	m.fallbacks[team] = fallbacks
+	for _, f := range fallbacks {
+		if !slices.Contains(m.borrowed[team], f) {
+			m.borrowed[team] = append(m.borrowed[team], f)
+		}
+	}
+}
+
+func (m *model) setLevel() {
+	id := m.pick(slices.Sorted(maps.Keys(m.teamOf)))
+	level := m.pick([]string{domain.LevelRegular, domain.LevelSenior, domain.LevelMaintainer})
+
+	res, err := m.users.SetLevel(m.ctx, repo.SetUserLevelParams{UserID: id, Level: level})
+	require.NoError(m.t, err)
+	require.Equal(m.t, level, res.Level)
+
+	m.senior[id] = domain.IsSenior(level)
+}
+
+func (m *model) toggleSeniorRule() {
+	team := m.pick(slices.Sorted(maps.Keys(m.teamSet())))
+	strict := !m.strict[team]
+
+	res, err := m.teams.SetRequireSenior(m.ctx, repo.SetTeamRequireSeniorParams{TeamName: team, RequireSenior: strict})
+	require.NoError(m.t, err)
+	require.Equal(m.t, strict, res.RequireSenior)
+
+	m.strict[team] = strict
+}
+
+func (m *model) setPairRule() {
+	all := slices.Sorted(maps.Keys(m.teamOf))
+	author, reviewer := m.pick(all), m.pick(all)
+	if author == reviewer {
+		return
+	}
+	key := [2]string{reviewer, author}
+	team := m.teamOf[author]
+
+	// now and then delete the rule instead
+	if m.rng.Intn(3) == 0 {
+		_, err := m.teams.DeletePairRule(m.ctx, repo.DeleteTeamPairRuleParams{TeamName: team, ReviewerID: reviewer, AuthorID: author})
+		if m.pairs[key] == "" {
+			require.ErrorIs(m.t, err, apperrors.ErrNotFound)
+			return
+		}
+		require.NoError(m.t, err)
+		delete(m.pairs, key)
+		return
+	}
+
+	kind := m.pick([]string{domain.PairNever, domain.PairPrefer})
+	res, err := m.teams.SetPairRule(m.ctx, repo.SetTeamPairRuleParams{TeamName: team, ReviewerID: reviewer, AuthorID: author, Kind: kind})
+	require.NoError(m.t, err)
+	require.Contains(m.t, res.PairRules, teams.PairRule{ReviewerID: reviewer, AuthorID: author, Kind: kind})
+
+	m.pairs[key] = kind
+}
+
+// allowed reports whether the user may review the author's PRs by the pair rules.
+func (m *model) allowed(id, author string) bool {
+	return m.pairs[[2]string{id, author}] != domain.PairNever
+}
+
+// hasSenior reports whether one of the users is a senior.
+func (m *model) hasSenior(ids []string) bool {
+	return slices.ContainsFunc(ids, func(id string) bool { return m.senior[id] })
+}
+
+// missingSenior reports how a PR of the author with the reviewers must be flagged.
+func (m *model) missingSenior(author string, reviewers []string) bool {
+	return m.strict[m.teamOf[author]] && !m.hasSenior(reviewers)
+}
+
+// teamSet returns the names of all teams.
+func (m *model) teamSet() map[string]bool {
+	set := make(map[string]bool)
+	for _, team := range m.teamOf {
+		set[team] = true
+	}
+
+	return set
+}
+
+// eligible reports whether the user may get a new review.
+func (m *model) eligible(id string) bool {
+	_, away := m.absence[id]
+	limit, capped := m.limit[id]
+	return m.active[id] && !away && (!capped || m.openReviews(m.reviewers, id) < int(limit))
+}
+
+// openReviews counts the reviews of the user on open PRs.
+func (m *model) openReviews(reviewers map[string][]string, id string) int {
+	n := 0
+	for prID, ids := range reviewers {
+		if m.merged[prID] == nil && slices.Contains(ids, id) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (m *model) createPR() {
+	// now and then reuse an existing ID
+	if len(m.authorOf) > 0 && m.rng.Intn(10) == 0 {
+		id := m.pick(slices.Sorted(maps.Keys(m.authorOf)))
+		_, err := m.prs.CreatePR(m.ctx, pr.CreatePRRequest{CreatePRParams: repo.CreatePRParams{PullRequestID: id, PullRequestName: id, AuthorID: m.authorOf[id]}})
+		require.ErrorIs(m.t, err, apperrors.ErrPRExists)
+		return
+	}
+
+	m.seq++
+	id := fmt.Sprintf("pr-%d", m.seq)
+	author := m.pick(slices.Sorted(maps.Keys(m.teamOf)))
+
+	res, err := m.prs.CreatePR(m.ctx, pr.CreatePRRequest{CreatePRParams: repo.CreatePRParams{PullRequestID: id, PullRequestName: id, AuthorID: author}})
+	require.NoError(m.t, err)
+	require.Equal(m.t, string(repo.PrStatusEnumOPEN), res.PR.Status)
+
+	// as many reviewers as there are eligible team mates, the rest from the fallback
+	// teams, a required senior may take the place of a team mate
+	eligible, fallback, seniors, preferred := 0, 0, 0, 0
+	for u, team := range m.teamOf {
+		switch {
+		case u == author || !m.eligible(u) || !m.allowed(u, author):
+		case team == m.teamOf[author]:
+			eligible++
+			if m.pairs[[2]string{u, author}] == domain.PairPrefer {
+				preferred++
+			}
+		case slices.Contains(m.fallbacks[m.teamOf[author]], team):
+			fallback++
+		default:
+			continue
+		}
+		if m.senior[u] && u != author && m.eligible(u) && m.allowed(u, author) {
+			seniors++
+		}
+	}
+	own := min(eligible, reviewersCount)
+	require.Len(m.t, res.PR.AssignedReviewers, own+min(fallback, reviewersCount-own))
+	mates, preferredMates := 0, 0
+	for _, r := range res.PR.AssignedReviewers {
+		if m.teamOf[r] == m.teamOf[author] {
+			mates++
+			if m.pairs[[2]string{r, author}] == domain.PairPrefer {
+				preferredMates++
+			}
+		}
+	}
+	require.Len(m.t, res.FallbackReviewers, len(res.PR.AssignedReviewers)-mates)
+	if !m.strict[m.teamOf[author]] {
+		require.Equal(m.t, own, mates, "team mates on %s", id)
+		require.Equal(m.t, min(preferred, own), preferredMates, "preferred team mates on %s", id)
+	}
+	for _, r := range res.FallbackReviewers {
+		require.Contains(m.t, m.fallbacks[m.teamOf[author]], m.teamOf[r], "fallback %s of %s", r, id)
+	}
+	if m.strict[m.teamOf[author]] {
+		require.Equal(m.t, seniors > 0, m.hasSenior(res.PR.AssignedReviewers), "senior on %s", id)
+	}
+	require.Equal(m.t, m.missingSenior(author, res.PR.AssignedReviewers), res.PR.MissingSenior, "missing senior on %s", id)
+
+	m.authorOf[id] = author
+	m.flagged[id] = res.PR.MissingSenior
+}
+
+func (m *model) reassign() {
+	if len(m.authorOf) == 0 {
+		return
+	}
+	id := m.pick(slices.Sorted(maps.Keys(m.authorOf)))
+	current := m.reviewers[id]
+
+	// mostly a current reviewer, sometimes anyone
+	old := m.pick(slices.Sorted(maps.Keys(m.teamOf)))
+	if len(current) > 0 && m.rng.Intn(4) > 0 {
+		old = m.pick(current)
+	}
+
+	others := slices.DeleteFunc(slices.Clone(current), func(r string) bool { return r == old })
+	onlySenior := m.strict[m.teamOf[m.authorOf[id]]] && m.senior[old] && !m.hasSenior(others)
+
+	res, err := m.prs.ReassignReviewer(m.ctx, domain.PRKey{PullRequestID: id}, old)
+	switch {
+	case m.merged[id] != nil:
+		require.ErrorIs(m.t, err, apperrors.ErrPRMerged)
+	case !slices.Contains(current, old):
+		require.ErrorIs(m.t, err, apperrors.ErrNotAssigned)
+	case errors.Is(err, apperrors.ErrNoCandidate):
+		// nobody left in the team who is eligible and not already involved,
+		// or no senior of them for the only senior reviewer
+		for u, team := range m.teamOf {
+			if team == m.teamOf[old] && m.eligible(u) && m.allowed(u, m.authorOf[id]) && u != old && u != m.authorOf[id] && (m.senior[u] || !onlySenior) {
+				require.Contains(m.t, current, u, "%s could replace %s on %s", u, old, id)
+			}
+		}
+	default:
+		require.NoError(m.t, err)
+		require.NotEqual(m.t, old, res.ReplacedBy)
+		require.Contains(m.t, res.PR.AssignedReviewers, res.ReplacedBy)
+		require.NotContains(m.t, res.PR.AssignedReviewers, old)
+		require.Len(m.t, res.PR.AssignedReviewers, len(current))
+		if onlySenior {
+			require.True(m.t, m.senior[res.ReplacedBy], "the only senior %s on %s replaced by %s", old, id, res.ReplacedBy)
+		}
+		require.Equal(m.t, m.missingSenior(m.authorOf[id], res.PR.AssignedReviewers), res.PR.MissingSenior, "missing senior on %s", id)
+		m.flagged[id] = res.PR.MissingSenior
+	}
+}
+
+func (m *model) merge() {
+	if len(m.authorOf) == 0 {
+		return
+	}
+	id := m.pick(slices.Sorted(maps.Keys(m.authorOf)))
+
+	res, err := m.prs.MergePR(m.ctx, domain.PRKey{PullRequestID: id})
+	require.NoError(m.t, err)
+	require.Equal(m.t, string(repo.PrStatusEnumMERGED), res.PR.Status)
+	require.ElementsMatch(m.t, m.reviewers[id], res.PR.AssignedReviewers)
+	require.Equal(m.t, m.flagged[id], res.PR.MissingSenior, "missing senior on merged %s", id)
+
+	if m.merged[id] == nil {
+		m.merged[id] = append([]string{}, m.reviewers[id]...)
+	}
+}
+
+func (m *model) deactivateUsers() {
+	ids := m.pickUsers()
+
+	res, err := m.teams.DeactivateUsers(m.ctx, ids, false)
+	require.NoError(m.t, err)
+
+	// every review of the users is reported, the merged ones as skipped
+	require.Len(m.t, res.Users, len(ids))
+	for _, report := range res.Users {
+		var open, merged []string
+		for prID, reviewers := range m.reviewers {
+			switch {
+			case !slices.Contains(reviewers, report.UserID):
+			case m.merged[prID] != nil:
+				merged = append(merged, prID)
+			default:
+				open = append(open, prID)
+			}
+		}
+		require.ElementsMatch(m.t, merged, report.Skipped, "skipped PRs of %s", report.UserID)
+		require.ElementsMatch(m.t, open, append(report.Reassigned, report.LostReviewer...), "open PRs of %s", report.UserID)
+	}
+
+	for _, p := range res.UpdatedPRs {
+		require.Equal(m.t, m.missingSenior(p.AuthorID, p.AssignedReviewers), p.MissingSenior, "missing senior on %s", p.PullRequestID)
+		m.flagged[p.PullRequestID] = p.MissingSenior
+	}
+
+	for _, id := range ids {
+		m.active[id] = false
+	}
+	for prID, reviewers := range m.load() {
+		if m.merged[prID] != nil {
+			continue
+		}
+		for _, id := range ids {
+			require.NotContains(m.t, reviewers, id, "deactivated %s still reviews open %s", id, prID)
+		}
+	}
+}
+
+func (m *model) planDeactivation() {
+	ids := m.pickUsers()
+
+	res, err := m.teams.DeactivateUsers(m.ctx, ids, true)
+	require.NoError(m.t, err)
+	require.True(m.t, res.DryRun)
+
+	// the plan covers every open review of the users, and nothing is written
+	planned := 0
+	for prID, reviewers := range m.reviewers {
+		for _, id := range ids {
+			if m.merged[prID] == nil && slices.Contains(reviewers, id) {
+				planned++
+			}
+		}
+	}
+	require.Len(m.t, res.Reassignments, planned)
+	require.Equal(m.t, m.reviewers, m.load())
+}
+
+func (m *model) releaseAbsentReviews() {
+	res, err := m.teams.ReleaseAbsentReviews(m.ctx)
+	require.NoError(m.t, err)
+	for _, p := range res.UpdatedPRs {
+		m.flagged[p.PullRequestID] = p.MissingSenior
+	}
+
+	// absent users never get new reviews, so they have no open ones left
+	for prID, reviewers := range m.load() {
+		if m.merged[prID] != nil {
+			continue
+		}
+		for id := range m.absence {
+			require.NotContains(m.t, reviewers, id, "absent %s still reviews open %s", id, prID)
+		}
+	}
+}
+
+// pickUsers returns one to three distinct users.
+func (m *model) pickUsers() []string {
+	all := slices.Sorted(maps.Keys(m.teamOf))
+	ids := make([]string, 0, 3)
+	for range 1 + m.rng.Intn(3) {
+		id := m.pick(all)
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// checkInvariants compares the stored assignments with the rules and with
+// the assignments before the step.
+func (m *model) checkInvariants(before map[string][]string) {
+	after := m.load()
+
+	users, err := m.repo.GetUsersByIDs(m.ctx, slices.Collect(maps.Keys(m.teamOf)))
+	require.NoError(m.t, err)
+	require.Len(m.t, users, len(m.teamOf))
+	for _, u := range users {
+		require.Equal(m.t, m.active[u.UserID], u.IsActive, "activity of %s", u.UserID)
+		require.Equal(m.t, m.teamOf[u.UserID], u.TeamName, "team of %s", u.UserID)
+	}
+
+	for prID, reviewers := range after {
+		author := m.authorOf[prID]
+
+		require.LessOrEqual(m.t, len(reviewers), reviewersCount, "too many reviewers on %s", prID)
+		require.NotContains(m.t, reviewers, author, "author reviews own %s", prID)
+		for _, r := range reviewers {
+			if m.teamOf[r] != m.teamOf[author] {
+				require.Contains(m.t, m.borrowed[m.teamOf[author]], m.teamOf[r], "%s on %s is not from the author's or a fallback team", r, prID)
+			}
+			if !slices.Contains(before[prID], r) {
+				require.True(m.t, m.eligible(r), "inactive, absent or busy %s assigned to %s", r, prID)
+				require.True(m.t, m.allowed(r, author), "%s assigned to %s despite a never rule", r, prID)
+				if limit, capped := m.limit[r]; capped {
+					require.LessOrEqual(m.t, m.openReviews(after, r), int(limit), "%s assigned over their limit", r)
+				}
+			}
+		}
+
+		if frozen := m.merged[prID]; frozen != nil {
+			require.ElementsMatch(m.t, frozen, reviewers, "reviewers of merged %s changed", prID)
+		}
+	}
+
+	m.reviewers = after
+}
+
+// load reads the current reviewers of every PR from the database.
+func (m *model) load() map[string][]string {
+	// the model's PRs have no repository
+	ids := slices.Collect(maps.Keys(m.authorOf))
+	keys := repo.GetPRsByIDsParams{PrRepositories: make([]string, len(ids)), PrIds: ids}
+
+	prs, err := m.repo.GetPRsByIDs(m.ctx, keys)
+	require.NoError(m.t, err)
+	require.Len(m.t, prs, len(ids))
+	for _, p := range prs {
+		merged := p.Status.PrStatusEnum == repo.PrStatusEnumMERGED
+		require.Equal(m.t, m.merged[p.PullRequestID] != nil, merged, "status of %s", p.PullRequestID)
+		require.Equal(m.t, m.authorOf[p.PullRequestID], p.AuthorID)
+		require.Equal(m.t, m.flagged[p.PullRequestID], p.MissingSenior, "stored missing senior on %s", p.PullRequestID)
+	}
+
+	rows, err := m.repo.GetReviewersByPRs(m.ctx, repo.GetReviewersByPRsParams(keys))
+	require.NoError(m.t, err)
+
+	reviewers := make(map[string][]string, len(ids))
+	for _, id := range ids {
+		reviewers[id] = []string{}
+	}
+	for _, row := range rows {
+		reviewers[row.PrID] = append(reviewers[row.PrID], row.ReviewerID)
+	}
+
+	return reviewers
+}
+
+func (m *model) pick(ids []string) string {
+	return ids[m.rng.Intn(len(ids))]
+}
@@ -4,29 +4,45 @@ package pr
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/codeowners"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
 	apperrors "github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-func (s *svc) CreatePR(ctx context.Context, createPRParams repo.CreatePRParams) (CreatePRResponse, error) {
+func (s *svc) CreatePR(ctx context.Context, req CreatePRRequest) (CreatePRResponse, error) {
+	createPRParams := req.CreatePRParams
+
 	// validate input
-	if createPRParams.AuthorID == "" {
-		return CreatePRResponse{}, apperrors.ErrInvalidInput
+	details := validateChanges(req.Repository, req.ChangedFiles)
+	if createPRParams.PullRequestID == "" {
+		details = append(details, apperrors.FieldError{Field: "pull_request_id", Reason: "must not be empty"})
 	}
 	if createPRParams.PullRequestName == "" {
-		return CreatePRResponse{}, apperrors.ErrInvalidInput
+		details = append(details, apperrors.FieldError{Field: "pull_request_name", Reason: "must not be empty"})
 	}
-	if createPRParams.PullRequestID == "" {
-		return CreatePRResponse{}, apperrors.ErrInvalidInput
+	if createPRParams.AuthorID == "" {
+		details = append(details, apperrors.FieldError{Field: "author_id", Reason: "must not be empty"})
+	}
+	if len(details) > 0 {
+		return CreatePRResponse{}, apperrors.ErrInvalidInput.WithDetails(details...)
 	}
 
+	createPRParams.Repository = pgtype.Text{String: req.Repository, Valid: req.Repository != ""}
+
 	// check if PR already exists
-	prExists, err := s.repo.PRExists(ctx, createPRParams.PullRequestID)
+	prExists, err := s.repo.PRExists(ctx, repo.PRExistsParams{
+		RepositoryKey: req.Repository,
+		PullRequestID: createPRParams.PullRequestID,
+	})
 	if err != nil {
 		return CreatePRResponse{}, err
 	}
@@ -34,20 +50,8 @@ func (s *svc) CreatePR(ctx context.Context, createPRParams repo.CreatePRParams)
 		return CreatePRResponse{}, apperrors.ErrPRExists
 	}
 
-	// get author and validate exists
-	author, err := s.repo.GetUser(ctx, createPRParams.AuthorID)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return CreatePRResponse{}, apperrors.ErrNotFound
-		}
-		return CreatePRResponse{}, err
-	}
-
-	// get active team members (excluding author)
-	teamMembers, err := s.repo.GetActiveTeamMembersExcept(ctx, repo.GetActiveTeamMembersExceptParams{
-		TeamName: author.TeamName,
-		UserID:   createPRParams.AuthorID,
-	})
+	// get author, the owners of the changed files and the candidates from the author's team
+	pool, err := s.resolveCandidates(ctx, createPRParams.AuthorID, req.Repository, req.ChangedFiles)
 	if err != nil {
 		return CreatePRResponse{}, err
 	}
@@ -66,14 +70,27 @@ func (s *svc) CreatePR(ctx context.Context, createPRParams repo.CreatePRParams)
 		return CreatePRResponse{}, err
 	}
 
-	// select up to 2 random reviewers
-	reviewers := selectRandomReviewers(teamMembers, 2)
+	// select up to reviewersCount reviewers
+	selected := pool.pick(s.reviewersCount)
+
+	// flag a PR that requires a senior reviewer but got none
+	if selected.missingSenior {
+		err := qtx.SetPRMissingSenior(ctx, repo.SetPRMissingSeniorParams{
+			RepositoryKey: pr.RepositoryKey,
+			PullRequestID: pr.PullRequestID,
+			MissingSenior: true,
+		})
+		if err != nil {
+			return CreatePRResponse{}, err
+		}
+	}
 
 	// assign reviewers
-	for _, reviewerID := range reviewers {
+	for _, reviewerID := range selected.reviewers {
 		_, err := qtx.AssignReviewer(ctx, repo.AssignReviewerParams{
-			PrID:       createPRParams.PullRequestID,
-			ReviewerID: reviewerID,
+			PrRepository: pr.RepositoryKey,
+			PrID:         pr.PullRequestID,
+			ReviewerID:   reviewerID,
 		})
 		if err != nil {
 			return CreatePRResponse{}, err
@@ -96,11 +113,466 @@ func (s *svc) CreatePR(ctx context.Context, createPRParams repo.CreatePRParams)
 			PullRequestName:   pr.PullRequestName,
 			AuthorID:          pr.AuthorID,
 			Status:            status,
-			AssignedReviewers: reviewers,
+			Repository:        pr.Repository.String,
+			AssignedReviewers: selected.reviewers,
+			MissingSenior:     selected.missingSenior,
 		},
+		OwnerReviewers:    selected.owners,
+		FallbackReviewers: selected.fallback,
 	}, nil
 }
 
+// validateChanges checks the changed files of a new PR and their repository.
+func validateChanges(repository string, changedFiles []string) []apperrors.FieldError {
+	var details []apperrors.FieldError
+	if len(changedFiles) > 0 && repository == "" {
+		details = append(details, apperrors.FieldError{Field: "repository", Reason: "must not be empty when changed_files are given"})
+	}
+	if len(changedFiles) > MaxChangedFiles {
+		details = append(details, apperrors.FieldError{Field: "changed_files", Reason: fmt.Sprintf("must contain at most %d paths", MaxChangedFiles)})
+	}
+	for i, path := range changedFiles {
+		if path == "" {
+			details = append(details, apperrors.FieldError{Field: fmt.Sprintf("changed_files[%d]", i), Reason: "must not be empty"})
+		}
+	}
+
+	return details
+}
+
+func (s *svc) PreviewAssignment(ctx context.Context, req PreviewRequest) (PreviewResponse, error) {
+	details := validateChanges(req.Repository, req.ChangedFiles)
+	if req.AuthorID == "" {
+		details = append(details, apperrors.FieldError{Field: "author_id", Reason: "must not be empty"})
+	}
+	if len(details) > 0 {
+		return PreviewResponse{}, apperrors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	pool, err := s.resolveCandidates(ctx, req.AuthorID, req.Repository, req.ChangedFiles)
+	if err != nil {
+		return PreviewResponse{}, err
+	}
+
+	candidates := make([]string, len(pool.candidates))
+	for i, c := range pool.candidates {
+		candidates[i] = c.UserID
+	}
+
+	owners := make([]string, len(pool.owners))
+	for i, o := range pool.owners {
+		owners[i] = o.UserID
+	}
+
+	fallback := []FallbackCandidate{}
+	for _, team := range pool.fallback {
+		for _, c := range team.candidates {
+			fallback = append(fallback, FallbackCandidate{UserID: c.UserID, TeamName: team.name})
+		}
+	}
+
+	seniors, preferred := []string{}, []string{}
+	for id, senior := range pool.seniors {
+		if senior {
+			seniors = append(seniors, id)
+		}
+		if pool.rules.Prefers(id) {
+			preferred = append(preferred, id)
+		}
+	}
+	slices.Sort(seniors)
+	slices.Sort(preferred)
+
+	return PreviewResponse{
+		AuthorID:            pool.author.UserID,
+		TeamName:            pool.author.TeamName,
+		ReviewersCount:      min(pool.size(), s.reviewersCount),
+		OwnerCandidates:     owners,
+		Candidates:          candidates,
+		Excluded:            pool.excluded,
+		FallbackCandidates:  fallback,
+		RequireSenior:       pool.requireSenior,
+		SeniorCandidates:    seniors,
+		PreferredCandidates: preferred,
+	}, nil
+}
+
+// candidatePool is the author's team split into possible reviewers and the rest.
+// owners are the possible reviewers owning the changed files, from any team,
+// fallback is only filled when the team and the owners are fewer than reviewersCount
+// or none of them is the senior the author's team requires.
+type candidatePool struct {
+	author        repo.User
+	owners        []repo.User
+	candidates    []repo.User
+	excluded      []Exclusion
+	fallback      []fallbackTeam
+	requireSenior bool
+	// seniors marks the senior and maintainer users among all the candidates
+	seniors map[string]bool
+	// rules are the pair rules of the author's team for the author
+	rules domain.PairRules
+}
+
+// selection is what pick chose: all the reviewers and which of them are owners and
+// which come from the fallback teams. missingSenior is set when a senior is required
+// but none could be picked.
+type selection struct {
+	reviewers     []string
+	owners        []string
+	fallback      []string
+	missingSenior bool
+}
+
+// pick chooses up to n random reviewers: owners of the changed files first, then
+// the author's team mates, then the fallback teams in order, the reviewers preferred
+// for the author first in each group. If the author's team requires a senior and none
+// was chosen, a senior from the first of these groups having one takes the place of
+// the last reviewer.
+func (p candidatePool) pick(n int) selection {
+	var sel selection
+	without := func(users []repo.User) []repo.User {
+		return slices.DeleteFunc(slices.Clone(users), func(u repo.User) bool { return slices.Contains(sel.reviewers, u.UserID) })
+	}
+
+	sel.reviewers = selectPreferredReviewers(p.rules, p.owners, n)
+	sel.owners = slices.Clone(sel.reviewers)
+	sel.reviewers = append(sel.reviewers, selectPreferredReviewers(p.rules, without(p.candidates), n-len(sel.reviewers))...)
+
+	sel.fallback = []string{}
+	for _, team := range p.fallback {
+		picked := selectPreferredReviewers(p.rules, without(team.candidates), n-len(sel.reviewers))
+		sel.reviewers = append(sel.reviewers, picked...)
+		sel.fallback = append(sel.fallback, picked...)
+	}
+
+	if !p.requireSenior || n == 0 || slices.ContainsFunc(sel.reviewers, func(id string) bool { return p.seniors[id] }) {
+		return sel
+	}
+
+	groups := [][]repo.User{p.owners, p.candidates}
+	for _, team := range p.fallback {
+		groups = append(groups, team.candidates)
+	}
+	for i, group := range groups {
+		seniors := slices.DeleteFunc(without(group), func(u repo.User) bool { return !p.seniors[u.UserID] })
+		if len(seniors) == 0 {
+			continue
+		}
+
+		if len(sel.reviewers) == n {
+			last := sel.reviewers[n-1]
+			sel.reviewers = sel.reviewers[:n-1]
+			sel.owners = slices.DeleteFunc(sel.owners, func(id string) bool { return id == last })
+			sel.fallback = slices.DeleteFunc(sel.fallback, func(id string) bool { return id == last })
+		}
+		senior := selectPreferredReviewers(p.rules, seniors, 1)
+		sel.reviewers = append(sel.reviewers, senior...)
+		switch {
+		case i == 0:
+			sel.owners = append(sel.owners, senior...)
+		case i > 1:
+			sel.fallback = append(sel.fallback, senior...)
+		}
+		return sel
+	}
+	sel.missingSenior = true
+
+	return sel
+}
+
+// hasSenior reports whether an owner or a team mate is a senior.
+func (p candidatePool) hasSenior() bool {
+	for _, u := range slices.Concat(p.owners, p.candidates) {
+		if p.seniors[u.UserID] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// size counts the distinct users pick may choose from.
+func (p candidatePool) size() int {
+	seen := make(map[string]bool)
+	for _, u := range slices.Concat(p.owners, p.candidates) {
+		seen[u.UserID] = true
+	}
+	for _, team := range p.fallback {
+		for _, u := range team.candidates {
+			seen[u.UserID] = true
+		}
+	}
+
+	return len(seen)
+}
+
+// admit reports whether the user may review a PR of the author: not the author,
+// active, present, below their review limit and not ruled out by a pair rule.
+// An admitted user is marked in seniors, any other is added to excluded once,
+// so an owner who is also a team mate is not listed twice.
+func (p *candidatePool) admit(u repo.User, absent map[string]bool, load repo.GetReviewLoadRow) bool {
+	var reason string
+	switch {
+	case u.UserID == p.author.UserID:
+		reason = ExcludedAuthor
+	case !u.IsActive:
+		reason = ExcludedInactive
+	case absent[u.UserID]:
+		reason = ExcludedAbsent
+	case atCapacity(load):
+		reason = ExcludedOverCapacity
+	case p.rules.Never(u.UserID):
+		reason = ExcludedPairRule
+	default:
+		p.seniors[u.UserID] = domain.IsSenior(load.Level)
+		return true
+	}
+
+	if !slices.ContainsFunc(p.excluded, func(e Exclusion) bool { return e.UserID == u.UserID }) {
+		p.excluded = append(p.excluded, Exclusion{UserID: u.UserID, TeamName: u.TeamName, Reason: reason})
+	}
+
+	return false
+}
+
+// fallbackTeam is a fallback team of the author's team with its possible reviewers.
+type fallbackTeam struct {
+	name       string
+	candidates []repo.User
+}
+
+// resolveCandidates finds who may review a new PR of the author changing the files
+// of the repository, it only reads, so CreatePR and PreviewAssignment see the same pool.
+func (s *svc) resolveCandidates(ctx context.Context, authorID, repository string, changedFiles []string) (candidatePool, error) {
+	author, err := s.repo.GetUser(ctx, authorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return candidatePool{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "author_id", Reason: "user not found"})
+		}
+		return candidatePool{}, err
+	}
+
+	members, err := s.repo.GetTeam(ctx, author.TeamName)
+	if err != nil {
+		return candidatePool{}, err
+	}
+
+	if err := s.checkRepository(ctx, repository); err != nil {
+		return candidatePool{}, err
+	}
+
+	owners, err := s.codeOwners(ctx, repository, changedFiles)
+	if err != nil {
+		return candidatePool{}, err
+	}
+
+	everyone := slices.Concat(members, owners)
+	absent, err := s.absentUsers(ctx, everyone)
+	if err != nil {
+		return candidatePool{}, err
+	}
+	load, err := s.reviewLoad(ctx, userIDs(everyone))
+	if err != nil {
+		return candidatePool{}, err
+	}
+	requireSenior, err := s.requiresSenior(ctx, author.TeamName)
+	if err != nil {
+		return candidatePool{}, err
+	}
+	rules, err := s.pairRules(ctx, author.UserID)
+	if err != nil {
+		return candidatePool{}, err
+	}
+
+	pool := candidatePool{author: author, excluded: []Exclusion{}, requireSenior: requireSenior, seniors: make(map[string]bool), rules: rules}
+	for _, o := range owners {
+		if pool.admit(o, absent, load[o.UserID]) {
+			pool.owners = append(pool.owners, o)
+		}
+	}
+	for _, m := range members {
+		if pool.admit(m, absent, load[m.UserID]) {
+			pool.candidates = append(pool.candidates, m)
+		}
+	}
+
+	if pool.size() < s.reviewersCount || (pool.requireSenior && !pool.hasSenior()) {
+		if err := s.fallbackCandidates(ctx, &pool); err != nil {
+			return candidatePool{}, err
+		}
+	}
+
+	return pool, nil
+}
+
+// codeOwners returns the users owning the changed files by the CODEOWNERS file
+// of the repository, sorted by ID. Team owners are expanded to their members.
+func (s *svc) codeOwners(ctx context.Context, repository string, changedFiles []string) ([]repo.User, error) {
+	if repository == "" || len(changedFiles) == 0 {
+		return nil, nil
+	}
+
+	file, err := s.repo.GetCodeOwners(ctx, repository)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// the file was validated on upload
+	rules, err := codeowners.Parse(file.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var userIDs, teams []string
+	for _, path := range changedFiles {
+		for _, o := range rules.Owners(path) {
+			switch {
+			case o.User != "" && !slices.Contains(userIDs, o.User):
+				userIDs = append(userIDs, o.User)
+			case o.Team != "" && !slices.Contains(teams, o.Team):
+				teams = append(teams, o.Team)
+			}
+		}
+	}
+	if len(userIDs) == 0 && len(teams) == 0 {
+		return nil, nil
+	}
+
+	users, err := s.repo.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.GetUsersByTeams(ctx, teams)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := slices.Concat(users, members)
+	slices.SortFunc(owners, func(a, b repo.User) int { return strings.Compare(a.UserID, b.UserID) })
+
+	return slices.CompactFunc(owners, func(a, b repo.User) bool { return a.UserID == b.UserID }), nil
+}
+
+// fallbackCandidates fills the fallback teams of the author's team in order with the
+// members that could review, the others are added to the excluded ones.
+func (s *svc) fallbackCandidates(ctx context.Context, pool *candidatePool) error {
+	names, err := s.repo.GetTeamFallbacks(ctx, pool.author.TeamName)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+
+	members, err := s.repo.GetUsersByTeams(ctx, names)
+	if err != nil {
+		return err
+	}
+	absent, err := s.absentUsers(ctx, members)
+	if err != nil {
+		return err
+	}
+	load, err := s.reviewLoad(ctx, userIDs(members))
+	if err != nil {
+		return err
+	}
+
+	pool.fallback = make([]fallbackTeam, len(names))
+	for i, name := range names {
+		pool.fallback[i].name = name
+		for _, m := range members {
+			if m.TeamName == name && pool.admit(m, absent, load[m.UserID]) {
+				pool.fallback[i].candidates = append(pool.fallback[i].candidates, m)
+			}
+		}
+	}
+
+	return nil
+}
+
+// requiresSenior reports whether PRs of the team's authors need a senior reviewer.
+func (s *svc) requiresSenior(ctx context.Context, teamName string) (bool, error) {
+	settings, err := s.repo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return settings.RequireSenior, nil
+}
+
+// pairRules returns the pair rules of the author's team for the author.
+func (s *svc) pairRules(ctx context.Context, authorID string) (domain.PairRules, error) {
+	rows, err := s.repo.GetPairRulesByAuthors(ctx, []string{authorID})
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make(domain.PairRules, len(rows))
+	for _, r := range rows {
+		rules[r.ReviewerID] = r.Kind
+	}
+
+	return rules, nil
+}
+
+// userIDs returns the IDs of the users.
+func userIDs(users []repo.User) []string {
+	ids := make([]string, len(users))
+	for i, u := range users {
+		ids[i] = u.UserID
+	}
+
+	return ids
+}
+
+// absentUsers returns which of the users are absent right now.
+func (s *svc) absentUsers(ctx context.Context, users []repo.User) (map[string]bool, error) {
+	absent, err := s.repo.GetAbsentUsers(ctx, userIDs(users))
+	if err != nil {
+		return nil, err
+	}
+
+	set := make(map[string]bool, len(absent))
+	for _, id := range absent {
+		set[id] = true
+	}
+
+	return set, nil
+}
+
+// reviewLoad returns the open reviews, the limit and the level of each of the users.
+func (s *svc) reviewLoad(ctx context.Context, ids []string) (map[string]repo.GetReviewLoadRow, error) {
+	rows, err := s.repo.GetReviewLoad(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	load := make(map[string]repo.GetReviewLoadRow, len(rows))
+	for _, r := range rows {
+		load[r.UserID] = r
+	}
+
+	return load, nil
+}
+
+// atCapacity reports whether the user has as many open reviews as they accept.
+func atCapacity(load repo.GetReviewLoadRow) bool {
+	return load.MaxOpenReviews.Valid && load.OpenReviews >= int64(load.MaxOpenReviews.Int32)
+}
+
+// selectPreferredReviewers randomly selects up to maxReviewers from the user list,
+// the users preferred by the rules first.
+func selectPreferredReviewers(rules domain.PairRules, users []repo.User, maxReviewers int) []string {
+	preferred := slices.DeleteFunc(slices.Clone(users), func(u repo.User) bool { return !rules.Prefers(u.UserID) })
+	others := slices.DeleteFunc(slices.Clone(users), func(u repo.User) bool { return rules.Prefers(u.UserID) })
+
+	picked := selectRandomReviewers(preferred, maxReviewers)
+	return append(picked, selectRandomReviewers(others, maxReviewers-len(picked))...)
+}
+
 // selectRandomReviewers randomly selects up to maxReviewers from the user list
 func selectRandomReviewers(users []repo.User, maxReviewers int) []string {
 	if len(users) == 0 {
@@ -132,14 +604,14 @@ func selectRandomReviewers(users []repo.User, maxReviewers int) []string {
 	return reviewers
 }
 
-func (s *svc) MergePR(ctx context.Context, prID string) (Response, error) {
+func (s *svc) MergePR(ctx context.Context, key domain.PRKey) (Response, error) {
 	// validate input
-	if prID == "" {
+	if key.PullRequestID == "" {
 		return Response{}, apperrors.ErrInvalidInput
 	}
 
 	// merge PR (idempotent - already merged PR will just return current state)
-	pr, err := s.repo.MergePR(ctx, prID)
+	pr, err := s.repo.MergePR(ctx, repo.MergePRParams{RepositoryKey: key.Repository, PullRequestID: key.PullRequestID})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return Response{}, apperrors.ErrNotFound
@@ -148,7 +620,7 @@ func (s *svc) MergePR(ctx context.Context, prID string) (Response, error) {
 	}
 
 	// get current reviewers
-	reviewerIDs, err := s.repo.GetPRReviewers(ctx, prID)
+	reviewerIDs, err := s.repo.GetPRReviewers(ctx, repo.GetPRReviewersParams{PrRepository: pr.RepositoryKey, PrID: pr.PullRequestID})
 	if err != nil {
 		return Response{}, err
 	}
@@ -165,19 +637,21 @@ func (s *svc) MergePR(ctx context.Context, prID string) (Response, error) {
 			PullRequestName:   pr.PullRequestName,
 			AuthorID:          pr.AuthorID,
 			Status:            status,
+			Repository:        pr.Repository.String,
 			AssignedReviewers: reviewerIDs,
+			MissingSenior:     pr.MissingSenior,
 		},
 	}, nil
 }
 
-func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (ReassignResponse, error) {
+func (s *svc) ReassignReviewer(ctx context.Context, key domain.PRKey, oldUserID string) (ReassignResponse, error) {
 	// validate input
-	if prID == "" || oldUserID == "" {
+	if key.PullRequestID == "" || oldUserID == "" {
 		return ReassignResponse{}, apperrors.ErrInvalidInput
 	}
 
 	// check PR exists and get it
-	pr, err := s.repo.GetPR(ctx, prID)
+	pr, err := s.repo.GetPR(ctx, repo.GetPRParams{RepositoryKey: key.Repository, PullRequestID: key.PullRequestID})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ReassignResponse{}, apperrors.ErrNotFound
@@ -192,8 +666,9 @@ func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (Rea
 
 	// check old reviewer is actually assigned
 	isAssigned, err := s.repo.CheckReviewerAssignment(ctx, repo.CheckReviewerAssignmentParams{
-		PrID:       prID,
-		ReviewerID: oldUserID,
+		PrRepository: pr.RepositoryKey,
+		PrID:         pr.PullRequestID,
+		ReviewerID:   oldUserID,
 	})
 	if err != nil {
 		return ReassignResponse{}, err
@@ -220,8 +695,14 @@ func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (Rea
 		return ReassignResponse{}, err
 	}
 
+	// absent members cannot take over
+	absent, err := s.absentUsers(ctx, teamMembers)
+	if err != nil {
+		return ReassignResponse{}, err
+	}
+
 	// get current reviewers to exclude them from candidates
-	currentReviewers, err := s.repo.GetPRReviewers(ctx, prID)
+	currentReviewers, err := s.repo.GetPRReviewers(ctx, repo.GetPRReviewersParams{PrRepository: pr.RepositoryKey, PrID: pr.PullRequestID})
 	if err != nil {
 		return ReassignResponse{}, err
 	}
@@ -231,25 +712,80 @@ func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (Rea
 		currentReviewersMap[id] = true
 	}
 
-	// filter out PR author and current reviewers from candidates
+	// the pair rules of the author's team for the author
+	rules, err := s.pairRules(ctx, pr.AuthorID)
+	if err != nil {
+		return ReassignResponse{}, err
+	}
+
+	// filter out PR author, current reviewers, absent members and members
+	// ruled out for the author from candidates
 	var candidates []repo.User
 	for _, member := range teamMembers {
-		if member.UserID != pr.AuthorID && !currentReviewersMap[member.UserID] {
+		if member.UserID != pr.AuthorID && !currentReviewersMap[member.UserID] && !absent[member.UserID] && !rules.Never(member.UserID) {
 			candidates = append(candidates, member)
 		}
 	}
 
+	// members at capacity are left out, the error names them
+	load, err := s.reviewLoad(ctx, slices.Concat(userIDs(candidates), currentReviewers))
+	if err != nil {
+		return ReassignResponse{}, err
+	}
+	var busy []apperrors.FieldError
+	candidates = slices.DeleteFunc(candidates, func(member repo.User) bool {
+		l := load[member.UserID]
+		if !atCapacity(l) {
+			return false
+		}
+		busy = append(busy, apperrors.FieldError{
+			Field:  "candidates",
+			Reason: fmt.Sprintf("%s is at capacity: %d of %d open reviews", member.UserID, l.OpenReviews, l.MaxOpenReviews.Int32),
+		})
+		return true
+	})
+
+	// a PR requiring a senior prefers senior candidates while no other reviewer
+	// is one, the only senior reviewer is never replaced by a non-senior
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ReassignResponse{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "author_id", Reason: "user not found"})
+		}
+		return ReassignResponse{}, err
+	}
+	requireSenior, err := s.requiresSenior(ctx, author.TeamName)
+	if err != nil {
+		return ReassignResponse{}, err
+	}
+	otherSenior := slices.ContainsFunc(currentReviewers, func(id string) bool {
+		return id != oldUserID && domain.IsSenior(load[id].Level)
+	})
+	if requireSenior && !otherSenior {
+		seniors := slices.DeleteFunc(slices.Clone(candidates), func(member repo.User) bool { return !domain.IsSenior(load[member.UserID].Level) })
+		switch {
+		case len(seniors) > 0:
+			candidates = seniors
+		case len(candidates) > 0 && domain.IsSenior(load[oldUserID].Level):
+			return ReassignResponse{}, apperrors.ErrNoCandidate.WithDetails(append(busy, apperrors.FieldError{
+				Field:  "candidates",
+				Reason: "no senior candidate can replace the only senior reviewer",
+			})...)
+		}
+	}
+
 	// check if there are any candidates
 	if len(candidates) == 0 {
-		return ReassignResponse{}, apperrors.ErrNoCandidate
+		return ReassignResponse{}, apperrors.ErrNoCandidate.WithDetails(busy...)
 	}
 
-	// select random new reviewer
-	newReviewers := selectRandomReviewers(candidates, 1)
+	// select random new reviewer, a preferred one if there is any
+	newReviewers := selectPreferredReviewers(rules, candidates, 1)
 	if len(newReviewers) == 0 {
 		return ReassignResponse{}, apperrors.ErrNoCandidate
 	}
 	newReviewerID := newReviewers[0]
+	missingSenior := requireSenior && !otherSenior && !domain.IsSenior(load[newReviewerID].Level)
 
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
@@ -261,9 +797,10 @@ func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (Rea
 
 	// mark old reviewer as replaced
 	_, err = qtx.ReplaceReviewer(ctx, repo.ReplaceReviewerParams{
-		PrID:       prID,
-		ReviewerID: oldUserID,
-		ReplacedBy: pgtype.Text{String: newReviewerID, Valid: true},
+		PrRepository: pr.RepositoryKey,
+		PrID:         pr.PullRequestID,
+		ReviewerID:   oldUserID,
+		ReplacedBy:   pgtype.Text{String: newReviewerID, Valid: true},
 	})
 	if err != nil {
 		return ReassignResponse{}, err
@@ -271,8 +808,18 @@ func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (Rea
 
 	// assign new reviewer
 	_, err = qtx.AssignReviewer(ctx, repo.AssignReviewerParams{
-		PrID:       prID,
-		ReviewerID: newReviewerID,
+		PrRepository: pr.RepositoryKey,
+		PrID:         pr.PullRequestID,
+		ReviewerID:   newReviewerID,
+	})
+	if err != nil {
+		return ReassignResponse{}, err
+	}
+
+	err = qtx.SetPRMissingSenior(ctx, repo.SetPRMissingSeniorParams{
+		RepositoryKey: pr.RepositoryKey,
+		PullRequestID: pr.PullRequestID,
+		MissingSenior: missingSenior,
 	})
 	if err != nil {
 		return ReassignResponse{}, err
@@ -283,7 +830,7 @@ func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (Rea
 	}
 
 	// get updated reviewers list
-	reviewerIDs, err := s.repo.GetPRReviewers(ctx, prID)
+	reviewerIDs, err := s.repo.GetPRReviewers(ctx, repo.GetPRReviewersParams{PrRepository: pr.RepositoryKey, PrID: pr.PullRequestID})
 	if err != nil {
 		return ReassignResponse{}, err
 	}
@@ -300,42 +847,86 @@ func (s *svc) ReassignReviewer(ctx context.Context, prID, oldUserID string) (Rea
 			PullRequestName:   pr.PullRequestName,
 			AuthorID:          pr.AuthorID,
 			Status:            status,
+			Repository:        pr.Repository.String,
 			AssignedReviewers: reviewerIDs,
+			MissingSenior:     missingSenior,
 		},
 		ReplacedBy: newReviewerID,
 	}, nil
 }
 
-func (s *svc) GetUserReviews(ctx context.Context, userID string) (UserReviewsResponse, error) {
+func (s *svc) GetUserReviews(ctx context.Context, userID, repository string) (UserReviewsResponse, error) {
 	// validate input
 	if userID == "" {
 		return UserReviewsResponse{}, apperrors.ErrInvalidInput
 	}
+	if err := s.checkRepository(ctx, repository); err != nil {
+		return UserReviewsResponse{}, err
+	}
 
 	// get PRs where user is reviewer
-	prs, err := s.repo.GetPRsByReviewer(ctx, userID)
+	prs, err := s.repo.GetPRsByReviewer(ctx, repo.GetPRsByReviewerParams{ReviewerID: userID, Repository: repository})
 	if err != nil {
 		return UserReviewsResponse{}, err
 	}
 
-	// convert to short format
-	prShorts := make([]Short, len(prs))
+	return UserReviewsResponse{
+		UserID:       userID,
+		PullRequests: shorts(prs),
+	}, nil
+}
+
+// ListPRs returns the PRs ordered by ID, filtered by repository and status when they are set.
+func (s *svc) ListPRs(ctx context.Context, params repo.ListPRsParams) (ListResponse, error) {
+	if params.Status != "" && params.Status != string(repo.PrStatusEnumOPEN) && params.Status != string(repo.PrStatusEnumMERGED) {
+		return ListResponse{}, apperrors.InvalidField("status", "must be OPEN or MERGED")
+	}
+	if err := s.checkRepository(ctx, params.Repository); err != nil {
+		return ListResponse{}, err
+	}
+
+	prs, err := s.repo.ListPRs(ctx, params)
+	if err != nil {
+		return ListResponse{}, err
+	}
+
+	return ListResponse{PullRequests: shorts(prs)}, nil
+}
+
+// checkRepository fails with NOT_FOUND if a repository is given but not registered.
+func (s *svc) checkRepository(ctx context.Context, repository string) error {
+	if repository == "" {
+		return nil
+	}
+
+	if _, err := s.repo.GetRepository(ctx, repository); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "repository", Reason: "repository not found"})
+		}
+		return err
+	}
+
+	return nil
+}
+
+// shorts converts PRs to the short format.
+func shorts(prs []repo.PullRequest) []Short {
+	result := make([]Short, len(prs))
 	for i, pr := range prs {
 		status := "OPEN"
 		if pr.Status.Valid {
 			status = string(pr.Status.PrStatusEnum)
 		}
 
-		prShorts[i] = Short{
+		result[i] = Short{
 			PullRequestID:   pr.PullRequestID,
 			PullRequestName: pr.PullRequestName,
 			AuthorID:        pr.AuthorID,
 			Status:          status,
+			Repository:      pr.Repository.String,
+			MissingSenior:   pr.MissingSenior,
 		}
 	}
 
-	return UserReviewsResponse{
-		UserID:       userID,
-		PullRequests: prShorts,
-	}, nil
+	return result
 }
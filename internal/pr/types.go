@@ -5,16 +5,18 @@ import (
 	"context"
 
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Service defines the interface for the PR service.
 type Service interface {
-	CreatePR(ctx context.Context, createPRParams repo.CreatePRParams) (CreatePRResponse, error)
-	MergePR(ctx context.Context, prID string) (Response, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (ReassignResponse, error)
-	GetUserReviews(ctx context.Context, userID string) (UserReviewsResponse, error)
+	CreatePR(ctx context.Context, req CreatePRRequest) (CreatePRResponse, error)
+	MergePR(ctx context.Context, key domain.PRKey) (Response, error)
+	ReassignReviewer(ctx context.Context, key domain.PRKey, oldUserID string) (ReassignResponse, error)
+	GetUserReviews(ctx context.Context, userID, repository string) (UserReviewsResponse, error)
+	ListPRs(ctx context.Context, params repo.ListPRsParams) (ListResponse, error)
+	PreviewAssignment(ctx context.Context, req PreviewRequest) (PreviewResponse, error)
 }
 
 // Handler handles HTTP requests for the PR service.
@@ -30,24 +32,48 @@ func NewHandler(service Service) *Handler {
 }
 
 type svc struct {
-	repo *repo.Queries
-	db   *pgxpool.Pool
+	repo           *repo.Queries
+	db             postgres.DB
+	reviewersCount int
 }
 
 // NewService creates a new PR service.
-func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
+// reviewersCount is how many reviewers are assigned to a new PR.
+func NewService(repo *repo.Queries, db postgres.DB, reviewersCount int) Service {
 	return &svc{
-		repo: repo,
-		db:   db,
+		repo:           repo,
+		db:             db,
+		reviewersCount: reviewersCount,
 	}
 }
 
+// MaxChangedFiles is the maximum number of changed file paths of a new PR.
+const MaxChangedFiles = 3000
+
+// CreatePRRequest represents the request for creating a PR in the optional Repository,
+// the owners of ChangedFiles by its CODEOWNERS file are preferred as reviewers.
+type CreatePRRequest struct {
+	repo.CreatePRParams
+	Repository   string   `json:"repository"`
+	ChangedFiles []string `json:"changed_files"`
+}
+
+// PreviewRequest represents the request for previewing the reviewer candidates of a new PR.
+type PreviewRequest struct {
+	AuthorID     string   `json:"author_id"`
+	Repository   string   `json:"repository"`
+	ChangedFiles []string `json:"changed_files"`
+}
+
 // WithReviewers represents a PR with its assigned reviewers.
 type WithReviewers = domain.PRWithReviewers
 
-// CreatePRResponse represents the response for creating a PR.
+// CreatePRResponse represents the response for creating a PR, OwnerReviewers are the
+// assigned code owners and FallbackReviewers the assigned reviewers from fallback teams.
 type CreatePRResponse struct {
-	PR WithReviewers `json:"pr"`
+	PR                WithReviewers `json:"pr"`
+	OwnerReviewers    []string      `json:"owner_reviewers"`
+	FallbackReviewers []string      `json:"fallback_reviewers"`
 }
 
 // Response represents the response for getting a PR.
@@ -61,12 +87,14 @@ type ReassignResponse struct {
 	ReplacedBy string        `json:"replaced_by"`
 }
 
-// Short represents a short version of a PR.
+// Short represents a short version of a PR, MissingSenior as in WithReviewers.
 type Short struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
 	Status          string `json:"status"`
+	Repository      string `json:"repository,omitempty"`
+	MissingSenior   bool   `json:"missing_senior,omitempty"`
 }
 
 // UserReviewsResponse represents the response for getting user reviews.
@@ -74,3 +102,49 @@ type UserReviewsResponse struct {
 	UserID       string  `json:"user_id"`
 	PullRequests []Short `json:"pull_requests"`
 }
+
+// ListResponse represents the response for listing PRs.
+type ListResponse struct {
+	PullRequests []Short `json:"pull_requests"`
+}
+
+// Reasons a user is not a reviewer candidate.
+const (
+	ExcludedAuthor       = "author"
+	ExcludedInactive     = "inactive"
+	ExcludedAbsent       = "absent"
+	ExcludedOverCapacity = "over_capacity"
+	ExcludedPairRule     = "pair_rule"
+)
+
+// Exclusion is a user left out of the candidate pool: a team mate of the author,
+// an owner of the changed files or a member of a fallback team.
+type Exclusion struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Reason   string `json:"reason"`
+}
+
+// FallbackCandidate is a possible reviewer from a fallback team.
+type FallbackCandidate struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+// PreviewResponse represents the candidate pool CreatePR would pick reviewers from:
+// OwnerCandidates first, then Candidates, FallbackCandidates fill the reviewers missing
+// from the team in order of the fallback teams. With RequireSenior one of the
+// SeniorCandidates is picked when none of the others is. PreferredCandidates
+// are picked before the others of their group.
+type PreviewResponse struct {
+	AuthorID            string              `json:"author_id"`
+	TeamName            string              `json:"team_name"`
+	ReviewersCount      int                 `json:"reviewers_count"`
+	OwnerCandidates     []string            `json:"owner_candidates"`
+	Candidates          []string            `json:"candidates"`
+	Excluded            []Exclusion         `json:"excluded"`
+	FallbackCandidates  []FallbackCandidate `json:"fallback_candidates"`
+	RequireSenior       bool                `json:"require_senior"`
+	SeniorCandidates    []string            `json:"senior_candidates"`
+	PreferredCandidates []string            `json:"preferred_candidates"`
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Clients identifies the client of a request. A client is keyed by its API key
+// only when the key is one of the configured ones, anyone can send a new key and
+// would get a fresh bucket with it. Forwarded headers are only trusted from the
+// configured proxies, anyone can send them too. The zero value knows no keys
+// and trusts no proxies.
+type Clients struct {
+	keyHeader string
+	apiKeys   map[string]string // API key -> bucket key
+	proxies   []netip.Prefix
+}
+
+// NewClients creates a client identifier. trustedProxies are IP addresses or CIDR prefixes.
+func NewClients(keyHeader string, apiKeys, trustedProxies []string) (*Clients, error) {
+	c := &Clients{
+		keyHeader: keyHeader,
+		apiKeys:   make(map[string]string, len(apiKeys)),
+	}
+	for _, k := range apiKeys {
+		// keys are hashed so they are never stored as is
+		sum := sha256.Sum256([]byte(k))
+		c.apiKeys[k] = "key:" + hex.EncodeToString(sum[:8])
+	}
+	for _, p := range trustedProxies {
+		prefix, err := ParsePrefix(p)
+		if err != nil {
+			return nil, err
+		}
+		c.proxies = append(c.proxies, prefix)
+	}
+
+	return c, nil
+}
+
+// ParsePrefix parses a trusted proxy: an IP address or a CIDR prefix.
+func ParsePrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid proxy prefix %q", s)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid proxy address %q", s)
+	}
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// KeyHeader is the header (or gRPC metadata key) carrying the API key.
+func (c *Clients) KeyHeader() string {
+	return c.keyHeader
+}
+
+// Key returns the bucket key of a client: its API key when the server knows it,
+// its IP otherwise. remoteAddr is the client IP, with or without a port.
+func (c *Clients) Key(apiKey, remoteAddr string) string {
+	if key, ok := c.apiKeys[apiKey]; ok && apiKey != "" {
+		return key
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// ClientIP returns the IP of the client behind remoteAddr. When remoteAddr is a
+// trusted proxy, the X-Forwarded-For values are walked from the right and the
+// first address that is not a trusted proxy is the client, X-Real-IP is used
+// when there is no X-Forwarded-For. Otherwise the headers are ignored.
+func (c *Clients) ClientIP(remoteAddr string, forwardedFor []string, realIP string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	if !c.trusted(host) {
+		return host
+	}
+
+	var hops []string
+	for _, v := range forwardedFor {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	if len(hops) == 0 && realIP != "" {
+		hops = []string{strings.TrimSpace(realIP)}
+	}
+
+	client := host
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(hops[i])
+		if err != nil {
+			// a malformed hop was not written by a trusted proxy
+			break
+		}
+		client = addr.Unmap().String()
+		if !c.trusted(client) {
+			break
+		}
+	}
+
+	return client
+}
+
+// RealIP replaces RemoteAddr with the client IP found by ClientIP, so logs and
+// the rate limiter see the client rather than the proxy. It replaces chi's
+// middleware.RealIP, which trusts the headers of any sender.
+func (c *Clients) RealIP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RemoteAddr = c.ClientIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For"), r.Header.Get("X-Real-IP"))
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c *Clients) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, p := range c.proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,62 @@
+// Package ratelimit provides a token-bucket rate limiter for the HTTP API.
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+)
+
+// Limit configures a token bucket: it refills at RPS tokens per second
+// and holds at most Burst tokens.
+type Limit struct {
+	RPS   float64 `yaml:"rps"`
+	Burst int     `yaml:"burst"`
+}
+
+// Decision is the outcome of taking a token from a bucket.
+type Decision struct {
+	Allowed bool
+	// RetryAfter is how long to wait until a token is available, set when not allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter takes tokens from per-key buckets.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Decision, error)
+	// Cleanup forgets buckets that have not been used for longer than maxIdle.
+	Cleanup(ctx context.Context, maxIdle time.Duration) error
+}
+
+// decide builds a decision from the tokens left in a bucket after the request.
+func decide(allowed bool, tokens float64, limit Limit) Decision {
+	if allowed {
+		return Decision{Allowed: true}
+	}
+
+	missing := math.Max(1-tokens, 0)
+	return Decision{
+		Allowed:    false,
+		RetryAfter: time.Duration(missing / limit.RPS * float64(time.Second)),
+	}
+}
+
+// CleanupWorker returns a background job that periodically forgets idle buckets.
+func CleanupWorker(limiter Limiter, interval, maxIdle time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := limiter.Cleanup(ctx, maxIdle); err != nil {
+					slog.Error("failed to clean up rate limit buckets", "error", err)
+				}
+			}
+		}
+	}
+}
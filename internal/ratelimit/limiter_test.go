@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clock is a fake time source for Memory.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newMemory() (*Memory, *clock) {
+	c := &clock{now: time.Date(2025, 11, 23, 12, 0, 0, 0, time.UTC)}
+	m := NewMemory()
+	m.now = c.Now
+
+	return m, c
+}
+
+func TestMemory_Burst(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newMemory()
+	limit := Limit{RPS: 1, Burst: 3}
+
+	for i := range 3 {
+		d, err := m.Allow(ctx, "a", limit)
+		require.NoError(t, err)
+		assert.True(t, d.Allowed, "request %d", i)
+	}
+
+	d, err := m.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, time.Second, d.RetryAfter)
+
+	// other keys have their own buckets
+	d, err = m.Allow(ctx, "b", limit)
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+}
+
+func TestMemory_Refill(t *testing.T) {
+	ctx := context.Background()
+	m, c := newMemory()
+	limit := Limit{RPS: 2, Burst: 2}
+
+	for range 2 {
+		d, err := m.Allow(ctx, "a", limit)
+		require.NoError(t, err)
+		require.True(t, d.Allowed)
+	}
+
+	c.Advance(200 * time.Millisecond)
+	d, err := m.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+	// 0.4 tokens refilled, the missing 0.6 take 300ms at 2 RPS
+	assert.InDelta(t, 300*time.Millisecond, d.RetryAfter, float64(time.Millisecond))
+
+	c.Advance(300 * time.Millisecond)
+	d, err = m.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+
+	// a long pause refills no more than the burst
+	c.Advance(time.Hour)
+	for range 2 {
+		d, err = m.Allow(ctx, "a", limit)
+		require.NoError(t, err)
+		require.True(t, d.Allowed)
+	}
+	d, err = m.Allow(ctx, "a", limit)
+	require.NoError(t, err)
+	assert.False(t, d.Allowed)
+}
+
+func TestMemory_Cleanup(t *testing.T) {
+	ctx := context.Background()
+	m, c := newMemory()
+	limit := Limit{RPS: 1, Burst: 1}
+
+	_, err := m.Allow(ctx, "idle", limit)
+	require.NoError(t, err)
+	c.Advance(time.Minute)
+	_, err = m.Allow(ctx, "busy", limit)
+	require.NoError(t, err)
+
+	require.NoError(t, m.Cleanup(ctx, 30*time.Second))
+	assert.NotContains(t, m.buckets, "idle")
+	assert.Contains(t, m.buckets, "busy")
+
+	// a forgotten bucket starts full again
+	d, err := m.Allow(ctx, "idle", limit)
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Memory keeps buckets in process memory. Each replica limits independently.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewMemory creates an in-memory limiter.
+func NewMemory() *Memory {
+	return &Memory{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key if one is available.
+func (m *Memory) Allow(_ context.Context, key string, limit Limit) (Decision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		m.buckets[key] = b
+	}
+
+	// refill
+	elapsed := now.Sub(b.updated).Seconds()
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.RPS)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return decide(false, b.tokens, limit), nil
+	}
+	b.tokens--
+
+	return decide(true, b.tokens, limit), nil
+}
+
+// Cleanup removes buckets idle for longer than maxIdle.
+func (m *Memory) Cleanup(_ context.Context, maxIdle time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	threshold := m.now().Add(-maxIdle)
+	for key, b := range m.buckets {
+		if b.updated.Before(threshold) {
+			delete(m.buckets, key)
+		}
+	}
+
+	return nil
+}
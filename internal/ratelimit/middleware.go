@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+)
+
+// Middleware limits requests of a route group per client, see Clients.Key.
+// RemoteAddr must already be the client IP (Clients.RealIP).
+// Errors of the limiter backend are logged and the request is let through.
+func Middleware(limiter Limiter, group string, limit Limit, clients *Clients) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := group + ":" + clients.Key(r.Header.Get(clients.KeyHeader()), r.RemoteAddr)
+
+			decision, err := limiter.Allow(r.Context(), key, limit)
+			if err != nil {
+				slog.Error("rate limiter failed, letting the request through", "group", group, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !decision.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(RetryAfterSeconds(decision.RetryAfter)))
+				errors.WriteAppError(w, r, "rate limit exceeded", errors.ErrRateLimited)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RetryAfterSeconds rounds the wait up to whole seconds for the Retry-After header, at least 1.
+func RetryAfterSeconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 1)
+}
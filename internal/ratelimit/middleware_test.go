@@ -0,0 +1,145 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ok = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+})
+
+// serve sends a request from remoteAddr through the handler, headers are name, value pairs.
+func serve(h http.Handler, remoteAddr string, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = remoteAddr
+	for i := 0; i < len(headers); i += 2 {
+		r.Header.Add(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func newClients(t *testing.T, apiKeys, proxies []string) *Clients {
+	t.Helper()
+
+	c, err := NewClients("X-API-Key", apiKeys, proxies)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestMiddleware_RateLimited(t *testing.T) {
+	m, c := newMemory()
+	h := Middleware(m, "team", Limit{RPS: 0.5, Burst: 2}, &Clients{})(ok)
+
+	for range 2 {
+		assert.Equal(t, http.StatusNoContent, serve(h, "10.0.0.1:1234").Code)
+	}
+
+	w := serve(h, "10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	var body errors.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "RATE_LIMITED", body.Error.Code)
+
+	// another client is not affected
+	assert.Equal(t, http.StatusNoContent, serve(h, "10.0.0.2:1234").Code)
+
+	c.Advance(2 * time.Second)
+	assert.Equal(t, http.StatusNoContent, serve(h, "10.0.0.1:1234").Code)
+}
+
+func TestMiddleware_Groups(t *testing.T) {
+	m, _ := newMemory()
+	clients := &Clients{}
+	team := Middleware(m, "team", Limit{RPS: 1, Burst: 1}, clients)(ok)
+	stats := Middleware(m, "stats", Limit{RPS: 1, Burst: 3}, clients)(ok)
+
+	assert.Equal(t, http.StatusNoContent, serve(team, "10.0.0.1:1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(team, "10.0.0.1:1").Code)
+
+	// the groups have separate buckets with their own limits
+	for range 3 {
+		assert.Equal(t, http.StatusNoContent, serve(stats, "10.0.0.1:1").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve(stats, "10.0.0.1:1").Code)
+}
+
+func TestMiddleware_APIKeys(t *testing.T) {
+	m, _ := newMemory()
+	h := Middleware(m, "team", Limit{RPS: 1, Burst: 1}, newClients(t, []string{"known"}, nil))(ok)
+
+	assert.Equal(t, http.StatusNoContent, serve(h, "10.0.0.1:1", "X-API-Key", "known").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(h, "10.0.0.2:1", "X-API-Key", "known").Code)
+
+	// unknown keys do not get fresh buckets, the client is limited by IP
+	assert.Equal(t, http.StatusNoContent, serve(h, "10.0.0.3:1", "X-API-Key", "bot-1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(h, "10.0.0.3:1", "X-API-Key", "bot-2").Code)
+	assert.Len(t, m.buckets, 2)
+}
+
+func TestMiddleware_LimiterFailure(t *testing.T) {
+	h := Middleware(failing{}, "team", Limit{RPS: 1, Burst: 1}, &Clients{})(ok)
+	assert.Equal(t, http.StatusNoContent, serve(h, "10.0.0.1:1").Code)
+}
+
+type failing struct{}
+
+func (failing) Allow(context.Context, string, Limit) (Decision, error) {
+	return Decision{}, stderrors.New("database is down")
+}
+
+func (failing) Cleanup(context.Context, time.Duration) error {
+	return nil
+}
+
+func TestClients_RealIP(t *testing.T) {
+	clients := newClients(t, nil, []string{"10.0.0.0/8", "192.168.1.1"})
+	var seen string
+	h := clients.RealIP(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    []string
+		want       string
+	}{
+		{"direct client", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"headers of an untrusted sender", "203.0.113.7:5000", []string{"X-Forwarded-For", "1.2.3.4", "X-Real-IP", "1.2.3.5"}, "203.0.113.7"},
+		{"trusted proxy", "10.1.2.3:5000", []string{"X-Forwarded-For", "198.51.100.1"}, "198.51.100.1"},
+		{"spoofed hop before the proxy", "10.1.2.3:5000", []string{"X-Forwarded-For", "1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of trusted proxies", "192.168.1.1:5000", []string{"X-Forwarded-For", "198.51.100.1, 10.0.0.5"}, "198.51.100.1"},
+		{"several header lines", "10.1.2.3:5000", []string{"X-Forwarded-For", "1.2.3.4", "X-Forwarded-For", "198.51.100.1"}, "198.51.100.1"},
+		{"real ip header", "10.1.2.3:5000", []string{"X-Real-IP", "198.51.100.2"}, "198.51.100.2"},
+		{"malformed hop", "10.1.2.3:5000", []string{"X-Forwarded-For", "1.2.3.4, junk"}, "10.1.2.3"},
+		{"proxy without headers", "10.1.2.3:5000", nil, "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serve(h, tt.remoteAddr, tt.headers...)
+			assert.Equal(t, tt.want, seen)
+		})
+	}
+}
+
+func TestNewClients_InvalidProxy(t *testing.T) {
+	_, err := NewClients("X-API-Key", nil, []string{"10.0.0.0/33"})
+	require.Error(t, err)
+	_, err = NewClients("X-API-Key", nil, []string{"proxy.local"})
+	require.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// postgresTimeout bounds a round trip to the shared table, a slow database fails
+// the check (and lets the request through) instead of holding the request.
+const postgresTimeout = 100 * time.Millisecond
+
+// Postgres keeps buckets in the rate_limit_buckets table, so all replicas
+// share the same limits. Every check is a single atomic upsert.
+//
+// The repo should run on its own small pool: the limiter protects the request
+// pool and must not compete with the requests for it. A local bucket with the
+// same limit is checked first, a replica never lets through more than the
+// shared limit, so requests it denies never reach the database.
+type Postgres struct {
+	repo  *repo.Queries
+	local *Memory
+}
+
+// NewPostgres creates a limiter shared through the database.
+func NewPostgres(repo *repo.Queries) *Postgres {
+	return &Postgres{
+		repo:  repo,
+		local: NewMemory(),
+	}
+}
+
+// Allow takes a token from the bucket of key if one is available.
+func (p *Postgres) Allow(ctx context.Context, key string, limit Limit) (Decision, error) {
+	if local, _ := p.local.Allow(ctx, key, limit); !local.Allowed {
+		return local, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, postgresTimeout)
+	defer cancel()
+
+	row, err := p.repo.TakeRateLimitToken(ctx, repo.TakeRateLimitTokenParams{
+		BucketKey: key,
+		Burst:     float64(limit.Burst),
+		Rate:      limit.RPS,
+	})
+	if err != nil {
+		return Decision{}, err
+	}
+
+	return decide(row.Allowed, row.Tokens, limit), nil
+}
+
+// Cleanup removes buckets idle for longer than maxIdle.
+func (p *Postgres) Cleanup(ctx context.Context, maxIdle time.Duration) error {
+	_ = p.local.Cleanup(ctx, maxIdle)
+
+	_, err := p.repo.DeleteStaleRateLimitBuckets(ctx, pgtype.Timestamptz{
+		Time:  time.Now().Add(-maxIdle),
+		Valid: true,
+	})
+	return err
+}
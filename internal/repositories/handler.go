@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"net/http"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+)
+
+// SetTeam handles registering a repository or changing the team owning it.
+func (h *Handler) SetTeam(w http.ResponseWriter, r *http.Request) {
+	var req repo.SetRepositoryTeamParams
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in SetTeam", errors.InvalidJSON(err))
+		return
+	}
+
+	response, err := h.service.SetTeam(r.Context(), req)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to set repository team", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// List handles listing repositories, optionally of one team.
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.List(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to list repositories", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+)
+
+// SetTeam creates the repository owned by the team or hands an existing one over
+// to it, PRs already created in the repository keep their reviewers.
+func (s *svc) SetTeam(ctx context.Context, params repo.SetRepositoryTeamParams) (Response, error) {
+	// validation
+	var details []errors.FieldError
+	if params.RepositoryName == "" {
+		details = append(details, errors.FieldError{Field: "repository_name", Reason: "must not be empty"})
+	}
+	if params.TeamName == "" {
+		details = append(details, errors.FieldError{Field: "team_name", Reason: "must not be empty"})
+	}
+	if len(details) > 0 {
+		return Response{}, errors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	exists, err := s.repo.TeamExists(ctx, params.TeamName)
+	if err != nil {
+		return Response{}, err
+	}
+	if !exists {
+		return Response{}, errors.ErrNotFound.WithDetails(errors.FieldError{Field: "team_name", Reason: "team not found"})
+	}
+
+	r, err := s.repo.SetRepositoryTeam(ctx, params)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response(r), nil
+}
+
+func (s *svc) List(ctx context.Context, teamName string) (ListResponse, error) {
+	repos, err := s.repo.ListRepositories(ctx, teamName)
+	if err != nil {
+		return ListResponse{}, err
+	}
+
+	response := ListResponse{Repositories: make([]Response, len(repos))}
+	for i, r := range repos {
+		response.Repositories[i] = Response(r)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,141 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/repositories"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/pgtest"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(t *testing.T, teamNames ...string) repositories.Service {
+	t.Helper()
+
+	db := pgtest.Open(t)
+	q := repo.New(db)
+	for _, name := range teamNames {
+		_, err := teams.NewService(q, db).CreateTeam(context.Background(), teams.CreateTeamParams{
+			TeamName: name,
+			Members:  []teams.MemberParams{{UserID: name + "-1", Username: name, IsActive: true}},
+		})
+		require.NoError(t, err)
+	}
+
+	return repositories.NewService(q, db)
+}
+
+func TestSetTeam_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  repo.SetRepositoryTeamParams
+		code    *errors.AppError
+		details []errors.FieldError
+	}{
+		{
+			name:    "empty repository name",
+			params:  repo.SetRepositoryTeamParams{TeamName: "backend"},
+			code:    errors.ErrInvalidInput,
+			details: []errors.FieldError{{Field: "repository_name", Reason: "must not be empty"}},
+		},
+		{
+			name:   "every empty field is reported",
+			params: repo.SetRepositoryTeamParams{},
+			code:   errors.ErrInvalidInput,
+			details: []errors.FieldError{
+				{Field: "repository_name", Reason: "must not be empty"},
+				{Field: "team_name", Reason: "must not be empty"},
+			},
+		},
+		{
+			name:    "team that does not exist",
+			params:  repo.SetRepositoryTeamParams{RepositoryName: "api", TeamName: "ghost"},
+			code:    errors.ErrNotFound,
+			details: []errors.FieldError{{Field: "team_name", Reason: "team not found"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newService(t, "backend")
+
+			_, err := svc.SetTeam(context.Background(), tt.params)
+			require.ErrorIs(t, err, tt.code)
+			assert.Equal(t, tt.details, errors.From(err).Details)
+
+			// nothing is written on failure
+			list, err := svc.List(context.Background(), "")
+			require.NoError(t, err)
+			assert.Empty(t, list.Repositories)
+		})
+	}
+}
+
+func TestSetTeam_MovesRepository(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, "backend", "frontend")
+
+	created, err := svc.SetTeam(ctx, repo.SetRepositoryTeamParams{RepositoryName: "api", TeamName: "backend"})
+	require.NoError(t, err)
+	assert.Equal(t, repositories.Response{RepositoryName: "api", TeamName: "backend"}, created)
+
+	moved, err := svc.SetTeam(ctx, repo.SetRepositoryTeamParams{RepositoryName: "api", TeamName: "frontend"})
+	require.NoError(t, err)
+	assert.Equal(t, repositories.Response{RepositoryName: "api", TeamName: "frontend"}, moved)
+
+	list, err := svc.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []repositories.Response{{RepositoryName: "api", TeamName: "frontend"}}, list.Repositories)
+}
+
+func TestList_FiltersByTeam(t *testing.T) {
+	ctx := context.Background()
+	svc := newService(t, "backend", "frontend")
+	for _, r := range []repo.SetRepositoryTeamParams{
+		{RepositoryName: "web", TeamName: "frontend"},
+		{RepositoryName: "billing", TeamName: "backend"},
+		{RepositoryName: "api", TeamName: "backend"},
+	} {
+		_, err := svc.SetTeam(ctx, r)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		team string
+		want []repositories.Response
+	}{
+		{
+			team: "",
+			want: []repositories.Response{
+				{RepositoryName: "api", TeamName: "backend"},
+				{RepositoryName: "billing", TeamName: "backend"},
+				{RepositoryName: "web", TeamName: "frontend"},
+			},
+		},
+		{
+			team: "backend",
+			want: []repositories.Response{
+				{RepositoryName: "api", TeamName: "backend"},
+				{RepositoryName: "billing", TeamName: "backend"},
+			},
+		},
+		{
+			team: "frontend",
+			want: []repositories.Response{{RepositoryName: "web", TeamName: "frontend"}},
+		},
+		{
+			team: "ghost",
+			want: []repositories.Response{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run("team="+tt.team, func(t *testing.T) {
+			list, err := svc.List(ctx, tt.team)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, list.Repositories)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+// Package repositories provides handlers and service logic for repositories and the teams owning them.
+package repositories
+
+import (
+	"context"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+)
+
+// Service defines the interface for the repositories service.
+type Service interface {
+	// SetTeam registers the repository or moves it to another team.
+	SetTeam(ctx context.Context, params repo.SetRepositoryTeamParams) (Response, error)
+	// List returns the repositories ordered by name, only the team's if teamName is set.
+	List(ctx context.Context, teamName string) (ListResponse, error)
+}
+
+// Handler handles HTTP requests for the repositories service.
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new repositories handler.
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+type svc struct {
+	repo *repo.Queries
+	db   postgres.DB
+}
+
+// NewService creates a new repositories service.
+func NewService(repo *repo.Queries, db postgres.DB) Service {
+	return &svc{
+		repo: repo,
+		db:   db,
+	}
+}
+
+// Response represents a repository and the team owning it.
+type Response struct {
+	RepositoryName string `json:"repository_name"`
+	TeamName       string `json:"team_name"`
+}
+
+// ListResponse represents the response for listing repositories.
+type ListResponse struct {
+	Repositories []Response `json:"repositories"`
+}
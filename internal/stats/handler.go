@@ -10,9 +10,9 @@ import (
 
 // GetStats handles the retrieval of system statistics.
 func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := h.service.GetStats(r.Context())
+	stats, err := h.service.GetStats(r.Context(), r.URL.Query().Get("repository"))
 	if err != nil {
-		errors.WriteAppError(w, "failed to get stats", err)
+		errors.WriteAppError(w, r, "failed to get stats", err)
 		return
 	}
 
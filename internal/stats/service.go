@@ -2,11 +2,25 @@ package stats
 
 import (
 	"context"
+	"errors"
+
+	apperrors "github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
+	"github.com/jackc/pgx/v5"
 )
 
-func (s *svc) GetStats(ctx context.Context) (Response, error) {
+func (s *svc) GetStats(ctx context.Context, repository string) (Response, error) {
+	// Check the repository filter
+	if repository != "" {
+		if _, err := s.repo.GetRepository(ctx, repository); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return Response{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "repository", Reason: "repository not found"})
+			}
+			return Response{}, err
+		}
+	}
+
 	// Get top reviewers
-	reviewerStats, err := s.repo.GetReviewerStats(ctx)
+	reviewerStats, err := s.repo.GetReviewerStats(ctx, repository)
 	if err != nil {
 		return Response{}, err
 	}
@@ -20,7 +34,7 @@ func (s *svc) GetStats(ctx context.Context) (Response, error) {
 	}
 
 	// Get PR status stats
-	prStats, err := s.repo.GetPRStatusStats(ctx)
+	prStats, err := s.repo.GetPRStatusStats(ctx, repository)
 	if err != nil {
 		return Response{}, err
 	}
@@ -37,7 +51,7 @@ func (s *svc) GetStats(ctx context.Context) (Response, error) {
 		}
 	}
 
-	// Get total active users
+	// Get total active users, users belong to teams rather than repositories
 	totalActiveUsers, err := s.repo.GetTotalActiveUsers(ctx)
 	if err != nil {
 		return Response{}, err
@@ -3,13 +3,14 @@ package stats
 import (
 	"context"
 
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Service defines the interface for the stats service.
 type Service interface {
-	GetStats(ctx context.Context) (Response, error)
+	// GetStats computes the statistics of the repository's PRs, of all PRs if repository is empty.
+	GetStats(ctx context.Context, repository string) (Response, error)
 }
 
 // Handler handles HTTP requests for the stats service.
@@ -26,11 +27,11 @@ func NewHandler(service Service) *Handler {
 
 type svc struct {
 	repo *repo.Queries
-	db   *pgxpool.Pool
+	db   postgres.DB
 }
 
 // NewService creates a new stats service.
-func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
+func NewService(repo *repo.Queries, db postgres.DB) Service {
 	return &svc{
 		repo: repo,
 		db:   db,
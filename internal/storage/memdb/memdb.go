@@ -0,0 +1,182 @@
+// Package memdb is an in-memory stand-in for PostgreSQL used by tests.
+//
+// It does not parse SQL: every statement is recognised by its sqlc
+// "-- name: X" header and executed by a Go function in queries.go that
+// mirrors the SQL in queries.sql, constraints included. A query without
+// such a function fails, so the fake has to grow with queries.sql.
+package memdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// DB implements postgres.DB.
+//
+// Statements run one at a time. Transactions are serialised with each other
+// and rolled back by restoring a snapshot, statements outside of them are
+// not blocked by an open transaction (a service may read through the pool
+// while it holds one) and see its uncommitted changes.
+type DB struct {
+	mu    sync.Mutex
+	data  *store
+	txMu  sync.Mutex
+	clock func() time.Time
+}
+
+// New creates an empty database.
+func New() *DB {
+	return &DB{
+		data:  newStore(),
+		clock: time.Now,
+	}
+}
+
+// Close is a no-op, it lets DB stand in for *pgxpool.Pool.
+func (db *DB) Close() {}
+
+// Exec runs a :exec or :execrows query.
+func (db *DB) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	rows, err := db.run(sql, args)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return pgconn.NewCommandTag(fmt.Sprintf("MEMDB %d", len(rows))), nil
+}
+
+// Query runs a :many query.
+func (db *DB) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := db.run(sql, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resultRows{rows: rows, pos: -1}, nil
+}
+
+// QueryRow runs a :one query.
+func (db *DB) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	rows, err := db.run(sql, args)
+	return &resultRow{rows: rows, err: err}
+}
+
+// Begin starts a transaction, it waits for the one in progress to finish.
+func (db *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+	db.txMu.Lock()
+
+	db.mu.Lock()
+	snapshot := db.data.clone()
+	db.mu.Unlock()
+
+	return &tx{db: db, snapshot: snapshot}, nil
+}
+
+func (db *DB) run(sql string, args []any) ([][]any, error) {
+	name := queryName(sql)
+	q, ok := queries[name]
+	if !ok {
+		return nil, fmt.Errorf("memdb: query %q is not implemented", name)
+	}
+
+	if err := checkText(args); err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return q(db.data, db.clock(), args)
+}
+
+// checkText rejects NUL bytes, PostgreSQL cannot store them in text values.
+func checkText(args []any) error {
+	for _, a := range args {
+		var values []string
+		switch v := a.(type) {
+		case string:
+			values = []string{v}
+		case []string:
+			values = v
+		case pgtype.Text:
+			values = []string{v.String}
+		}
+
+		for _, v := range values {
+			if strings.IndexByte(v, 0) >= 0 {
+				return &pgconn.PgError{
+					Severity: "ERROR",
+					Code:     "22021",
+					Message:  `invalid byte sequence for encoding "UTF8": 0x00`,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// queryName extracts X from the "-- name: X :kind" header sqlc puts first.
+func queryName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) < 3 || fields[0] != "--" || fields[1] != "name:" {
+		return sql
+	}
+
+	return fields[2]
+}
+
+// tx is a transaction, pgx.Tx is embedded for the methods the services do not use.
+type tx struct {
+	pgx.Tx
+
+	db       *DB
+	snapshot *store
+	done     bool
+}
+
+func (t *tx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.db.Exec(ctx, sql, args...)
+}
+
+func (t *tx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.db.Query(ctx, sql, args...)
+}
+
+func (t *tx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.db.QueryRow(ctx, sql, args...)
+}
+
+func (t *tx) Commit(context.Context) error {
+	if t.done {
+		return pgx.ErrTxClosed
+	}
+	t.done = true
+	t.db.txMu.Unlock()
+
+	return nil
+}
+
+func (t *tx) Rollback(context.Context) error {
+	if t.done {
+		return pgx.ErrTxClosed
+	}
+	t.done = true
+
+	t.db.mu.Lock()
+	t.db.data = t.snapshot
+	t.db.mu.Unlock()
+	t.db.txMu.Unlock()
+
+	return nil
+}
+
+var _ postgres.DB = (*DB)(nil)
@@ -0,0 +1,65 @@
+package memdb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_RollbackRestoresData(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	q := repo.New(db)
+
+	_, err := q.CreateUser(ctx, repo.CreateUserParams{UserID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"})
+	require.NoError(t, err)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+	_, err = q.WithTx(tx).SetUserActivity(ctx, repo.SetUserActivityParams{UserID: "u1", IsActive: false})
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+	assert.ErrorIs(t, tx.Commit(ctx), pgx.ErrTxClosed)
+
+	user, err := q.GetUser(ctx, "u1")
+	require.NoError(t, err)
+	assert.True(t, user.IsActive)
+
+	_, err = q.GetUser(ctx, "ghost")
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestDB_Constraints(t *testing.T) {
+	ctx := context.Background()
+	q := repo.New(New())
+
+	_, err := q.CreatePR(ctx, repo.CreatePRParams{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "ghost"})
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr), "got %v", err)
+	assert.Equal(t, "23503", pgErr.Code)
+
+	_, err = q.CreateUser(ctx, repo.CreateUserParams{UserID: "u1", Username: "Alice", IsActive: true, TeamName: "backend"})
+	require.NoError(t, err)
+	_, err = q.CreateUser(ctx, repo.CreateUserParams{UserID: "u2", Username: "Bob", IsActive: true, TeamName: "backend"})
+	require.NoError(t, err)
+
+	pr, err := q.CreatePR(ctx, repo.CreatePRParams{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, repo.PrStatusEnumOPEN, pr.Status.PrStatusEnum)
+
+	// ON CONFLICT DO NOTHING returns no row
+	_, err = q.CreatePR(ctx, repo.CreatePRParams{PullRequestID: "pr-1", PullRequestName: "Again", AuthorID: "u1"})
+	assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = q.AssignReviewer(ctx, repo.AssignReviewerParams{PrID: "pr-1", ReviewerID: "u2"})
+	require.NoError(t, err)
+	_, err = q.AssignReviewer(ctx, repo.AssignReviewerParams{PrID: "pr-1", ReviewerID: "u2"})
+	require.True(t, errors.As(err, &pgErr), "got %v", err)
+	assert.Equal(t, "23505", pgErr.Code)
+}
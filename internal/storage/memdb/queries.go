@@ -0,0 +1,1144 @@
+package memdb
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// query executes one named statement and returns its result rows.
+type query func(s *store, now time.Time, args []any) ([][]any, error)
+
+// queries mirrors internal/storage/postgres/sqlc/queries.sql.
+var queries = map[string]query{
+	"CreateUser":                 createUser,
+	"TeamExists":                 teamExists,
+	"GetUser":                    getUser,
+	"GetTeam":                    getTeam,
+	"SetUserActivity":            setUserActivity,
+	"CreatePR":                   createPR,
+	"AssignReviewer":             assignReviewer,
+	"PRExists":                   prExists,
+	"GetPR":                      getPR,
+	"GetPRReviewers":             getPRReviewers,
+	"GetActiveTeamMembersExcept": getActiveTeamMembersExcept,
+	"MergePR":                    mergePR,
+	"CheckReviewerAssignment":    checkReviewerAssignment,
+	"ReplaceReviewer":            replaceReviewer,
+	"GetPRsByReviewer":           getPRsByReviewer,
+	"GetReviewerStats":           getReviewerStats,
+	"GetPRStatusStats":           getPRStatusStats,
+	"GetTotalActiveUsers":        getTotalActiveUsers,
+	"DeleteReviewer":             deleteReviewer,
+	"GetUsersByIDs":              getUsersByIDs,
+	"GetUsersByTeams":            getUsersByTeams,
+	"GetPRsByIDs":                getPRsByIDs,
+	"GetPRsByReviewers":          getPRsByReviewers,
+	"GetReviewersByPRs":          getReviewersByPRs,
+	"DeactivateUsers":            deactivateUsers,
+	"ReplaceReviewers":           replaceReviewers,
+	"AssignReviewers":            assignReviewers,
+	"DeleteReviewers":            deleteReviewers,
+	"LockOpenPRs":                lockOpenPRs,
+	"SetUsersActivity":           setUsersActivity,
+	"GetUsersWithHistory":        getUsersWithHistory,
+	"DeleteUsers":                deleteUsers,
+	"CreateAbsence":              createAbsence,
+	"GetUserAbsences":            getUserAbsences,
+	"DeleteAbsence":              deleteAbsence,
+	"GetAbsentUsers":             getAbsentUsers,
+	"ReleaseStartedAbsences":     releaseStartedAbsences,
+	"SetMaxOpenReviews":          setMaxOpenReviews,
+	"GetReviewLoad":              getReviewLoad,
+	"SetUserLevel":               setUserLevel,
+	"GetTeamFallbacks":           getTeamFallbacks,
+	"DeleteTeamFallbacks":        deleteTeamFallbacks,
+	"AddTeamFallbacks":           addTeamFallbacks,
+	"UpsertCodeOwners":           upsertCodeOwners,
+	"GetCodeOwners":              getCodeOwners,
+	"SetRepositoryTeam":          setRepositoryTeam,
+	"GetRepository":              getRepository,
+	"ListRepositories":           listRepositories,
+	"ListPRs":                    listPRs,
+	"GetTeamSettings":            getTeamSettings,
+	"SetTeamRequireSenior":       setTeamRequireSenior,
+	"GetPRsRequiringSenior":      getPRsRequiringSenior,
+	"SetPRMissingSenior":         setPRMissingSenior,
+	"SetPRsMissingSenior":        setPRsMissingSenior,
+	"GetTeamPairRules":           getTeamPairRules,
+	"SetTeamPairRule":            setTeamPairRule,
+	"DeleteTeamPairRule":         deleteTeamPairRule,
+	"GetPairRulesByAuthors":      getPairRulesByAuthors,
+}
+
+func createUser(s *store, _ time.Time, args []any) ([][]any, error) {
+	u := repo.User{
+		UserID:   arg[string](args, 0),
+		Username: arg[string](args, 1),
+		IsActive: arg[bool](args, 2),
+		TeamName: arg[string](args, 3),
+	}
+
+	if i, ok := s.user(u.UserID); ok {
+		s.users[i] = u
+	} else {
+		s.users = append(s.users, u)
+	}
+
+	return [][]any{userRow(u)}, nil
+}
+
+func teamExists(s *store, _ time.Time, args []any) ([][]any, error) {
+	team := arg[string](args, 0)
+	exists := slices.ContainsFunc(s.users, func(u repo.User) bool { return u.TeamName == team })
+
+	return [][]any{{exists}}, nil
+}
+
+func getUser(s *store, _ time.Time, args []any) ([][]any, error) {
+	i, ok := s.user(arg[string](args, 0))
+	if !ok {
+		return nil, nil
+	}
+
+	return [][]any{userRow(s.users[i])}, nil
+}
+
+func getTeam(s *store, _ time.Time, args []any) ([][]any, error) {
+	team := arg[string](args, 0)
+
+	var rows [][]any
+	for _, u := range s.users {
+		if u.TeamName == team {
+			rows = append(rows, userRow(u))
+		}
+	}
+
+	return rows, nil
+}
+
+func setUserActivity(s *store, _ time.Time, args []any) ([][]any, error) {
+	i, ok := s.user(arg[string](args, 0))
+	if !ok {
+		return nil, nil
+	}
+	s.users[i].IsActive = arg[bool](args, 1)
+
+	return [][]any{userRow(s.users[i])}, nil
+}
+
+func createPR(s *store, _ time.Time, args []any) ([][]any, error) {
+	pr := repo.PullRequest{
+		PullRequestID:   arg[string](args, 0),
+		PullRequestName: arg[string](args, 1),
+		AuthorID:        arg[string](args, 2),
+		Status:          repo.NullPrStatusEnum{PrStatusEnum: repo.PrStatusEnumOPEN, Valid: true},
+		Repository:      arg[pgtype.Text](args, 3),
+	}
+	pr.RepositoryKey = pr.Repository.String
+
+	if _, ok := s.pr(pr.RepositoryKey, pr.PullRequestID); ok {
+		return nil, nil
+	}
+	if err := s.foreignKey("pull_requests", "author_id", pr.AuthorID); err != nil {
+		return nil, err
+	}
+	if _, ok := s.repository(pr.Repository.String); pr.Repository.Valid && !ok {
+		return nil, &pgconn.PgError{
+			Severity:       "ERROR",
+			Code:           "23503",
+			Message:        `insert or update on table "pull_requests" violates foreign key constraint`,
+			Detail:         fmt.Sprintf("Key (repository)=(%s) is not present in table \"repositories\".", pr.Repository.String),
+			TableName:      "pull_requests",
+			ConstraintName: "pull_requests_repository_fkey",
+		}
+	}
+	s.prs = append(s.prs, pr)
+
+	return [][]any{prRow(pr)}, nil
+}
+
+func assignReviewer(s *store, now time.Time, args []any) ([][]any, error) {
+	prRepository, prID, reviewerID := arg[string](args, 0), arg[string](args, 1), arg[string](args, 2)
+
+	if _, ok := s.pr(prRepository, prID); !ok {
+		return nil, &pgconn.PgError{
+			Severity:       "ERROR",
+			Code:           "23503",
+			Message:        `insert or update on table "pr_reviewer_assignment" violates foreign key constraint`,
+			TableName:      "pr_reviewer_assignment",
+			ConstraintName: "pr_reviewer_assignment_pr_fkey",
+		}
+	}
+	if err := s.foreignKey("pr_reviewer_assignment", "reviewer_id", reviewerID); err != nil {
+		return nil, err
+	}
+	for _, a := range s.assignments {
+		if a.PrRepository == prRepository && a.PrID == prID && a.ReviewerID == reviewerID && !a.ReplacedBy.Valid {
+			return nil, &pgconn.PgError{
+				Severity:       "ERROR",
+				Code:           "23505",
+				Message:        `duplicate key value violates unique constraint "pr_reviewer_assignment_current_key"`,
+				TableName:      "pr_reviewer_assignment",
+				ConstraintName: "pr_reviewer_assignment_current_key",
+			}
+		}
+	}
+
+	s.seq++
+	s.assignments = append(s.assignments, repo.PrReviewerAssignment{
+		AssignmentID: fmt.Sprintf("a%d", s.seq),
+		PrID:         prID,
+		ReviewerID:   reviewerID,
+		AssignedAt:   pgtype.Timestamptz{Time: s.now(now), Valid: true},
+		PrRepository: prRepository,
+	})
+
+	return [][]any{{reviewerID}}, nil
+}
+
+func prExists(s *store, _ time.Time, args []any) ([][]any, error) {
+	_, ok := s.pr(arg[string](args, 0), arg[string](args, 1))
+	return [][]any{{ok}}, nil
+}
+
+func getPR(s *store, _ time.Time, args []any) ([][]any, error) {
+	i, ok := s.pr(arg[string](args, 0), arg[string](args, 1))
+	if !ok {
+		return nil, nil
+	}
+
+	return [][]any{prRow(s.prs[i])}, nil
+}
+
+func getPRReviewers(s *store, _ time.Time, args []any) ([][]any, error) {
+	prRepository, prID := arg[string](args, 0), arg[string](args, 1)
+
+	var rows [][]any
+	for _, a := range s.assignments {
+		if a.PrRepository == prRepository && a.PrID == prID && !a.ReplacedBy.Valid {
+			rows = append(rows, []any{a.ReviewerID})
+		}
+	}
+
+	return rows, nil
+}
+
+func getActiveTeamMembersExcept(s *store, _ time.Time, args []any) ([][]any, error) {
+	team, except := arg[string](args, 0), arg[string](args, 1)
+
+	var rows [][]any
+	for _, u := range s.users {
+		if u.TeamName == team && u.IsActive && u.UserID != except {
+			rows = append(rows, userRow(u))
+		}
+	}
+
+	return rows, nil
+}
+
+func mergePR(s *store, now time.Time, args []any) ([][]any, error) {
+	i, ok := s.pr(arg[string](args, 0), arg[string](args, 1))
+	if !ok {
+		return nil, nil
+	}
+
+	pr := &s.prs[i]
+	pr.Status = repo.NullPrStatusEnum{PrStatusEnum: repo.PrStatusEnumMERGED, Valid: true}
+	if !pr.MergedAt.Valid {
+		pr.MergedAt = pgtype.Timestamptz{Time: s.now(now), Valid: true}
+	}
+
+	return [][]any{prRow(*pr)}, nil
+}
+
+func checkReviewerAssignment(s *store, _ time.Time, args []any) ([][]any, error) {
+	prRepository, prID, reviewerID := arg[string](args, 0), arg[string](args, 1), arg[string](args, 2)
+	exists := slices.ContainsFunc(s.assignments, func(a repo.PrReviewerAssignment) bool {
+		return a.PrRepository == prRepository && a.PrID == prID && a.ReviewerID == reviewerID && !a.ReplacedBy.Valid
+	})
+
+	return [][]any{{exists}}, nil
+}
+
+func replaceReviewer(s *store, _ time.Time, args []any) ([][]any, error) {
+	prRepository, prID, reviewerID := arg[string](args, 0), arg[string](args, 1), arg[string](args, 2)
+	replacedBy := arg[pgtype.Text](args, 3)
+
+	if replacedBy.Valid {
+		if err := s.foreignKey("pr_reviewer_assignment", "replaced_by", replacedBy.String); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows [][]any
+	for i := range s.assignments {
+		a := &s.assignments[i]
+		if a.PrRepository != prRepository || a.PrID != prID || a.ReviewerID != reviewerID || a.ReplacedBy.Valid {
+			continue
+		}
+		a.ReplacedBy = replacedBy
+		rows = append(rows, []any{a.AssignmentID, a.PrID, a.ReviewerID, timeValue(a.AssignedAt), textValue(a.ReplacedBy), a.PrRepository})
+	}
+
+	return rows, nil
+}
+
+func getPRsByReviewer(s *store, _ time.Time, args []any) ([][]any, error) {
+	reviewerID, repository := arg[string](args, 0), arg[string](args, 1)
+
+	type key struct{ repository, pr string }
+	var rows [][]any
+	seen := make(map[key]bool)
+	for _, a := range s.assignments {
+		k := key{a.PrRepository, a.PrID}
+		if a.ReviewerID != reviewerID || a.ReplacedBy.Valid || seen[k] {
+			continue
+		}
+		seen[k] = true
+		if i, ok := s.pr(a.PrRepository, a.PrID); ok && inRepository(s.prs[i], repository) {
+			rows = append(rows, prRow(s.prs[i]))
+		}
+	}
+
+	return rows, nil
+}
+
+func getReviewerStats(s *store, _ time.Time, args []any) ([][]any, error) {
+	repository := arg[string](args, 0)
+
+	var order []string
+	counts := make(map[string]int64)
+	for _, a := range s.assignments {
+		if i, ok := s.pr(a.PrRepository, a.PrID); a.ReplacedBy.Valid || !ok || !inRepository(s.prs[i], repository) {
+			continue
+		}
+		if counts[a.ReviewerID] == 0 {
+			order = append(order, a.ReviewerID)
+		}
+		counts[a.ReviewerID]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	if len(order) > 10 {
+		order = order[:10]
+	}
+
+	rows := make([][]any, len(order))
+	for i, id := range order {
+		rows[i] = []any{id, counts[id]}
+	}
+
+	return rows, nil
+}
+
+func getPRStatusStats(s *store, _ time.Time, args []any) ([][]any, error) {
+	repository := arg[string](args, 0)
+
+	var order []any
+	counts := make(map[any]int64)
+	for _, pr := range s.prs {
+		if !inRepository(pr, repository) {
+			continue
+		}
+		status := statusValue(pr.Status)
+		if _, ok := counts[status]; !ok {
+			order = append(order, status)
+		}
+		counts[status]++
+	}
+
+	rows := make([][]any, len(order))
+	for i, status := range order {
+		rows[i] = []any{status, counts[status]}
+	}
+
+	return rows, nil
+}
+
+func getTotalActiveUsers(s *store, _ time.Time, _ []any) ([][]any, error) {
+	var count int64
+	for _, u := range s.users {
+		if u.IsActive {
+			count++
+		}
+	}
+
+	return [][]any{{count}}, nil
+}
+
+func deleteReviewer(s *store, _ time.Time, args []any) ([][]any, error) {
+	prRepository, prID, reviewerID := arg[string](args, 0), arg[string](args, 1), arg[string](args, 2)
+
+	var rows [][]any
+	s.assignments = slices.DeleteFunc(s.assignments, func(a repo.PrReviewerAssignment) bool {
+		if a.PrRepository == prRepository && a.PrID == prID && a.ReviewerID == reviewerID && !a.ReplacedBy.Valid {
+			rows = append(rows, nil)
+			return true
+		}
+		return false
+	})
+
+	return rows, nil
+}
+
+func getUsersByIDs(s *store, _ time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	var rows [][]any
+	for _, u := range s.users {
+		if slices.Contains(ids, u.UserID) {
+			rows = append(rows, userRow(u))
+		}
+	}
+
+	return rows, nil
+}
+
+func getUsersByTeams(s *store, _ time.Time, args []any) ([][]any, error) {
+	teams := arg[[]string](args, 0)
+
+	var users []repo.User
+	for _, u := range s.users {
+		if slices.Contains(teams, u.TeamName) {
+			users = append(users, u)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].TeamName != users[j].TeamName {
+			return users[i].TeamName < users[j].TeamName
+		}
+		return users[i].UserID < users[j].UserID
+	})
+
+	rows := make([][]any, len(users))
+	for i, u := range users {
+		rows[i] = userRow(u)
+	}
+
+	return rows, nil
+}
+
+func getPRsByIDs(s *store, _ time.Time, args []any) ([][]any, error) {
+	repositories, ids := arg[[]string](args, 0), arg[[]string](args, 1)
+
+	var rows [][]any
+	for _, pr := range s.prs {
+		if hasKey(repositories, ids, pr.RepositoryKey, pr.PullRequestID) {
+			rows = append(rows, prRow(pr))
+		}
+	}
+
+	return rows, nil
+}
+
+func getPRsByReviewers(s *store, _ time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	type key struct{ reviewer, pr, repository string }
+	seen := make(map[key]bool)
+	var keys []key
+	for _, a := range s.assignments {
+		k := key{a.ReviewerID, a.PrID, a.PrRepository}
+		if a.ReplacedBy.Valid || !slices.Contains(ids, a.ReviewerID) || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].reviewer != keys[j].reviewer {
+			return keys[i].reviewer < keys[j].reviewer
+		}
+		if keys[i].pr != keys[j].pr {
+			return keys[i].pr < keys[j].pr
+		}
+		return keys[i].repository < keys[j].repository
+	})
+
+	var rows [][]any
+	for _, k := range keys {
+		if i, ok := s.pr(k.repository, k.pr); ok {
+			rows = append(rows, append([]any{k.reviewer}, prRow(s.prs[i])...))
+		}
+	}
+
+	return rows, nil
+}
+
+func getReviewersByPRs(s *store, _ time.Time, args []any) ([][]any, error) {
+	repositories, ids := arg[[]string](args, 0), arg[[]string](args, 1)
+
+	var assignments []repo.PrReviewerAssignment
+	for _, a := range s.assignments {
+		if !a.ReplacedBy.Valid && hasKey(repositories, ids, a.PrRepository, a.PrID) {
+			assignments = append(assignments, a)
+		}
+	}
+	sort.SliceStable(assignments, func(i, j int) bool {
+		if assignments[i].PrRepository != assignments[j].PrRepository {
+			return assignments[i].PrRepository < assignments[j].PrRepository
+		}
+		if assignments[i].PrID != assignments[j].PrID {
+			return assignments[i].PrID < assignments[j].PrID
+		}
+		return assignments[i].AssignedAt.Time.Before(assignments[j].AssignedAt.Time)
+	})
+
+	rows := make([][]any, len(assignments))
+	for i, a := range assignments {
+		rows[i] = []any{a.PrRepository, a.PrID, a.ReviewerID}
+	}
+
+	return rows, nil
+}
+
+// arg returns the i-th query argument, the zero value if it is missing or of another type.
+func arg[T any](args []any, i int) T {
+	var zero T
+	if i >= len(args) {
+		return zero
+	}
+	v, ok := args[i].(T)
+	if !ok {
+		return zero
+	}
+
+	return v
+}
+
+// lockOpenPRs needs no locks, transactions are serialised.
+func lockOpenPRs(s *store, _ time.Time, args []any) ([][]any, error) {
+	repositories, ids := arg[[]string](args, 0), arg[[]string](args, 1)
+
+	var rows [][]any
+	for _, pr := range s.prs {
+		if hasKey(repositories, ids, pr.RepositoryKey, pr.PullRequestID) && pr.Status.PrStatusEnum == repo.PrStatusEnumOPEN {
+			rows = append(rows, []any{pr.RepositoryKey, pr.PullRequestID})
+		}
+	}
+	sortKeys(rows)
+
+	return rows, nil
+}
+
+// sortKeys orders (repository key, PR id) rows like ORDER BY repository_key, pull_request_id.
+func sortKeys(rows [][]any) {
+	slices.SortFunc(rows, func(a, b []any) int {
+		if c := strings.Compare(a[0].(string), b[0].(string)); c != 0 {
+			return c
+		}
+		return strings.Compare(a[1].(string), b[1].(string))
+	})
+}
+
+func deactivateUsers(s *store, _ time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	var rows [][]any
+	for i := range s.users {
+		if slices.Contains(ids, s.users[i].UserID) {
+			s.users[i].IsActive = false
+			rows = append(rows, nil)
+		}
+	}
+
+	return rows, nil
+}
+
+// The bulk statements below apply their single-row counterparts to the
+// unnested arrays on a copy of the store, so a failure leaves no partial writes.
+
+func replaceReviewers(s *store, now time.Time, args []any) ([][]any, error) {
+	prRepositories, prIDs := arg[[]string](args, 0), arg[[]string](args, 1)
+	reviewerIDs, replacedBy := arg[[]string](args, 2), arg[[]string](args, 3)
+
+	return unnest(s, len(prIDs), func(t *store, i int) ([][]any, error) {
+		return replaceReviewer(t, now, []any{prRepositories[i], prIDs[i], reviewerIDs[i], pgtype.Text{String: replacedBy[i], Valid: true}})
+	})
+}
+
+func assignReviewers(s *store, now time.Time, args []any) ([][]any, error) {
+	prRepositories, prIDs, reviewerIDs := arg[[]string](args, 0), arg[[]string](args, 1), arg[[]string](args, 2)
+
+	return unnest(s, len(prIDs), func(t *store, i int) ([][]any, error) {
+		return assignReviewer(t, now, []any{prRepositories[i], prIDs[i], reviewerIDs[i]})
+	})
+}
+
+func deleteReviewers(s *store, now time.Time, args []any) ([][]any, error) {
+	prRepositories, prIDs, reviewerIDs := arg[[]string](args, 0), arg[[]string](args, 1), arg[[]string](args, 2)
+
+	return unnest(s, len(prIDs), func(t *store, i int) ([][]any, error) {
+		return deleteReviewer(t, now, []any{prRepositories[i], prIDs[i], reviewerIDs[i]})
+	})
+}
+
+// unnest runs row for each of the n array elements as one atomic statement.
+func unnest(s *store, n int, row func(t *store, i int) ([][]any, error)) ([][]any, error) {
+	t := s.clone()
+
+	var rows [][]any
+	for i := range n {
+		r, err := row(t, i)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, r...)
+	}
+	*s = *t
+
+	return rows, nil
+}
+
+func setUsersActivity(s *store, now time.Time, args []any) ([][]any, error) {
+	ids, active := arg[[]string](args, 0), arg[[]bool](args, 1)
+
+	return unnest(s, len(ids), func(t *store, i int) ([][]any, error) {
+		return setUserActivity(t, now, []any{ids[i], active[i]})
+	})
+}
+
+func getUsersWithHistory(s *store, _ time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	var rows [][]any
+	for _, id := range ids {
+		if s.referenced(id) {
+			rows = append(rows, []any{id})
+		}
+	}
+
+	return rows, nil
+}
+
+func deleteUsers(s *store, _ time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	for _, id := range ids {
+		if _, ok := s.user(id); ok && s.referenced(id) {
+			return nil, &pgconn.PgError{
+				Severity:  "ERROR",
+				Code:      "23503",
+				Message:   `update or delete on table "users" violates foreign key constraint`,
+				Detail:    fmt.Sprintf("Key (user_id)=(%s) is still referenced.", id),
+				TableName: "users",
+			}
+		}
+	}
+
+	var rows [][]any
+	s.users = slices.DeleteFunc(s.users, func(u repo.User) bool {
+		if slices.Contains(ids, u.UserID) {
+			rows = append(rows, nil)
+			return true
+		}
+		return false
+	})
+	// ON DELETE CASCADE
+	s.absences = slices.DeleteFunc(s.absences, func(a repo.UserAbsence) bool {
+		return slices.Contains(ids, a.UserID)
+	})
+	s.settings = slices.DeleteFunc(s.settings, func(rs repo.ReviewerSetting) bool {
+		return slices.Contains(ids, rs.UserID)
+	})
+	s.pairRules = slices.DeleteFunc(s.pairRules, func(r repo.TeamPairRule) bool {
+		return slices.Contains(ids, r.ReviewerID) || slices.Contains(ids, r.AuthorID)
+	})
+
+	return rows, nil
+}
+
+func createAbsence(s *store, _ time.Time, args []any) ([][]any, error) {
+	a := repo.UserAbsence{
+		UserID:   arg[string](args, 0),
+		StartsAt: arg[pgtype.Timestamptz](args, 1),
+		EndsAt:   arg[pgtype.Timestamptz](args, 2),
+	}
+
+	if err := s.foreignKey("user_absences", "user_id", a.UserID); err != nil {
+		return nil, err
+	}
+	if !a.EndsAt.Time.After(a.StartsAt.Time) {
+		return nil, &pgconn.PgError{
+			Severity:       "ERROR",
+			Code:           "23514",
+			Message:        `new row for relation "user_absences" violates check constraint "user_absences_check"`,
+			TableName:      "user_absences",
+			ConstraintName: "user_absences_check",
+		}
+	}
+
+	s.seq++
+	a.AbsenceID = fmt.Sprintf("abs%d", s.seq)
+	s.absences = append(s.absences, a)
+
+	return [][]any{absenceRow(a)}, nil
+}
+
+func getUserAbsences(s *store, _ time.Time, args []any) ([][]any, error) {
+	userID := arg[string](args, 0)
+
+	var absences []repo.UserAbsence
+	for _, a := range s.absences {
+		if a.UserID == userID {
+			absences = append(absences, a)
+		}
+	}
+	sort.SliceStable(absences, func(i, j int) bool {
+		if !absences[i].StartsAt.Time.Equal(absences[j].StartsAt.Time) {
+			return absences[i].StartsAt.Time.Before(absences[j].StartsAt.Time)
+		}
+		return absences[i].EndsAt.Time.Before(absences[j].EndsAt.Time)
+	})
+
+	rows := make([][]any, len(absences))
+	for i, a := range absences {
+		rows[i] = absenceRow(a)
+	}
+
+	return rows, nil
+}
+
+func deleteAbsence(s *store, _ time.Time, args []any) ([][]any, error) {
+	id := arg[string](args, 0)
+
+	var rows [][]any
+	s.absences = slices.DeleteFunc(s.absences, func(a repo.UserAbsence) bool {
+		if a.AbsenceID == id {
+			rows = append(rows, absenceRow(a))
+			return true
+		}
+		return false
+	})
+
+	return rows, nil
+}
+
+func getAbsentUsers(s *store, now time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	var absent []string
+	for _, a := range s.absences {
+		if slices.Contains(ids, a.UserID) && absentAt(a, now) && !slices.Contains(absent, a.UserID) {
+			absent = append(absent, a.UserID)
+		}
+	}
+	slices.Sort(absent)
+
+	rows := make([][]any, len(absent))
+	for i, id := range absent {
+		rows[i] = []any{id}
+	}
+
+	return rows, nil
+}
+
+func releaseStartedAbsences(s *store, now time.Time, _ []any) ([][]any, error) {
+	var rows [][]any
+	for i := range s.absences {
+		a := &s.absences[i]
+		if !a.ReviewsReleased && absentAt(*a, now) {
+			a.ReviewsReleased = true
+			rows = append(rows, []any{a.UserID})
+		}
+	}
+
+	return rows, nil
+}
+
+func setMaxOpenReviews(s *store, _ time.Time, args []any) ([][]any, error) {
+	rs := s.setting(arg[string](args, 0))
+	rs.MaxOpenReviews = arg[pgtype.Int4](args, 1)
+
+	if err := s.foreignKey("reviewer_settings", "user_id", rs.UserID); err != nil {
+		return nil, err
+	}
+	if rs.MaxOpenReviews.Valid && rs.MaxOpenReviews.Int32 < 0 {
+		return nil, &pgconn.PgError{
+			Severity:       "ERROR",
+			Code:           "23514",
+			Message:        `new row for relation "reviewer_settings" violates check constraint "reviewer_settings_max_open_reviews_check"`,
+			TableName:      "reviewer_settings",
+			ConstraintName: "reviewer_settings_max_open_reviews_check",
+		}
+	}
+	s.putSetting(rs)
+
+	return [][]any{settingRow(rs)}, nil
+}
+
+func setUserLevel(s *store, _ time.Time, args []any) ([][]any, error) {
+	rs := s.setting(arg[string](args, 0))
+	rs.Level = arg[string](args, 1)
+
+	if err := s.foreignKey("reviewer_settings", "user_id", rs.UserID); err != nil {
+		return nil, err
+	}
+	if !slices.Contains([]string{"regular", "senior", "maintainer"}, rs.Level) {
+		return nil, &pgconn.PgError{
+			Severity:       "ERROR",
+			Code:           "23514",
+			Message:        `new row for relation "reviewer_settings" violates check constraint "reviewer_settings_level_check"`,
+			TableName:      "reviewer_settings",
+			ConstraintName: "reviewer_settings_level_check",
+		}
+	}
+	s.putSetting(rs)
+
+	return [][]any{settingRow(rs)}, nil
+}
+
+func getReviewLoad(s *store, _ time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	var found []string
+	for _, u := range s.users {
+		if slices.Contains(ids, u.UserID) {
+			found = append(found, u.UserID)
+		}
+	}
+	slices.Sort(found)
+
+	rows := make([][]any, len(found))
+	for i, id := range found {
+		rs := s.setting(id)
+
+		var open int64
+		for _, a := range s.assignments {
+			if a.ReviewerID != id || a.ReplacedBy.Valid {
+				continue
+			}
+			if j, ok := s.pr(a.PrRepository, a.PrID); ok && s.prs[j].Status.PrStatusEnum == repo.PrStatusEnumOPEN {
+				open++
+			}
+		}
+
+		rows[i] = []any{id, int4Value(rs.MaxOpenReviews), rs.Level, open}
+	}
+
+	return rows, nil
+}
+
+func getTeamFallbacks(s *store, _ time.Time, args []any) ([][]any, error) {
+	team := arg[string](args, 0)
+
+	var fallbacks []repo.TeamFallback
+	for _, f := range s.fallbacks {
+		if f.TeamName == team {
+			fallbacks = append(fallbacks, f)
+		}
+	}
+	slices.SortFunc(fallbacks, func(a, b repo.TeamFallback) int { return int(a.Position - b.Position) })
+
+	rows := make([][]any, len(fallbacks))
+	for i, f := range fallbacks {
+		rows[i] = []any{f.FallbackTeam}
+	}
+
+	return rows, nil
+}
+
+func deleteTeamFallbacks(s *store, _ time.Time, args []any) ([][]any, error) {
+	team := arg[string](args, 0)
+
+	var rows [][]any
+	s.fallbacks = slices.DeleteFunc(s.fallbacks, func(f repo.TeamFallback) bool {
+		if f.TeamName == team {
+			rows = append(rows, nil)
+			return true
+		}
+		return false
+	})
+
+	return rows, nil
+}
+
+func addTeamFallbacks(s *store, _ time.Time, args []any) ([][]any, error) {
+	team, fallbacks := arg[string](args, 0), arg[[]string](args, 1)
+
+	return unnest(s, len(fallbacks), func(t *store, i int) ([][]any, error) {
+		f := repo.TeamFallback{TeamName: team, Position: int32(i + 1), FallbackTeam: fallbacks[i]}
+		if f.FallbackTeam == f.TeamName {
+			return nil, &pgconn.PgError{
+				Severity:       "ERROR",
+				Code:           "23514",
+				Message:        `new row for relation "team_fallbacks" violates check constraint "team_fallbacks_check"`,
+				TableName:      "team_fallbacks",
+				ConstraintName: "team_fallbacks_check",
+			}
+		}
+		for _, x := range t.fallbacks {
+			if x.TeamName == f.TeamName && (x.FallbackTeam == f.FallbackTeam || x.Position == f.Position) {
+				return nil, &pgconn.PgError{
+					Severity:  "ERROR",
+					Code:      "23505",
+					Message:   `duplicate key value violates unique constraint on "team_fallbacks"`,
+					TableName: "team_fallbacks",
+				}
+			}
+		}
+		t.fallbacks = append(t.fallbacks, f)
+
+		return [][]any{nil}, nil
+	})
+}
+
+func upsertCodeOwners(s *store, now time.Time, args []any) ([][]any, error) {
+	c := repo.Codeowner{
+		Repository: arg[string](args, 0),
+		Content:    arg[string](args, 1),
+		UploadedAt: pgtype.Timestamptz{Time: s.now(now), Valid: true},
+	}
+
+	i := slices.IndexFunc(s.codeowners, func(x repo.Codeowner) bool { return x.Repository == c.Repository })
+	if i >= 0 {
+		s.codeowners[i] = c
+	} else {
+		s.codeowners = append(s.codeowners, c)
+	}
+
+	return [][]any{codeownerRow(c)}, nil
+}
+
+func getCodeOwners(s *store, _ time.Time, args []any) ([][]any, error) {
+	repository := arg[string](args, 0)
+
+	for _, c := range s.codeowners {
+		if c.Repository == repository {
+			return [][]any{codeownerRow(c)}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func repositoryRow(r repo.Repository) []any {
+	return []any{r.RepositoryName, r.TeamName}
+}
+
+func setRepositoryTeam(s *store, _ time.Time, args []any) ([][]any, error) {
+	r := repo.Repository{RepositoryName: arg[string](args, 0), TeamName: arg[string](args, 1)}
+
+	if i, ok := s.repository(r.RepositoryName); ok {
+		s.repos[i] = r
+	} else {
+		s.repos = append(s.repos, r)
+	}
+
+	return [][]any{repositoryRow(r)}, nil
+}
+
+func getRepository(s *store, _ time.Time, args []any) ([][]any, error) {
+	i, ok := s.repository(arg[string](args, 0))
+	if !ok {
+		return nil, nil
+	}
+
+	return [][]any{repositoryRow(s.repos[i])}, nil
+}
+
+func listRepositories(s *store, _ time.Time, args []any) ([][]any, error) {
+	team := arg[string](args, 0)
+
+	var rows [][]any
+	for _, r := range s.repos {
+		if team == "" || r.TeamName == team {
+			rows = append(rows, repositoryRow(r))
+		}
+	}
+	slices.SortFunc(rows, func(a, b []any) int { return strings.Compare(a[0].(string), b[0].(string)) })
+
+	return rows, nil
+}
+
+func listPRs(s *store, _ time.Time, args []any) ([][]any, error) {
+	repository, status := arg[string](args, 0), arg[string](args, 1)
+
+	var rows [][]any
+	for _, pr := range s.prs {
+		if inRepository(pr, repository) && (status == "" || statusValue(pr.Status) == status) {
+			rows = append(rows, prRow(pr))
+		}
+	}
+	slices.SortFunc(rows, func(a, b []any) int {
+		if c := strings.Compare(a[0].(string), b[0].(string)); c != 0 {
+			return c
+		}
+		return strings.Compare(a[6].(string), b[6].(string))
+	})
+
+	return rows, nil
+}
+
+func teamSettingRow(t repo.TeamSetting) []any {
+	return []any{t.TeamName, t.RequireSenior}
+}
+
+func getTeamSettings(s *store, _ time.Time, args []any) ([][]any, error) {
+	team := arg[string](args, 0)
+
+	for _, t := range s.teamSettings {
+		if t.TeamName == team {
+			return [][]any{teamSettingRow(t)}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func setTeamRequireSenior(s *store, _ time.Time, args []any) ([][]any, error) {
+	t := repo.TeamSetting{TeamName: arg[string](args, 0), RequireSenior: arg[bool](args, 1)}
+
+	i := slices.IndexFunc(s.teamSettings, func(x repo.TeamSetting) bool { return x.TeamName == t.TeamName })
+	if i >= 0 {
+		s.teamSettings[i] = t
+	} else {
+		s.teamSettings = append(s.teamSettings, t)
+	}
+
+	return [][]any{teamSettingRow(t)}, nil
+}
+
+func getPRsRequiringSenior(s *store, _ time.Time, args []any) ([][]any, error) {
+	repositories, ids := arg[[]string](args, 0), arg[[]string](args, 1)
+
+	var rows [][]any
+	for _, pr := range s.prs {
+		if !hasKey(repositories, ids, pr.RepositoryKey, pr.PullRequestID) {
+			continue
+		}
+		i, ok := s.user(pr.AuthorID)
+		if !ok {
+			continue
+		}
+		for _, t := range s.teamSettings {
+			if t.TeamName == s.users[i].TeamName && t.RequireSenior {
+				rows = append(rows, []any{pr.RepositoryKey, pr.PullRequestID})
+			}
+		}
+	}
+	sortKeys(rows)
+
+	return rows, nil
+}
+
+func setPRMissingSenior(s *store, _ time.Time, args []any) ([][]any, error) {
+	if i, ok := s.pr(arg[string](args, 0), arg[string](args, 1)); ok {
+		s.prs[i].MissingSenior = arg[bool](args, 2)
+	}
+
+	return nil, nil
+}
+
+func setPRsMissingSenior(s *store, now time.Time, args []any) ([][]any, error) {
+	repositories, ids, missing := arg[[]string](args, 0), arg[[]string](args, 1), arg[[]bool](args, 2)
+
+	return unnest(s, len(ids), func(t *store, i int) ([][]any, error) {
+		return setPRMissingSenior(t, now, []any{repositories[i], ids[i], missing[i]})
+	})
+}
+
+func pairRuleRow(r repo.TeamPairRule) []any {
+	return []any{r.TeamName, r.ReviewerID, r.AuthorID, r.Kind}
+}
+
+// samePair reports whether the rules are for the same team, reviewer and author.
+func samePair(a, b repo.TeamPairRule) bool {
+	return a.TeamName == b.TeamName && a.ReviewerID == b.ReviewerID && a.AuthorID == b.AuthorID
+}
+
+// sortPairRules orders rule rows by author_id, reviewer_id.
+func sortPairRules(rows [][]any) {
+	slices.SortFunc(rows, func(a, b []any) int {
+		if c := strings.Compare(a[2].(string), b[2].(string)); c != 0 {
+			return c
+		}
+		return strings.Compare(a[1].(string), b[1].(string))
+	})
+}
+
+func getTeamPairRules(s *store, _ time.Time, args []any) ([][]any, error) {
+	team := arg[string](args, 0)
+
+	var rows [][]any
+	for _, r := range s.pairRules {
+		if r.TeamName == team {
+			rows = append(rows, pairRuleRow(r))
+		}
+	}
+	sortPairRules(rows)
+
+	return rows, nil
+}
+
+func setTeamPairRule(s *store, _ time.Time, args []any) ([][]any, error) {
+	r := repo.TeamPairRule{
+		TeamName:   arg[string](args, 0),
+		ReviewerID: arg[string](args, 1),
+		AuthorID:   arg[string](args, 2),
+		Kind:       arg[string](args, 3),
+	}
+
+	if err := s.foreignKey("team_pair_rules", "reviewer_id", r.ReviewerID); err != nil {
+		return nil, err
+	}
+	if err := s.foreignKey("team_pair_rules", "author_id", r.AuthorID); err != nil {
+		return nil, err
+	}
+	if r.ReviewerID == r.AuthorID || (r.Kind != "never" && r.Kind != "prefer") {
+		return nil, &pgconn.PgError{
+			Severity:  "ERROR",
+			Code:      "23514",
+			Message:   `new row for relation "team_pair_rules" violates check constraint`,
+			TableName: "team_pair_rules",
+		}
+	}
+
+	i := slices.IndexFunc(s.pairRules, func(x repo.TeamPairRule) bool { return samePair(x, r) })
+	if i >= 0 {
+		s.pairRules[i] = r
+	} else {
+		s.pairRules = append(s.pairRules, r)
+	}
+
+	return [][]any{pairRuleRow(r)}, nil
+}
+
+func deleteTeamPairRule(s *store, _ time.Time, args []any) ([][]any, error) {
+	key := repo.TeamPairRule{TeamName: arg[string](args, 0), ReviewerID: arg[string](args, 1), AuthorID: arg[string](args, 2)}
+
+	var rows [][]any
+	s.pairRules = slices.DeleteFunc(s.pairRules, func(r repo.TeamPairRule) bool {
+		if samePair(r, key) {
+			rows = append(rows, pairRuleRow(r))
+			return true
+		}
+		return false
+	})
+
+	return rows, nil
+}
+
+func getPairRulesByAuthors(s *store, _ time.Time, args []any) ([][]any, error) {
+	ids := arg[[]string](args, 0)
+
+	var rows [][]any
+	for _, r := range s.pairRules {
+		if !slices.Contains(ids, r.AuthorID) {
+			continue
+		}
+		if i, ok := s.user(r.AuthorID); ok && s.users[i].TeamName == r.TeamName {
+			rows = append(rows, pairRuleRow(r))
+		}
+	}
+	sortPairRules(rows)
+
+	return rows, nil
+}
@@ -0,0 +1,107 @@
+package memdb
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// resultRows implements pgx.Rows over precomputed values.
+type resultRows struct {
+	pgx.Rows
+
+	rows [][]any
+	pos  int
+	err  error
+}
+
+func (r *resultRows) Next() bool {
+	r.pos++
+	return r.pos < len(r.rows)
+}
+
+func (r *resultRows) Scan(dest ...any) error {
+	if r.pos < 0 || r.pos >= len(r.rows) {
+		return fmt.Errorf("memdb: Scan called without a current row")
+	}
+	if err := scan(r.rows[r.pos], dest); err != nil {
+		r.err = err
+		return err
+	}
+
+	return nil
+}
+
+func (r *resultRows) Values() ([]any, error) {
+	return r.rows[r.pos], nil
+}
+
+func (r *resultRows) Err() error {
+	return r.err
+}
+
+func (r *resultRows) Close() {}
+
+func (r *resultRows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag(fmt.Sprintf("MEMDB %d", len(r.rows)))
+}
+
+// resultRow implements pgx.Row, it is the first row of a result.
+type resultRow struct {
+	rows [][]any
+	err  error
+}
+
+func (r *resultRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.rows) == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return scan(r.rows[0], dest)
+}
+
+// scan assigns values to destinations the way pgx does for the types sqlc generates:
+// sql.Scanner implementations (pgtype, enums) get the raw value, plain pointers get it assigned.
+func scan(values []any, dest []any) error {
+	if len(values) != len(dest) {
+		return fmt.Errorf("memdb: %d values scanned into %d destinations", len(values), len(dest))
+	}
+
+	for i, v := range values {
+		if s, ok := dest[i].(sql.Scanner); ok {
+			if err := s.Scan(v); err != nil {
+				return fmt.Errorf("memdb: scan column %d: %w", i, err)
+			}
+			continue
+		}
+
+		ptr := reflect.ValueOf(dest[i])
+		if ptr.Kind() != reflect.Pointer || ptr.IsNil() {
+			return fmt.Errorf("memdb: column %d: destination %T is not a pointer", i, dest[i])
+		}
+		target := ptr.Elem()
+
+		if v == nil {
+			target.Set(reflect.Zero(target.Type()))
+			continue
+		}
+
+		val := reflect.ValueOf(v)
+		switch {
+		case val.Type().AssignableTo(target.Type()):
+			target.Set(val)
+		case val.Type().ConvertibleTo(target.Type()):
+			target.Set(val.Convert(target.Type()))
+		default:
+			return fmt.Errorf("memdb: column %d: cannot scan %T into %T", i, v, dest[i])
+		}
+	}
+
+	return nil
+}
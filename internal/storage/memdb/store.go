@@ -0,0 +1,216 @@
+package memdb
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// store holds the tables in insertion order, which is also the order
+// unordered queries return rows in.
+type store struct {
+	users        []repo.User
+	prs          []repo.PullRequest
+	assignments  []repo.PrReviewerAssignment
+	absences     []repo.UserAbsence
+	settings     []repo.ReviewerSetting
+	fallbacks    []repo.TeamFallback
+	codeowners   []repo.Codeowner
+	repos        []repo.Repository
+	teamSettings []repo.TeamSetting
+	pairRules    []repo.TeamPairRule
+	seq          int
+	lastTime     time.Time
+}
+
+func newStore() *store {
+	return &store{}
+}
+
+func (s *store) clone() *store {
+	return &store{
+		users:        append([]repo.User(nil), s.users...),
+		prs:          append([]repo.PullRequest(nil), s.prs...),
+		assignments:  append([]repo.PrReviewerAssignment(nil), s.assignments...),
+		absences:     append([]repo.UserAbsence(nil), s.absences...),
+		settings:     append([]repo.ReviewerSetting(nil), s.settings...),
+		fallbacks:    append([]repo.TeamFallback(nil), s.fallbacks...),
+		codeowners:   append([]repo.Codeowner(nil), s.codeowners...),
+		repos:        append([]repo.Repository(nil), s.repos...),
+		teamSettings: append([]repo.TeamSetting(nil), s.teamSettings...),
+		pairRules:    append([]repo.TeamPairRule(nil), s.pairRules...),
+		seq:          s.seq,
+		lastTime:     s.lastTime,
+	}
+}
+
+func (s *store) user(id string) (int, bool) {
+	for i, u := range s.users {
+		if u.UserID == id {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// pr finds a PR by its repository key and id, the key is empty for PRs without a repository.
+func (s *store) pr(repository, id string) (int, bool) {
+	for i, pr := range s.prs {
+		if pr.RepositoryKey == repository && pr.PullRequestID == id {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+func (s *store) repository(name string) (int, bool) {
+	for i, r := range s.repos {
+		if r.RepositoryName == name {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// setting returns the reviewer settings of the user, the column defaults if there is no row.
+func (s *store) setting(userID string) repo.ReviewerSetting {
+	for _, rs := range s.settings {
+		if rs.UserID == userID {
+			return rs
+		}
+	}
+
+	return repo.ReviewerSetting{UserID: userID, Level: "regular"}
+}
+
+func (s *store) putSetting(rs repo.ReviewerSetting) {
+	i := slices.IndexFunc(s.settings, func(x repo.ReviewerSetting) bool { return x.UserID == rs.UserID })
+	if i >= 0 {
+		s.settings[i] = rs
+	} else {
+		s.settings = append(s.settings, rs)
+	}
+}
+
+// now returns a strictly increasing timestamp so that ORDER BY assigned_at is stable.
+func (s *store) now(clock time.Time) time.Time {
+	if !clock.After(s.lastTime) {
+		clock = s.lastTime.Add(time.Microsecond)
+	}
+	s.lastTime = clock
+
+	return clock
+}
+
+// foreignKey fails like PostgreSQL does when a referenced user is missing.
+func (s *store) foreignKey(table, column, userID string) error {
+	if _, ok := s.user(userID); ok {
+		return nil
+	}
+
+	return &pgconn.PgError{
+		Severity:       "ERROR",
+		Code:           "23503",
+		Message:        fmt.Sprintf("insert or update on table %q violates foreign key constraint", table),
+		Detail:         fmt.Sprintf("Key (%s)=(%s) is not present in table \"users\".", column, userID),
+		TableName:      table,
+		ConstraintName: fmt.Sprintf("%s_%s_fkey", table, column),
+	}
+}
+
+// referenced reports whether a PR or an assignment points at the user.
+func (s *store) referenced(userID string) bool {
+	for _, pr := range s.prs {
+		if pr.AuthorID == userID {
+			return true
+		}
+	}
+	for _, a := range s.assignments {
+		if a.ReviewerID == userID || (a.ReplacedBy.Valid && a.ReplacedBy.String == userID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func settingRow(rs repo.ReviewerSetting) []any {
+	return []any{rs.UserID, int4Value(rs.MaxOpenReviews), rs.Level}
+}
+
+func userRow(u repo.User) []any {
+	return []any{u.UserID, u.Username, u.IsActive, u.TeamName}
+}
+
+func absenceRow(a repo.UserAbsence) []any {
+	return []any{a.AbsenceID, a.UserID, timeValue(a.StartsAt), timeValue(a.EndsAt), a.ReviewsReleased}
+}
+
+// absentAt reports whether the absence covers the moment t.
+func absentAt(a repo.UserAbsence, t time.Time) bool {
+	return !a.StartsAt.Time.After(t) && a.EndsAt.Time.After(t)
+}
+
+func codeownerRow(c repo.Codeowner) []any {
+	return []any{c.Repository, c.Content, timeValue(c.UploadedAt)}
+}
+
+func prRow(pr repo.PullRequest) []any {
+	return []any{pr.PullRequestID, pr.PullRequestName, pr.AuthorID, statusValue(pr.Status), timeValue(pr.MergedAt), textValue(pr.Repository), pr.RepositoryKey, pr.MissingSenior}
+}
+
+// hasKey reports whether the (repository key, id) pair is one of the unnested key arrays.
+func hasKey(repositories, ids []string, repository, id string) bool {
+	for i := range ids {
+		if i < len(repositories) && repositories[i] == repository && ids[i] == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inRepository reports whether the PR passes the optional repository filter, an empty one matches every PR.
+func inRepository(pr repo.PullRequest, repository string) bool {
+	return repository == "" || pr.Repository.Valid && pr.Repository.String == repository
+}
+
+func statusValue(s repo.NullPrStatusEnum) any {
+	if !s.Valid {
+		return nil
+	}
+
+	return string(s.PrStatusEnum)
+}
+
+func timeValue(t pgtype.Timestamptz) any {
+	if !t.Valid {
+		return nil
+	}
+
+	return t.Time
+}
+
+// int4Value is an INTEGER as database/sql drivers return it.
+func int4Value(n pgtype.Int4) any {
+	if !n.Valid {
+		return nil
+	}
+
+	return int64(n.Int32)
+}
+
+func textValue(t pgtype.Text) any {
+	if !t.Valid {
+		return nil
+	}
+
+	return t.String
+}
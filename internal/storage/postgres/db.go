@@ -0,0 +1,17 @@
+// Package postgres holds the PostgreSQL storage: migrations, sqlc queries
+// and the connection the services run them on.
+package postgres
+
+import (
+	"context"
+
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5"
+)
+
+// DB is the part of the connection pool the services use.
+// *pgxpool.Pool implements it, tests use the in-memory memdb.DB.
+type DB interface {
+	repo.DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
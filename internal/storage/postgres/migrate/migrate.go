@@ -0,0 +1,61 @@
+// Package migrate applies the embedded database migrations using goose.
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/migrations"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// Migrator runs goose migrations against the given pool.
+// A Postgres advisory lock is held while migrating, so several replicas
+// starting at the same time apply migrations only once.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// New creates a new Migrator using the embedded migrations.
+func New(pool *pgxpool.Pool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("create session locker: %w", err)
+	}
+
+	provider, err := goose.NewProvider(
+		goose.DialectPostgres,
+		stdlib.OpenDBFromPool(pool),
+		migrations.FS,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create goose provider: %w", err)
+	}
+
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls back the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
+
+// Status returns the state of every known migration.
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Close releases the database handle used by the migrator.
+// The underlying pool stays open.
+func (m *Migrator) Close() error {
+	return m.provider.Close()
+}
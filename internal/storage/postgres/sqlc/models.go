@@ -53,12 +53,19 @@ func (ns NullPrStatusEnum) Value() (driver.Value, error) {
 	return string(ns.PrStatusEnum), nil
 }
 
+type Codeowner struct {
+	Repository string             `json:"repository"`
+	Content    string             `json:"content"`
+	UploadedAt pgtype.Timestamptz `json:"uploaded_at"`
+}
+
 type PrReviewerAssignment struct {
 	AssignmentID string             `json:"assignment_id"`
 	PrID         string             `json:"pr_id"`
 	ReviewerID   string             `json:"reviewer_id"`
 	AssignedAt   pgtype.Timestamptz `json:"assigned_at"`
 	ReplacedBy   pgtype.Text        `json:"replaced_by"`
+	PrRepository string             `json:"pr_repository"`
 }
 
 type PullRequest struct {
@@ -67,6 +74,45 @@ type PullRequest struct {
 	AuthorID        string             `json:"author_id"`
 	Status          NullPrStatusEnum   `json:"status"`
 	MergedAt        pgtype.Timestamptz `json:"merged_at"`
+	Repository      pgtype.Text        `json:"repository"`
+	RepositoryKey   string             `json:"repository_key"`
+	MissingSenior   bool               `json:"missing_senior"`
+}
+
+type RateLimitBucket struct {
+	BucketKey string             `json:"bucket_key"`
+	Tokens    float64            `json:"tokens"`
+	Allowed   bool               `json:"allowed"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Repository struct {
+	RepositoryName string `json:"repository_name"`
+	TeamName       string `json:"team_name"`
+}
+
+type ReviewerSetting struct {
+	UserID         string      `json:"user_id"`
+	MaxOpenReviews pgtype.Int4 `json:"max_open_reviews"`
+	Level          string      `json:"level"`
+}
+
+type TeamFallback struct {
+	TeamName     string `json:"team_name"`
+	Position     int32  `json:"position"`
+	FallbackTeam string `json:"fallback_team"`
+}
+
+type TeamPairRule struct {
+	TeamName   string `json:"team_name"`
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Kind       string `json:"kind"`
+}
+
+type TeamSetting struct {
+	TeamName      string `json:"team_name"`
+	RequireSenior bool   `json:"require_senior"`
 }
 
 type User struct {
@@ -75,3 +121,11 @@ type User struct {
 	IsActive bool   `json:"is_active"`
 	TeamName string `json:"team_name"`
 }
+
+type UserAbsence struct {
+	AbsenceID       string             `json:"absence_id"`
+	UserID          string             `json:"user_id"`
+	StartsAt        pgtype.Timestamptz `json:"starts_at"`
+	EndsAt          pgtype.Timestamptz `json:"ends_at"`
+	ReviewsReleased bool               `json:"reviews_released"`
+}
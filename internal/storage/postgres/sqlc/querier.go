@@ -6,28 +6,71 @@ package repo
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AddTeamFallbacks(ctx context.Context, arg AddTeamFallbacksParams) error
 	AssignReviewer(ctx context.Context, arg AssignReviewerParams) (string, error)
+	AssignReviewers(ctx context.Context, arg AssignReviewersParams) error
 	CheckReviewerAssignment(ctx context.Context, arg CheckReviewerAssignmentParams) (bool, error)
+	CreateAbsence(ctx context.Context, arg CreateAbsenceParams) (UserAbsence, error)
 	CreatePR(ctx context.Context, arg CreatePRParams) (PullRequest, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeactivateUsers(ctx context.Context, userIds []string) error
+	DeleteAbsence(ctx context.Context, absenceID string) (UserAbsence, error)
 	DeleteReviewer(ctx context.Context, arg DeleteReviewerParams) error
+	DeleteReviewers(ctx context.Context, arg DeleteReviewersParams) error
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) (int64, error)
+	DeleteTeamFallbacks(ctx context.Context, teamName string) error
+	DeleteTeamPairRule(ctx context.Context, arg DeleteTeamPairRuleParams) (TeamPairRule, error)
+	DeleteUsers(ctx context.Context, userIds []string) error
+	GetAbsentUsers(ctx context.Context, userIds []string) ([]string, error)
 	GetActiveTeamMembersExcept(ctx context.Context, arg GetActiveTeamMembersExceptParams) ([]User, error)
-	GetPR(ctx context.Context, pullRequestID string) (PullRequest, error)
-	GetPRReviewers(ctx context.Context, prID string) ([]string, error)
-	GetPRStatusStats(ctx context.Context) ([]GetPRStatusStatsRow, error)
-	GetPRsByReviewer(ctx context.Context, reviewerID string) ([]PullRequest, error)
-	GetReviewerStats(ctx context.Context) ([]GetReviewerStatsRow, error)
+	GetCodeOwners(ctx context.Context, repository string) (Codeowner, error)
+	GetPR(ctx context.Context, arg GetPRParams) (PullRequest, error)
+	GetPRReviewers(ctx context.Context, arg GetPRReviewersParams) ([]string, error)
+	GetPRStatusStats(ctx context.Context, repository string) ([]GetPRStatusStatsRow, error)
+	GetPRsByIDs(ctx context.Context, arg GetPRsByIDsParams) ([]PullRequest, error)
+	GetPRsByReviewer(ctx context.Context, arg GetPRsByReviewerParams) ([]PullRequest, error)
+	GetPRsByReviewers(ctx context.Context, reviewerIds []string) ([]GetPRsByReviewersRow, error)
+	GetPRsRequiringSenior(ctx context.Context, arg GetPRsRequiringSeniorParams) ([]GetPRsRequiringSeniorRow, error)
+	GetPairRulesByAuthors(ctx context.Context, authorIds []string) ([]TeamPairRule, error)
+	GetRepository(ctx context.Context, repositoryName string) (Repository, error)
+	GetReviewLoad(ctx context.Context, userIds []string) ([]GetReviewLoadRow, error)
+	GetReviewerStats(ctx context.Context, repository string) ([]GetReviewerStatsRow, error)
+	GetReviewersByPRs(ctx context.Context, arg GetReviewersByPRsParams) ([]GetReviewersByPRsRow, error)
 	GetTeam(ctx context.Context, teamName string) ([]User, error)
+	GetTeamFallbacks(ctx context.Context, teamName string) ([]string, error)
+	GetTeamPairRules(ctx context.Context, teamName string) ([]TeamPairRule, error)
+	GetTeamSettings(ctx context.Context, teamName string) (TeamSetting, error)
 	GetTotalActiveUsers(ctx context.Context) (int64, error)
 	GetUser(ctx context.Context, userID string) (User, error)
-	MergePR(ctx context.Context, pullRequestID string) (PullRequest, error)
-	PRExists(ctx context.Context, pullRequestID string) (bool, error)
+	GetUserAbsences(ctx context.Context, userID string) ([]UserAbsence, error)
+	GetUsersByIDs(ctx context.Context, userIds []string) ([]User, error)
+	GetUsersByTeams(ctx context.Context, teamNames []string) ([]User, error)
+	GetUsersWithHistory(ctx context.Context, userIds []string) ([]string, error)
+	ListPRs(ctx context.Context, arg ListPRsParams) ([]PullRequest, error)
+	ListRepositories(ctx context.Context, teamName string) ([]Repository, error)
+	LockOpenPRs(ctx context.Context, arg LockOpenPRsParams) ([]LockOpenPRsRow, error)
+	MergePR(ctx context.Context, arg MergePRParams) (PullRequest, error)
+	PRExists(ctx context.Context, arg PRExistsParams) (bool, error)
+	ReleaseStartedAbsences(ctx context.Context) ([]string, error)
 	ReplaceReviewer(ctx context.Context, arg ReplaceReviewerParams) (PrReviewerAssignment, error)
+	ReplaceReviewers(ctx context.Context, arg ReplaceReviewersParams) error
+	SetMaxOpenReviews(ctx context.Context, arg SetMaxOpenReviewsParams) (ReviewerSetting, error)
+	SetPRMissingSenior(ctx context.Context, arg SetPRMissingSeniorParams) error
+	SetPRsMissingSenior(ctx context.Context, arg SetPRsMissingSeniorParams) error
+	SetRepositoryTeam(ctx context.Context, arg SetRepositoryTeamParams) (Repository, error)
+	SetTeamPairRule(ctx context.Context, arg SetTeamPairRuleParams) (TeamPairRule, error)
+	SetTeamRequireSenior(ctx context.Context, arg SetTeamRequireSeniorParams) (TeamSetting, error)
 	SetUserActivity(ctx context.Context, arg SetUserActivityParams) (User, error)
+	SetUserLevel(ctx context.Context, arg SetUserLevelParams) (ReviewerSetting, error)
+	SetUsersActivity(ctx context.Context, arg SetUsersActivityParams) ([]User, error)
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error)
 	TeamExists(ctx context.Context, teamName string) (bool, error)
+	UpsertCodeOwners(ctx context.Context, arg UpsertCodeOwnersParams) (Codeowner, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -28,28 +28,28 @@ WHERE user_id = $1
 RETURNING *;
 
 -- name: CreatePR :one
-INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id)
-VALUES ($1, $2, $3)
-ON CONFLICT (pull_request_id) DO NOTHING
+INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, repository)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (repository_key, pull_request_id) DO NOTHING
 RETURNING *;
 
 -- name: AssignReviewer :one
-INSERT INTO pr_reviewer_assignment (pr_id, reviewer_id)
-VALUES ($1, $2)
+INSERT INTO pr_reviewer_assignment (pr_repository, pr_id, reviewer_id)
+VALUES ($1, $2, $3)
 RETURNING reviewer_id;
 
 -- name: PRExists :one
 SELECT EXISTS (
-  SELECT 1 FROM pull_requests WHERE pull_request_id = $1
+  SELECT 1 FROM pull_requests WHERE repository_key = $1 AND pull_request_id = $2
 );
 
 -- name: GetPR :one
 SELECT * FROM pull_requests
-WHERE pull_request_id = $1;
+WHERE repository_key = $1 AND pull_request_id = $2;
 
 -- name: GetPRReviewers :many
 SELECT reviewer_id FROM pr_reviewer_assignment
-WHERE pr_id = $1 AND replaced_by IS NULL;
+WHERE pr_repository = $1 AND pr_id = $2 AND replaced_by IS NULL;
 
 -- name: GetActiveTeamMembersExcept :many
 SELECT * FROM users
@@ -58,37 +58,40 @@ WHERE team_name = $1 AND is_active = true AND user_id != $2;
 -- name: MergePR :one
 UPDATE pull_requests
 SET status = 'MERGED', merged_at = COALESCE(merged_at, now())
-WHERE pull_request_id = $1
+WHERE repository_key = $1 AND pull_request_id = $2
 RETURNING *;
 
 -- name: CheckReviewerAssignment :one
 SELECT EXISTS (
   SELECT 1 FROM pr_reviewer_assignment 
-  WHERE pr_id = $1 AND reviewer_id = $2 AND replaced_by IS NULL
+  WHERE pr_repository = $1 AND pr_id = $2 AND reviewer_id = $3 AND replaced_by IS NULL
 );
 
 -- name: ReplaceReviewer :one
 UPDATE pr_reviewer_assignment
-SET replaced_by = $3
-WHERE pr_id = $1 AND reviewer_id = $2 AND replaced_by IS NULL
+SET replaced_by = $4
+WHERE pr_repository = $1 AND pr_id = $2 AND reviewer_id = $3 AND replaced_by IS NULL
 RETURNING *;
 
 -- name: GetPRsByReviewer :many
 SELECT DISTINCT pr.* FROM pull_requests pr
-JOIN pr_reviewer_assignment pra ON pr.pull_request_id = pra.pr_id
-WHERE pra.reviewer_id = $1 AND pra.replaced_by IS NULL;
+JOIN pr_reviewer_assignment pra ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id
+WHERE pra.reviewer_id = @reviewer_id AND pra.replaced_by IS NULL
+  AND (@repository::text = '' OR pr.repository = @repository);
 
 -- name: GetReviewerStats :many
-SELECT reviewer_id, COUNT(*) as assignment_count
-FROM pr_reviewer_assignment
-WHERE replaced_by IS NULL
-GROUP BY reviewer_id
+SELECT pra.reviewer_id, COUNT(*) as assignment_count
+FROM pr_reviewer_assignment pra
+JOIN pull_requests pr ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id
+WHERE pra.replaced_by IS NULL AND (@repository::text = '' OR pr.repository = @repository)
+GROUP BY pra.reviewer_id
 ORDER BY assignment_count DESC
 LIMIT 10;
 
 -- name: GetPRStatusStats :many
 SELECT status, COUNT(*) as count
 FROM pull_requests
+WHERE @repository::text = '' OR repository = @repository
 GROUP BY status;
 
 -- name: GetTotalActiveUsers :one
@@ -96,4 +99,243 @@ SELECT COUNT(*) FROM users WHERE is_active = true;
 
 -- name: DeleteReviewer :exec
 DELETE FROM pr_reviewer_assignment
-WHERE pr_id = $1 AND reviewer_id = $2 AND replaced_by IS NULL;
+WHERE pr_repository = $1 AND pr_id = $2 AND reviewer_id = $3 AND replaced_by IS NULL;
+
+-- name: TakeRateLimitToken :one
+INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, allowed, updated_at)
+VALUES (@bucket_key, @burst::float8 - 1, true, now())
+ON CONFLICT (bucket_key) DO UPDATE
+SET
+    allowed = LEAST(@burst::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * @rate::float8) >= 1,
+    tokens = LEAST(@burst::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * @rate::float8)
+        - CASE WHEN LEAST(@burst::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * @rate::float8) >= 1 THEN 1 ELSE 0 END,
+    updated_at = now()
+RETURNING tokens, allowed;
+
+-- name: DeleteStaleRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets
+WHERE updated_at < $1;
+
+-- name: GetUsersByIDs :many
+SELECT * FROM users
+WHERE user_id = ANY(@user_ids::text[]);
+
+-- name: GetUsersByTeams :many
+SELECT * FROM users
+WHERE team_name = ANY(@team_names::text[])
+ORDER BY team_name, user_id;
+
+-- name: GetPRsByIDs :many
+SELECT pr.* FROM pull_requests pr
+JOIN unnest(@pr_repositories::text[], @pr_ids::text[]) AS k(repository_key, pull_request_id)
+  ON pr.repository_key = k.repository_key AND pr.pull_request_id = k.pull_request_id;
+
+-- name: GetPRsByReviewers :many
+SELECT DISTINCT pra.reviewer_id, pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.merged_at, pr.repository, pr.repository_key, pr.missing_senior
+FROM pr_reviewer_assignment pra
+JOIN pull_requests pr ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id
+WHERE pra.reviewer_id = ANY(@reviewer_ids::text[]) AND pra.replaced_by IS NULL
+ORDER BY pra.reviewer_id, pr.pull_request_id, pr.repository_key;
+
+-- name: GetReviewersByPRs :many
+SELECT pra.pr_repository, pra.pr_id, pra.reviewer_id FROM pr_reviewer_assignment pra
+JOIN unnest(@pr_repositories::text[], @pr_ids::text[]) AS k(pr_repository, pr_id)
+  ON pra.pr_repository = k.pr_repository AND pra.pr_id = k.pr_id
+WHERE pra.replaced_by IS NULL
+ORDER BY pra.pr_repository, pra.pr_id, pra.assigned_at;
+
+-- name: DeactivateUsers :exec
+UPDATE users
+SET is_active = false
+WHERE user_id = ANY(@user_ids::text[]);
+
+-- name: ReplaceReviewers :exec
+UPDATE pr_reviewer_assignment AS pra
+SET replaced_by = r.replaced_by
+FROM unnest(@pr_repositories::text[], @pr_ids::text[], @reviewer_ids::text[], @replaced_by::text[]) AS r(pr_repository, pr_id, reviewer_id, replaced_by)
+WHERE pra.pr_repository = r.pr_repository AND pra.pr_id = r.pr_id AND pra.reviewer_id = r.reviewer_id AND pra.replaced_by IS NULL;
+
+-- name: AssignReviewers :exec
+INSERT INTO pr_reviewer_assignment (pr_repository, pr_id, reviewer_id)
+SELECT r.pr_repository, r.pr_id, r.reviewer_id FROM unnest(@pr_repositories::text[], @pr_ids::text[], @reviewer_ids::text[]) AS r(pr_repository, pr_id, reviewer_id);
+
+-- name: DeleteReviewers :exec
+DELETE FROM pr_reviewer_assignment AS pra
+USING unnest(@pr_repositories::text[], @pr_ids::text[], @reviewer_ids::text[]) AS r(pr_repository, pr_id, reviewer_id)
+WHERE pra.pr_repository = r.pr_repository AND pra.pr_id = r.pr_id AND pra.reviewer_id = r.reviewer_id AND pra.replaced_by IS NULL;
+
+-- name: LockOpenPRs :many
+SELECT pr.repository_key, pr.pull_request_id FROM pull_requests pr
+JOIN unnest(@pr_repositories::text[], @pr_ids::text[]) AS k(repository_key, pull_request_id)
+  ON pr.repository_key = k.repository_key AND pr.pull_request_id = k.pull_request_id
+WHERE pr.status = 'OPEN'
+ORDER BY pr.repository_key, pr.pull_request_id
+FOR UPDATE OF pr;
+
+-- name: SetUsersActivity :many
+UPDATE users AS u
+SET is_active = v.is_active
+FROM unnest(@user_ids::text[], @is_active::bool[]) AS v(user_id, is_active)
+WHERE u.user_id = v.user_id
+RETURNING u.*;
+
+-- name: GetUsersWithHistory :many
+SELECT author_id AS user_id FROM pull_requests WHERE author_id = ANY(@user_ids::text[])
+UNION
+SELECT reviewer_id FROM pr_reviewer_assignment WHERE reviewer_id = ANY(@user_ids::text[])
+UNION
+SELECT replaced_by FROM pr_reviewer_assignment WHERE replaced_by = ANY(@user_ids::text[]);
+
+-- name: DeleteUsers :exec
+DELETE FROM users
+WHERE user_id = ANY(@user_ids::text[]);
+
+-- name: CreateAbsence :one
+INSERT INTO user_absences (user_id, starts_at, ends_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetUserAbsences :many
+SELECT * FROM user_absences
+WHERE user_id = $1
+ORDER BY starts_at, ends_at;
+
+-- name: DeleteAbsence :one
+DELETE FROM user_absences
+WHERE absence_id = $1
+RETURNING *;
+
+-- name: GetAbsentUsers :many
+SELECT DISTINCT user_id FROM user_absences
+WHERE user_id = ANY(@user_ids::text[]) AND starts_at <= now() AND ends_at > now()
+ORDER BY user_id;
+
+-- name: ReleaseStartedAbsences :many
+UPDATE user_absences
+SET reviews_released = true
+WHERE NOT reviews_released AND starts_at <= now() AND ends_at > now()
+RETURNING user_id;
+
+-- name: SetMaxOpenReviews :one
+INSERT INTO reviewer_settings (user_id, max_open_reviews)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET max_open_reviews = EXCLUDED.max_open_reviews
+RETURNING *;
+
+-- name: GetReviewLoad :many
+SELECT u.user_id, s.max_open_reviews, COALESCE(s.level, 'regular')::text AS level, COUNT(pr.pull_request_id) AS open_reviews
+FROM users u
+LEFT JOIN reviewer_settings s ON s.user_id = u.user_id
+LEFT JOIN pr_reviewer_assignment pra ON pra.reviewer_id = u.user_id AND pra.replaced_by IS NULL
+LEFT JOIN pull_requests pr ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id AND pr.status = 'OPEN'
+WHERE u.user_id = ANY(@user_ids::text[])
+GROUP BY u.user_id, s.max_open_reviews, s.level
+ORDER BY u.user_id;
+
+-- name: GetTeamFallbacks :many
+SELECT fallback_team FROM team_fallbacks
+WHERE team_name = $1
+ORDER BY position;
+
+-- name: DeleteTeamFallbacks :exec
+DELETE FROM team_fallbacks
+WHERE team_name = $1;
+
+-- name: AddTeamFallbacks :exec
+INSERT INTO team_fallbacks (team_name, position, fallback_team)
+SELECT @team_name::text, f.position, f.fallback_team
+FROM unnest(@fallback_teams::text[]) WITH ORDINALITY AS f(fallback_team, position);
+
+-- name: UpsertCodeOwners :one
+INSERT INTO codeowners (repository, content)
+VALUES ($1, $2)
+ON CONFLICT (repository) DO UPDATE
+SET content = EXCLUDED.content, uploaded_at = now()
+RETURNING *;
+
+-- name: GetCodeOwners :one
+SELECT * FROM codeowners
+WHERE repository = $1;
+
+-- name: SetRepositoryTeam :one
+INSERT INTO repositories (repository_name, team_name)
+VALUES ($1, $2)
+ON CONFLICT (repository_name) DO UPDATE
+SET team_name = EXCLUDED.team_name
+RETURNING *;
+
+-- name: GetRepository :one
+SELECT * FROM repositories
+WHERE repository_name = $1;
+
+-- name: ListRepositories :many
+SELECT * FROM repositories
+WHERE @team_name::text = '' OR team_name = @team_name
+ORDER BY repository_name;
+
+-- name: ListPRs :many
+SELECT * FROM pull_requests
+WHERE (@repository::text = '' OR repository = @repository)
+  AND (@status::text = '' OR status::text = @status)
+ORDER BY pull_request_id, repository_key;
+
+-- name: SetUserLevel :one
+INSERT INTO reviewer_settings (user_id, level)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET level = EXCLUDED.level
+RETURNING *;
+
+-- name: GetTeamSettings :one
+SELECT * FROM team_settings
+WHERE team_name = $1;
+
+-- name: SetTeamRequireSenior :one
+INSERT INTO team_settings (team_name, require_senior)
+VALUES ($1, $2)
+ON CONFLICT (team_name) DO UPDATE
+SET require_senior = EXCLUDED.require_senior
+RETURNING *;
+
+-- name: GetPRsRequiringSenior :many
+SELECT pr.repository_key, pr.pull_request_id FROM pull_requests pr
+JOIN unnest(@pr_repositories::text[], @pr_ids::text[]) AS k(repository_key, pull_request_id)
+  ON pr.repository_key = k.repository_key AND pr.pull_request_id = k.pull_request_id
+JOIN users u ON u.user_id = pr.author_id
+JOIN team_settings t ON t.team_name = u.team_name
+WHERE t.require_senior
+ORDER BY pr.repository_key, pr.pull_request_id;
+
+-- name: SetPRMissingSenior :exec
+UPDATE pull_requests SET missing_senior = $3
+WHERE repository_key = $1 AND pull_request_id = $2;
+
+-- name: SetPRsMissingSenior :exec
+UPDATE pull_requests AS pr
+SET missing_senior = v.missing_senior
+FROM unnest(@pr_repositories::text[], @pr_ids::text[], @missing_senior::bool[]) AS v(repository_key, pull_request_id, missing_senior)
+WHERE pr.repository_key = v.repository_key AND pr.pull_request_id = v.pull_request_id;
+
+-- name: GetTeamPairRules :many
+SELECT * FROM team_pair_rules
+WHERE team_name = $1
+ORDER BY author_id, reviewer_id;
+
+-- name: SetTeamPairRule :one
+INSERT INTO team_pair_rules (team_name, reviewer_id, author_id, kind)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (team_name, reviewer_id, author_id) DO UPDATE
+SET kind = EXCLUDED.kind
+RETURNING *;
+
+-- name: DeleteTeamPairRule :one
+DELETE FROM team_pair_rules
+WHERE team_name = $1 AND reviewer_id = $2 AND author_id = $3
+RETURNING *;
+
+-- name: GetPairRulesByAuthors :many
+SELECT r.team_name, r.reviewer_id, r.author_id, r.kind FROM team_pair_rules r
+JOIN users u ON u.user_id = r.author_id AND u.team_name = r.team_name
+WHERE r.author_id = ANY(@author_ids::text[])
+ORDER BY r.author_id, r.reviewer_id;
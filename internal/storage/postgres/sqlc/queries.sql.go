@@ -11,58 +11,123 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addTeamFallbacks = `-- name: AddTeamFallbacks :exec
+INSERT INTO team_fallbacks (team_name, position, fallback_team)
+SELECT $1::text, f.position, f.fallback_team
+FROM unnest($2::text[]) WITH ORDINALITY AS f(fallback_team, position)
+`
+
+type AddTeamFallbacksParams struct {
+	TeamName      string   `json:"team_name"`
+	FallbackTeams []string `json:"fallback_teams"`
+}
+
+func (q *Queries) AddTeamFallbacks(ctx context.Context, arg AddTeamFallbacksParams) error {
+	_, err := q.db.Exec(ctx, addTeamFallbacks, arg.TeamName, arg.FallbackTeams)
+	return err
+}
+
 const assignReviewer = `-- name: AssignReviewer :one
-INSERT INTO pr_reviewer_assignment (pr_id, reviewer_id)
-VALUES ($1, $2)
+INSERT INTO pr_reviewer_assignment (pr_repository, pr_id, reviewer_id)
+VALUES ($1, $2, $3)
 RETURNING reviewer_id
 `
 
 type AssignReviewerParams struct {
-	PrID       string `json:"pr_id"`
-	ReviewerID string `json:"reviewer_id"`
+	PrRepository string `json:"pr_repository"`
+	PrID         string `json:"pr_id"`
+	ReviewerID   string `json:"reviewer_id"`
 }
 
 func (q *Queries) AssignReviewer(ctx context.Context, arg AssignReviewerParams) (string, error) {
-	row := q.db.QueryRow(ctx, assignReviewer, arg.PrID, arg.ReviewerID)
+	row := q.db.QueryRow(ctx, assignReviewer, arg.PrRepository, arg.PrID, arg.ReviewerID)
 	var reviewer_id string
 	err := row.Scan(&reviewer_id)
 	return reviewer_id, err
 }
 
+const assignReviewers = `-- name: AssignReviewers :exec
+INSERT INTO pr_reviewer_assignment (pr_repository, pr_id, reviewer_id)
+SELECT r.pr_repository, r.pr_id, r.reviewer_id FROM unnest($1::text[], $2::text[], $3::text[]) AS r(pr_repository, pr_id, reviewer_id)
+`
+
+type AssignReviewersParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
+	ReviewerIds    []string `json:"reviewer_ids"`
+}
+
+func (q *Queries) AssignReviewers(ctx context.Context, arg AssignReviewersParams) error {
+	_, err := q.db.Exec(ctx, assignReviewers, arg.PrRepositories, arg.PrIds, arg.ReviewerIds)
+	return err
+}
+
 const checkReviewerAssignment = `-- name: CheckReviewerAssignment :one
 SELECT EXISTS (
   SELECT 1 FROM pr_reviewer_assignment 
-  WHERE pr_id = $1 AND reviewer_id = $2 AND replaced_by IS NULL
+  WHERE pr_repository = $1 AND pr_id = $2 AND reviewer_id = $3 AND replaced_by IS NULL
 )
 `
 
 type CheckReviewerAssignmentParams struct {
-	PrID       string `json:"pr_id"`
-	ReviewerID string `json:"reviewer_id"`
+	PrRepository string `json:"pr_repository"`
+	PrID         string `json:"pr_id"`
+	ReviewerID   string `json:"reviewer_id"`
 }
 
 func (q *Queries) CheckReviewerAssignment(ctx context.Context, arg CheckReviewerAssignmentParams) (bool, error) {
-	row := q.db.QueryRow(ctx, checkReviewerAssignment, arg.PrID, arg.ReviewerID)
+	row := q.db.QueryRow(ctx, checkReviewerAssignment, arg.PrRepository, arg.PrID, arg.ReviewerID)
 	var exists bool
 	err := row.Scan(&exists)
 	return exists, err
 }
 
-const createPR = `-- name: CreatePR :one
-INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id)
+const createAbsence = `-- name: CreateAbsence :one
+INSERT INTO user_absences (user_id, starts_at, ends_at)
 VALUES ($1, $2, $3)
-ON CONFLICT (pull_request_id) DO NOTHING
-RETURNING pull_request_id, pull_request_name, author_id, status, merged_at
+RETURNING absence_id, user_id, starts_at, ends_at, reviews_released
+`
+
+type CreateAbsenceParams struct {
+	UserID   string             `json:"user_id"`
+	StartsAt pgtype.Timestamptz `json:"starts_at"`
+	EndsAt   pgtype.Timestamptz `json:"ends_at"`
+}
+
+func (q *Queries) CreateAbsence(ctx context.Context, arg CreateAbsenceParams) (UserAbsence, error) {
+	row := q.db.QueryRow(ctx, createAbsence, arg.UserID, arg.StartsAt, arg.EndsAt)
+	var i UserAbsence
+	err := row.Scan(
+		&i.AbsenceID,
+		&i.UserID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.ReviewsReleased,
+	)
+	return i, err
+}
+
+const createPR = `-- name: CreatePR :one
+INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, repository)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (repository_key, pull_request_id) DO NOTHING
+RETURNING pull_request_id, pull_request_name, author_id, status, merged_at, repository, repository_key, missing_senior
 `
 
 type CreatePRParams struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
+	PullRequestID   string      `json:"pull_request_id"`
+	PullRequestName string      `json:"pull_request_name"`
+	AuthorID        string      `json:"author_id"`
+	Repository      pgtype.Text `json:"repository"`
 }
 
 func (q *Queries) CreatePR(ctx context.Context, arg CreatePRParams) (PullRequest, error) {
-	row := q.db.QueryRow(ctx, createPR, arg.PullRequestID, arg.PullRequestName, arg.AuthorID)
+	row := q.db.QueryRow(ctx, createPR,
+		arg.PullRequestID,
+		arg.PullRequestName,
+		arg.AuthorID,
+		arg.Repository,
+	)
 	var i PullRequest
 	err := row.Scan(
 		&i.PullRequestID,
@@ -70,6 +135,9 @@ func (q *Queries) CreatePR(ctx context.Context, arg CreatePRParams) (PullRequest
 		&i.AuthorID,
 		&i.Status,
 		&i.MergedAt,
+		&i.Repository,
+		&i.RepositoryKey,
+		&i.MissingSenior,
 	)
 	return i, err
 }
@@ -109,21 +177,152 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 	return i, err
 }
 
+const deactivateUsers = `-- name: DeactivateUsers :exec
+UPDATE users
+SET is_active = false
+WHERE user_id = ANY($1::text[])
+`
+
+func (q *Queries) DeactivateUsers(ctx context.Context, userIds []string) error {
+	_, err := q.db.Exec(ctx, deactivateUsers, userIds)
+	return err
+}
+
+const deleteAbsence = `-- name: DeleteAbsence :one
+DELETE FROM user_absences
+WHERE absence_id = $1
+RETURNING absence_id, user_id, starts_at, ends_at, reviews_released
+`
+
+func (q *Queries) DeleteAbsence(ctx context.Context, absenceID string) (UserAbsence, error) {
+	row := q.db.QueryRow(ctx, deleteAbsence, absenceID)
+	var i UserAbsence
+	err := row.Scan(
+		&i.AbsenceID,
+		&i.UserID,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.ReviewsReleased,
+	)
+	return i, err
+}
+
 const deleteReviewer = `-- name: DeleteReviewer :exec
 DELETE FROM pr_reviewer_assignment
-WHERE pr_id = $1 AND reviewer_id = $2 AND replaced_by IS NULL
+WHERE pr_repository = $1 AND pr_id = $2 AND reviewer_id = $3 AND replaced_by IS NULL
 `
 
 type DeleteReviewerParams struct {
-	PrID       string `json:"pr_id"`
-	ReviewerID string `json:"reviewer_id"`
+	PrRepository string `json:"pr_repository"`
+	PrID         string `json:"pr_id"`
+	ReviewerID   string `json:"reviewer_id"`
 }
 
 func (q *Queries) DeleteReviewer(ctx context.Context, arg DeleteReviewerParams) error {
-	_, err := q.db.Exec(ctx, deleteReviewer, arg.PrID, arg.ReviewerID)
+	_, err := q.db.Exec(ctx, deleteReviewer, arg.PrRepository, arg.PrID, arg.ReviewerID)
+	return err
+}
+
+const deleteReviewers = `-- name: DeleteReviewers :exec
+DELETE FROM pr_reviewer_assignment AS pra
+USING unnest($1::text[], $2::text[], $3::text[]) AS r(pr_repository, pr_id, reviewer_id)
+WHERE pra.pr_repository = r.pr_repository AND pra.pr_id = r.pr_id AND pra.reviewer_id = r.reviewer_id AND pra.replaced_by IS NULL
+`
+
+type DeleteReviewersParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
+	ReviewerIds    []string `json:"reviewer_ids"`
+}
+
+func (q *Queries) DeleteReviewers(ctx context.Context, arg DeleteReviewersParams) error {
+	_, err := q.db.Exec(ctx, deleteReviewers, arg.PrRepositories, arg.PrIds, arg.ReviewerIds)
+	return err
+}
+
+const deleteStaleRateLimitBuckets = `-- name: DeleteStaleRateLimitBuckets :execrows
+DELETE FROM rate_limit_buckets
+WHERE updated_at < $1
+`
+
+func (q *Queries) DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteStaleRateLimitBuckets, updatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteTeamFallbacks = `-- name: DeleteTeamFallbacks :exec
+DELETE FROM team_fallbacks
+WHERE team_name = $1
+`
+
+func (q *Queries) DeleteTeamFallbacks(ctx context.Context, teamName string) error {
+	_, err := q.db.Exec(ctx, deleteTeamFallbacks, teamName)
+	return err
+}
+
+const deleteTeamPairRule = `-- name: DeleteTeamPairRule :one
+DELETE FROM team_pair_rules
+WHERE team_name = $1 AND reviewer_id = $2 AND author_id = $3
+RETURNING team_name, reviewer_id, author_id, kind
+`
+
+type DeleteTeamPairRuleParams struct {
+	TeamName   string `json:"team_name"`
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+}
+
+func (q *Queries) DeleteTeamPairRule(ctx context.Context, arg DeleteTeamPairRuleParams) (TeamPairRule, error) {
+	row := q.db.QueryRow(ctx, deleteTeamPairRule, arg.TeamName, arg.ReviewerID, arg.AuthorID)
+	var i TeamPairRule
+	err := row.Scan(
+		&i.TeamName,
+		&i.ReviewerID,
+		&i.AuthorID,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const deleteUsers = `-- name: DeleteUsers :exec
+DELETE FROM users
+WHERE user_id = ANY($1::text[])
+`
+
+func (q *Queries) DeleteUsers(ctx context.Context, userIds []string) error {
+	_, err := q.db.Exec(ctx, deleteUsers, userIds)
 	return err
 }
 
+const getAbsentUsers = `-- name: GetAbsentUsers :many
+SELECT DISTINCT user_id FROM user_absences
+WHERE user_id = ANY($1::text[]) AND starts_at <= now() AND ends_at > now()
+ORDER BY user_id
+`
+
+func (q *Queries) GetAbsentUsers(ctx context.Context, userIds []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getAbsentUsers, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var user_id string
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getActiveTeamMembersExcept = `-- name: GetActiveTeamMembersExcept :many
 SELECT user_id, username, is_active, team_name FROM users
 WHERE team_name = $1 AND is_active = true AND user_id != $2
@@ -159,13 +358,34 @@ func (q *Queries) GetActiveTeamMembersExcept(ctx context.Context, arg GetActiveT
 	return items, nil
 }
 
+const getCodeOwners = `-- name: GetCodeOwners :one
+SELECT repository, content, uploaded_at FROM codeowners
+WHERE repository = $1
+`
+
+func (q *Queries) GetCodeOwners(ctx context.Context, repository string) (Codeowner, error) {
+	row := q.db.QueryRow(ctx, getCodeOwners, repository)
+	var i Codeowner
+	err := row.Scan(
+		&i.Repository,
+		&i.Content,
+		&i.UploadedAt,
+	)
+	return i, err
+}
+
 const getPR = `-- name: GetPR :one
-SELECT pull_request_id, pull_request_name, author_id, status, merged_at FROM pull_requests
-WHERE pull_request_id = $1
+SELECT pull_request_id, pull_request_name, author_id, status, merged_at, repository, repository_key, missing_senior FROM pull_requests
+WHERE repository_key = $1 AND pull_request_id = $2
 `
 
-func (q *Queries) GetPR(ctx context.Context, pullRequestID string) (PullRequest, error) {
-	row := q.db.QueryRow(ctx, getPR, pullRequestID)
+type GetPRParams struct {
+	RepositoryKey string `json:"repository_key"`
+	PullRequestID string `json:"pull_request_id"`
+}
+
+func (q *Queries) GetPR(ctx context.Context, arg GetPRParams) (PullRequest, error) {
+	row := q.db.QueryRow(ctx, getPR, arg.RepositoryKey, arg.PullRequestID)
 	var i PullRequest
 	err := row.Scan(
 		&i.PullRequestID,
@@ -173,17 +393,25 @@ func (q *Queries) GetPR(ctx context.Context, pullRequestID string) (PullRequest,
 		&i.AuthorID,
 		&i.Status,
 		&i.MergedAt,
+		&i.Repository,
+		&i.RepositoryKey,
+		&i.MissingSenior,
 	)
 	return i, err
 }
 
 const getPRReviewers = `-- name: GetPRReviewers :many
 SELECT reviewer_id FROM pr_reviewer_assignment
-WHERE pr_id = $1 AND replaced_by IS NULL
+WHERE pr_repository = $1 AND pr_id = $2 AND replaced_by IS NULL
 `
 
-func (q *Queries) GetPRReviewers(ctx context.Context, prID string) ([]string, error) {
-	rows, err := q.db.Query(ctx, getPRReviewers, prID)
+type GetPRReviewersParams struct {
+	PrRepository string `json:"pr_repository"`
+	PrID         string `json:"pr_id"`
+}
+
+func (q *Queries) GetPRReviewers(ctx context.Context, arg GetPRReviewersParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, getPRReviewers, arg.PrRepository, arg.PrID)
 	if err != nil {
 		return nil, err
 	}
@@ -205,6 +433,7 @@ func (q *Queries) GetPRReviewers(ctx context.Context, prID string) ([]string, er
 const getPRStatusStats = `-- name: GetPRStatusStats :many
 SELECT status, COUNT(*) as count
 FROM pull_requests
+WHERE $1::text = '' OR repository = $1
 GROUP BY status
 `
 
@@ -213,8 +442,8 @@ type GetPRStatusStatsRow struct {
 	Count  int64            `json:"count"`
 }
 
-func (q *Queries) GetPRStatusStats(ctx context.Context) ([]GetPRStatusStatsRow, error) {
-	rows, err := q.db.Query(ctx, getPRStatusStats)
+func (q *Queries) GetPRStatusStats(ctx context.Context, repository string) ([]GetPRStatusStatsRow, error) {
+	rows, err := q.db.Query(ctx, getPRStatusStats, repository)
 	if err != nil {
 		return nil, err
 	}
@@ -233,14 +462,19 @@ func (q *Queries) GetPRStatusStats(ctx context.Context) ([]GetPRStatusStatsRow,
 	return items, nil
 }
 
-const getPRsByReviewer = `-- name: GetPRsByReviewer :many
-SELECT DISTINCT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.merged_at FROM pull_requests pr
-JOIN pr_reviewer_assignment pra ON pr.pull_request_id = pra.pr_id
-WHERE pra.reviewer_id = $1 AND pra.replaced_by IS NULL
+const getPRsByIDs = `-- name: GetPRsByIDs :many
+SELECT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.merged_at, pr.repository, pr.repository_key, pr.missing_senior FROM pull_requests pr
+JOIN unnest($1::text[], $2::text[]) AS k(repository_key, pull_request_id)
+  ON pr.repository_key = k.repository_key AND pr.pull_request_id = k.pull_request_id
 `
 
-func (q *Queries) GetPRsByReviewer(ctx context.Context, reviewerID string) ([]PullRequest, error) {
-	rows, err := q.db.Query(ctx, getPRsByReviewer, reviewerID)
+type GetPRsByIDsParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
+}
+
+func (q *Queries) GetPRsByIDs(ctx context.Context, arg GetPRsByIDsParams) ([]PullRequest, error) {
+	rows, err := q.db.Query(ctx, getPRsByIDs, arg.PrRepositories, arg.PrIds)
 	if err != nil {
 		return nil, err
 	}
@@ -254,6 +488,9 @@ func (q *Queries) GetPRsByReviewer(ctx context.Context, reviewerID string) ([]Pu
 			&i.AuthorID,
 			&i.Status,
 			&i.MergedAt,
+			&i.Repository,
+			&i.RepositoryKey,
+			&i.MissingSenior,
 		); err != nil {
 			return nil, err
 		}
@@ -265,30 +502,37 @@ func (q *Queries) GetPRsByReviewer(ctx context.Context, reviewerID string) ([]Pu
 	return items, nil
 }
 
-const getReviewerStats = `-- name: GetReviewerStats :many
-SELECT reviewer_id, COUNT(*) as assignment_count
-FROM pr_reviewer_assignment
-WHERE replaced_by IS NULL
-GROUP BY reviewer_id
-ORDER BY assignment_count DESC
-LIMIT 10
+const getPRsByReviewer = `-- name: GetPRsByReviewer :many
+SELECT DISTINCT pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.merged_at, pr.repository, pr.repository_key, pr.missing_senior FROM pull_requests pr
+JOIN pr_reviewer_assignment pra ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id
+WHERE pra.reviewer_id = $1 AND pra.replaced_by IS NULL
+  AND ($2::text = '' OR pr.repository = $2)
 `
 
-type GetReviewerStatsRow struct {
-	ReviewerID      string `json:"reviewer_id"`
-	AssignmentCount int64  `json:"assignment_count"`
+type GetPRsByReviewerParams struct {
+	ReviewerID string `json:"reviewer_id"`
+	Repository string `json:"repository"`
 }
 
-func (q *Queries) GetReviewerStats(ctx context.Context) ([]GetReviewerStatsRow, error) {
-	rows, err := q.db.Query(ctx, getReviewerStats)
+func (q *Queries) GetPRsByReviewer(ctx context.Context, arg GetPRsByReviewerParams) ([]PullRequest, error) {
+	rows, err := q.db.Query(ctx, getPRsByReviewer, arg.ReviewerID, arg.Repository)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetReviewerStatsRow
+	var items []PullRequest
 	for rows.Next() {
-		var i GetReviewerStatsRow
-		if err := rows.Scan(&i.ReviewerID, &i.AssignmentCount); err != nil {
+		var i PullRequest
+		if err := rows.Scan(
+			&i.PullRequestID,
+			&i.PullRequestName,
+			&i.AuthorID,
+			&i.Status,
+			&i.MergedAt,
+			&i.Repository,
+			&i.RepositoryKey,
+			&i.MissingSenior,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -299,25 +543,45 @@ func (q *Queries) GetReviewerStats(ctx context.Context) ([]GetReviewerStatsRow,
 	return items, nil
 }
 
-const getTeam = `-- name: GetTeam :many
-SELECT user_id, username, is_active, team_name FROM users
-WHERE team_name = $1
+const getPRsByReviewers = `-- name: GetPRsByReviewers :many
+SELECT DISTINCT pra.reviewer_id, pr.pull_request_id, pr.pull_request_name, pr.author_id, pr.status, pr.merged_at, pr.repository, pr.repository_key, pr.missing_senior
+FROM pr_reviewer_assignment pra
+JOIN pull_requests pr ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id
+WHERE pra.reviewer_id = ANY($1::text[]) AND pra.replaced_by IS NULL
+ORDER BY pra.reviewer_id, pr.pull_request_id, pr.repository_key
 `
 
-func (q *Queries) GetTeam(ctx context.Context, teamName string) ([]User, error) {
-	rows, err := q.db.Query(ctx, getTeam, teamName)
+type GetPRsByReviewersRow struct {
+	ReviewerID      string             `json:"reviewer_id"`
+	PullRequestID   string             `json:"pull_request_id"`
+	PullRequestName string             `json:"pull_request_name"`
+	AuthorID        string             `json:"author_id"`
+	Status          NullPrStatusEnum   `json:"status"`
+	MergedAt        pgtype.Timestamptz `json:"merged_at"`
+	Repository      pgtype.Text        `json:"repository"`
+	RepositoryKey   string             `json:"repository_key"`
+	MissingSenior   bool               `json:"missing_senior"`
+}
+
+func (q *Queries) GetPRsByReviewers(ctx context.Context, reviewerIds []string) ([]GetPRsByReviewersRow, error) {
+	rows, err := q.db.Query(ctx, getPRsByReviewers, reviewerIds)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []GetPRsByReviewersRow
 	for rows.Next() {
-		var i User
+		var i GetPRsByReviewersRow
 		if err := rows.Scan(
-			&i.UserID,
-			&i.Username,
-			&i.IsActive,
-			&i.TeamName,
+			&i.ReviewerID,
+			&i.PullRequestID,
+			&i.PullRequestName,
+			&i.AuthorID,
+			&i.Status,
+			&i.MergedAt,
+			&i.Repository,
+			&i.RepositoryKey,
+			&i.MissingSenior,
 		); err != nil {
 			return nil, err
 		}
@@ -329,117 +593,929 @@ func (q *Queries) GetTeam(ctx context.Context, teamName string) ([]User, error)
 	return items, nil
 }
 
-const getTotalActiveUsers = `-- name: GetTotalActiveUsers :one
-SELECT COUNT(*) FROM users WHERE is_active = true
+const getPRsRequiringSenior = `-- name: GetPRsRequiringSenior :many
+SELECT pr.repository_key, pr.pull_request_id FROM pull_requests pr
+JOIN unnest($1::text[], $2::text[]) AS k(repository_key, pull_request_id)
+  ON pr.repository_key = k.repository_key AND pr.pull_request_id = k.pull_request_id
+JOIN users u ON u.user_id = pr.author_id
+JOIN team_settings t ON t.team_name = u.team_name
+WHERE t.require_senior
+ORDER BY pr.repository_key, pr.pull_request_id
 `
 
-func (q *Queries) GetTotalActiveUsers(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, getTotalActiveUsers)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type GetPRsRequiringSeniorParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
 }
 
-const getUser = `-- name: GetUser :one
-SELECT user_id, username, is_active, team_name FROM users
-WHERE user_id = $1
-`
-
-func (q *Queries) GetUser(ctx context.Context, userID string) (User, error) {
-	row := q.db.QueryRow(ctx, getUser, userID)
-	var i User
-	err := row.Scan(
-		&i.UserID,
-		&i.Username,
-		&i.IsActive,
-		&i.TeamName,
-	)
-	return i, err
+type GetPRsRequiringSeniorRow struct {
+	RepositoryKey string `json:"repository_key"`
+	PullRequestID string `json:"pull_request_id"`
 }
 
-const mergePR = `-- name: MergePR :one
-UPDATE pull_requests
-SET status = 'MERGED', merged_at = COALESCE(merged_at, now())
-WHERE pull_request_id = $1
-RETURNING pull_request_id, pull_request_name, author_id, status, merged_at
-`
-
-func (q *Queries) MergePR(ctx context.Context, pullRequestID string) (PullRequest, error) {
-	row := q.db.QueryRow(ctx, mergePR, pullRequestID)
-	var i PullRequest
-	err := row.Scan(
-		&i.PullRequestID,
-		&i.PullRequestName,
-		&i.AuthorID,
-		&i.Status,
-		&i.MergedAt,
-	)
-	return i, err
+func (q *Queries) GetPRsRequiringSenior(ctx context.Context, arg GetPRsRequiringSeniorParams) ([]GetPRsRequiringSeniorRow, error) {
+	rows, err := q.db.Query(ctx, getPRsRequiringSenior, arg.PrRepositories, arg.PrIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPRsRequiringSeniorRow
+	for rows.Next() {
+		var i GetPRsRequiringSeniorRow
+		if err := rows.Scan(&i.RepositoryKey, &i.PullRequestID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const pRExists = `-- name: PRExists :one
-SELECT EXISTS (
-  SELECT 1 FROM pull_requests WHERE pull_request_id = $1
-)
+const getPairRulesByAuthors = `-- name: GetPairRulesByAuthors :many
+SELECT r.team_name, r.reviewer_id, r.author_id, r.kind FROM team_pair_rules r
+JOIN users u ON u.user_id = r.author_id AND u.team_name = r.team_name
+WHERE r.author_id = ANY($1::text[])
+ORDER BY r.author_id, r.reviewer_id
 `
 
-func (q *Queries) PRExists(ctx context.Context, pullRequestID string) (bool, error) {
-	row := q.db.QueryRow(ctx, pRExists, pullRequestID)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+func (q *Queries) GetPairRulesByAuthors(ctx context.Context, authorIds []string) ([]TeamPairRule, error) {
+	rows, err := q.db.Query(ctx, getPairRulesByAuthors, authorIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamPairRule
+	for rows.Next() {
+		var i TeamPairRule
+		if err := rows.Scan(
+			&i.TeamName,
+			&i.ReviewerID,
+			&i.AuthorID,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const replaceReviewer = `-- name: ReplaceReviewer :one
-UPDATE pr_reviewer_assignment
-SET replaced_by = $3
-WHERE pr_id = $1 AND reviewer_id = $2 AND replaced_by IS NULL
-RETURNING assignment_id, pr_id, reviewer_id, assigned_at, replaced_by
+const getRepository = `-- name: GetRepository :one
+SELECT repository_name, team_name FROM repositories
+WHERE repository_name = $1
 `
 
-type ReplaceReviewerParams struct {
-	PrID       string      `json:"pr_id"`
-	ReviewerID string      `json:"reviewer_id"`
-	ReplacedBy pgtype.Text `json:"replaced_by"`
-}
-
-func (q *Queries) ReplaceReviewer(ctx context.Context, arg ReplaceReviewerParams) (PrReviewerAssignment, error) {
-	row := q.db.QueryRow(ctx, replaceReviewer, arg.PrID, arg.ReviewerID, arg.ReplacedBy)
-	var i PrReviewerAssignment
-	err := row.Scan(
-		&i.AssignmentID,
-		&i.PrID,
-		&i.ReviewerID,
-		&i.AssignedAt,
-		&i.ReplacedBy,
-	)
+func (q *Queries) GetRepository(ctx context.Context, repositoryName string) (Repository, error) {
+	row := q.db.QueryRow(ctx, getRepository, repositoryName)
+	var i Repository
+	err := row.Scan(&i.RepositoryName, &i.TeamName)
 	return i, err
 }
 
-const setUserActivity = `-- name: SetUserActivity :one
-UPDATE users
-SET is_active = $2
-WHERE user_id = $1
-RETURNING user_id, username, is_active, team_name
+const getReviewLoad = `-- name: GetReviewLoad :many
+SELECT u.user_id, s.max_open_reviews, COALESCE(s.level, 'regular')::text AS level, COUNT(pr.pull_request_id) AS open_reviews
+FROM users u
+LEFT JOIN reviewer_settings s ON s.user_id = u.user_id
+LEFT JOIN pr_reviewer_assignment pra ON pra.reviewer_id = u.user_id AND pra.replaced_by IS NULL
+LEFT JOIN pull_requests pr ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id AND pr.status = 'OPEN'
+WHERE u.user_id = ANY($1::text[])
+GROUP BY u.user_id, s.max_open_reviews, s.level
+ORDER BY u.user_id
 `
 
-type SetUserActivityParams struct {
-	UserID   string `json:"user_id"`
-	IsActive bool   `json:"is_active"`
+type GetReviewLoadRow struct {
+	UserID         string      `json:"user_id"`
+	MaxOpenReviews pgtype.Int4 `json:"max_open_reviews"`
+	Level          string      `json:"level"`
+	OpenReviews    int64       `json:"open_reviews"`
 }
 
-func (q *Queries) SetUserActivity(ctx context.Context, arg SetUserActivityParams) (User, error) {
-	row := q.db.QueryRow(ctx, setUserActivity, arg.UserID, arg.IsActive)
-	var i User
-	err := row.Scan(
-		&i.UserID,
-		&i.Username,
-		&i.IsActive,
+func (q *Queries) GetReviewLoad(ctx context.Context, userIds []string) ([]GetReviewLoadRow, error) {
+	rows, err := q.db.Query(ctx, getReviewLoad, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReviewLoadRow
+	for rows.Next() {
+		var i GetReviewLoadRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.MaxOpenReviews,
+			&i.Level,
+			&i.OpenReviews,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReviewerStats = `-- name: GetReviewerStats :many
+SELECT pra.reviewer_id, COUNT(*) as assignment_count
+FROM pr_reviewer_assignment pra
+JOIN pull_requests pr ON pr.repository_key = pra.pr_repository AND pr.pull_request_id = pra.pr_id
+WHERE pra.replaced_by IS NULL AND ($1::text = '' OR pr.repository = $1)
+GROUP BY pra.reviewer_id
+ORDER BY assignment_count DESC
+LIMIT 10
+`
+
+type GetReviewerStatsRow struct {
+	ReviewerID      string `json:"reviewer_id"`
+	AssignmentCount int64  `json:"assignment_count"`
+}
+
+func (q *Queries) GetReviewerStats(ctx context.Context, repository string) ([]GetReviewerStatsRow, error) {
+	rows, err := q.db.Query(ctx, getReviewerStats, repository)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReviewerStatsRow
+	for rows.Next() {
+		var i GetReviewerStatsRow
+		if err := rows.Scan(&i.ReviewerID, &i.AssignmentCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getReviewersByPRs = `-- name: GetReviewersByPRs :many
+SELECT pra.pr_repository, pra.pr_id, pra.reviewer_id FROM pr_reviewer_assignment pra
+JOIN unnest($1::text[], $2::text[]) AS k(pr_repository, pr_id)
+  ON pra.pr_repository = k.pr_repository AND pra.pr_id = k.pr_id
+WHERE pra.replaced_by IS NULL
+ORDER BY pra.pr_repository, pra.pr_id, pra.assigned_at
+`
+
+type GetReviewersByPRsParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
+}
+
+type GetReviewersByPRsRow struct {
+	PrRepository string `json:"pr_repository"`
+	PrID         string `json:"pr_id"`
+	ReviewerID   string `json:"reviewer_id"`
+}
+
+func (q *Queries) GetReviewersByPRs(ctx context.Context, arg GetReviewersByPRsParams) ([]GetReviewersByPRsRow, error) {
+	rows, err := q.db.Query(ctx, getReviewersByPRs, arg.PrRepositories, arg.PrIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReviewersByPRsRow
+	for rows.Next() {
+		var i GetReviewersByPRsRow
+		if err := rows.Scan(&i.PrRepository, &i.PrID, &i.ReviewerID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeam = `-- name: GetTeam :many
+SELECT user_id, username, is_active, team_name FROM users
+WHERE team_name = $1
+`
+
+func (q *Queries) GetTeam(ctx context.Context, teamName string) ([]User, error) {
+	rows, err := q.db.Query(ctx, getTeam, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.IsActive,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamFallbacks = `-- name: GetTeamFallbacks :many
+SELECT fallback_team FROM team_fallbacks
+WHERE team_name = $1
+ORDER BY position
+`
+
+func (q *Queries) GetTeamFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getTeamFallbacks, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var fallback_team string
+		if err := rows.Scan(&fallback_team); err != nil {
+			return nil, err
+		}
+		items = append(items, fallback_team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamPairRules = `-- name: GetTeamPairRules :many
+SELECT team_name, reviewer_id, author_id, kind FROM team_pair_rules
+WHERE team_name = $1
+ORDER BY author_id, reviewer_id
+`
+
+func (q *Queries) GetTeamPairRules(ctx context.Context, teamName string) ([]TeamPairRule, error) {
+	rows, err := q.db.Query(ctx, getTeamPairRules, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TeamPairRule
+	for rows.Next() {
+		var i TeamPairRule
+		if err := rows.Scan(
+			&i.TeamName,
+			&i.ReviewerID,
+			&i.AuthorID,
+			&i.Kind,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTeamSettings = `-- name: GetTeamSettings :one
+SELECT team_name, require_senior FROM team_settings
+WHERE team_name = $1
+`
+
+func (q *Queries) GetTeamSettings(ctx context.Context, teamName string) (TeamSetting, error) {
+	row := q.db.QueryRow(ctx, getTeamSettings, teamName)
+	var i TeamSetting
+	err := row.Scan(&i.TeamName, &i.RequireSenior)
+	return i, err
+}
+
+const getTotalActiveUsers = `-- name: GetTotalActiveUsers :one
+SELECT COUNT(*) FROM users WHERE is_active = true
+`
+
+func (q *Queries) GetTotalActiveUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, getTotalActiveUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getUser = `-- name: GetUser :one
+SELECT user_id, username, is_active, team_name FROM users
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUser(ctx context.Context, userID string) (User, error) {
+	row := q.db.QueryRow(ctx, getUser, userID)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Username,
+		&i.IsActive,
+		&i.TeamName,
+	)
+	return i, err
+}
+
+const getUserAbsences = `-- name: GetUserAbsences :many
+SELECT absence_id, user_id, starts_at, ends_at, reviews_released FROM user_absences
+WHERE user_id = $1
+ORDER BY starts_at, ends_at
+`
+
+func (q *Queries) GetUserAbsences(ctx context.Context, userID string) ([]UserAbsence, error) {
+	rows, err := q.db.Query(ctx, getUserAbsences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserAbsence
+	for rows.Next() {
+		var i UserAbsence
+		if err := rows.Scan(
+			&i.AbsenceID,
+			&i.UserID,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.ReviewsReleased,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByIDs = `-- name: GetUsersByIDs :many
+SELECT user_id, username, is_active, team_name FROM users
+WHERE user_id = ANY($1::text[])
+`
+
+func (q *Queries) GetUsersByIDs(ctx context.Context, userIds []string) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByIDs, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.IsActive,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersByTeams = `-- name: GetUsersByTeams :many
+SELECT user_id, username, is_active, team_name FROM users
+WHERE team_name = ANY($1::text[])
+ORDER BY team_name, user_id
+`
+
+func (q *Queries) GetUsersByTeams(ctx context.Context, teamNames []string) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsersByTeams, teamNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.IsActive,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUsersWithHistory = `-- name: GetUsersWithHistory :many
+SELECT author_id AS user_id FROM pull_requests WHERE author_id = ANY($1::text[])
+UNION
+SELECT reviewer_id FROM pr_reviewer_assignment WHERE reviewer_id = ANY($1::text[])
+UNION
+SELECT replaced_by FROM pr_reviewer_assignment WHERE replaced_by = ANY($1::text[])
+`
+
+func (q *Queries) GetUsersWithHistory(ctx context.Context, userIds []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, getUsersWithHistory, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var user_id string
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPRs = `-- name: ListPRs :many
+SELECT pull_request_id, pull_request_name, author_id, status, merged_at, repository, repository_key, missing_senior FROM pull_requests
+WHERE ($1::text = '' OR repository = $1)
+  AND ($2::text = '' OR status::text = $2)
+ORDER BY pull_request_id, repository_key
+`
+
+type ListPRsParams struct {
+	Repository string `json:"repository"`
+	Status     string `json:"status"`
+}
+
+func (q *Queries) ListPRs(ctx context.Context, arg ListPRsParams) ([]PullRequest, error) {
+	rows, err := q.db.Query(ctx, listPRs, arg.Repository, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PullRequest
+	for rows.Next() {
+		var i PullRequest
+		if err := rows.Scan(
+			&i.PullRequestID,
+			&i.PullRequestName,
+			&i.AuthorID,
+			&i.Status,
+			&i.MergedAt,
+			&i.Repository,
+			&i.RepositoryKey,
+			&i.MissingSenior,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRepositories = `-- name: ListRepositories :many
+SELECT repository_name, team_name FROM repositories
+WHERE $1::text = '' OR team_name = $1
+ORDER BY repository_name
+`
+
+func (q *Queries) ListRepositories(ctx context.Context, teamName string) ([]Repository, error) {
+	rows, err := q.db.Query(ctx, listRepositories, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Repository
+	for rows.Next() {
+		var i Repository
+		if err := rows.Scan(&i.RepositoryName, &i.TeamName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOpenPRs = `-- name: LockOpenPRs :many
+SELECT pr.repository_key, pr.pull_request_id FROM pull_requests pr
+JOIN unnest($1::text[], $2::text[]) AS k(repository_key, pull_request_id)
+  ON pr.repository_key = k.repository_key AND pr.pull_request_id = k.pull_request_id
+WHERE pr.status = 'OPEN'
+ORDER BY pr.repository_key, pr.pull_request_id
+FOR UPDATE OF pr
+`
+
+type LockOpenPRsParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
+}
+
+type LockOpenPRsRow struct {
+	RepositoryKey string `json:"repository_key"`
+	PullRequestID string `json:"pull_request_id"`
+}
+
+func (q *Queries) LockOpenPRs(ctx context.Context, arg LockOpenPRsParams) ([]LockOpenPRsRow, error) {
+	rows, err := q.db.Query(ctx, lockOpenPRs, arg.PrRepositories, arg.PrIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockOpenPRsRow
+	for rows.Next() {
+		var i LockOpenPRsRow
+		if err := rows.Scan(&i.RepositoryKey, &i.PullRequestID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const mergePR = `-- name: MergePR :one
+UPDATE pull_requests
+SET status = 'MERGED', merged_at = COALESCE(merged_at, now())
+WHERE repository_key = $1 AND pull_request_id = $2
+RETURNING pull_request_id, pull_request_name, author_id, status, merged_at, repository, repository_key, missing_senior
+`
+
+type MergePRParams struct {
+	RepositoryKey string `json:"repository_key"`
+	PullRequestID string `json:"pull_request_id"`
+}
+
+func (q *Queries) MergePR(ctx context.Context, arg MergePRParams) (PullRequest, error) {
+	row := q.db.QueryRow(ctx, mergePR, arg.RepositoryKey, arg.PullRequestID)
+	var i PullRequest
+	err := row.Scan(
+		&i.PullRequestID,
+		&i.PullRequestName,
+		&i.AuthorID,
+		&i.Status,
+		&i.MergedAt,
+		&i.Repository,
+		&i.RepositoryKey,
+		&i.MissingSenior,
+	)
+	return i, err
+}
+
+const pRExists = `-- name: PRExists :one
+SELECT EXISTS (
+  SELECT 1 FROM pull_requests WHERE repository_key = $1 AND pull_request_id = $2
+)
+`
+
+type PRExistsParams struct {
+	RepositoryKey string `json:"repository_key"`
+	PullRequestID string `json:"pull_request_id"`
+}
+
+func (q *Queries) PRExists(ctx context.Context, arg PRExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, pRExists, arg.RepositoryKey, arg.PullRequestID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const releaseStartedAbsences = `-- name: ReleaseStartedAbsences :many
+UPDATE user_absences
+SET reviews_released = true
+WHERE NOT reviews_released AND starts_at <= now() AND ends_at > now()
+RETURNING user_id
+`
+
+func (q *Queries) ReleaseStartedAbsences(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, releaseStartedAbsences)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var user_id string
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const replaceReviewer = `-- name: ReplaceReviewer :one
+UPDATE pr_reviewer_assignment
+SET replaced_by = $4
+WHERE pr_repository = $1 AND pr_id = $2 AND reviewer_id = $3 AND replaced_by IS NULL
+RETURNING assignment_id, pr_id, reviewer_id, assigned_at, replaced_by, pr_repository
+`
+
+type ReplaceReviewerParams struct {
+	PrRepository string      `json:"pr_repository"`
+	PrID         string      `json:"pr_id"`
+	ReviewerID   string      `json:"reviewer_id"`
+	ReplacedBy   pgtype.Text `json:"replaced_by"`
+}
+
+func (q *Queries) ReplaceReviewer(ctx context.Context, arg ReplaceReviewerParams) (PrReviewerAssignment, error) {
+	row := q.db.QueryRow(ctx, replaceReviewer,
+		arg.PrRepository,
+		arg.PrID,
+		arg.ReviewerID,
+		arg.ReplacedBy,
+	)
+	var i PrReviewerAssignment
+	err := row.Scan(
+		&i.AssignmentID,
+		&i.PrID,
+		&i.ReviewerID,
+		&i.AssignedAt,
+		&i.ReplacedBy,
+		&i.PrRepository,
+	)
+	return i, err
+}
+
+const replaceReviewers = `-- name: ReplaceReviewers :exec
+UPDATE pr_reviewer_assignment AS pra
+SET replaced_by = r.replaced_by
+FROM unnest($1::text[], $2::text[], $3::text[], $4::text[]) AS r(pr_repository, pr_id, reviewer_id, replaced_by)
+WHERE pra.pr_repository = r.pr_repository AND pra.pr_id = r.pr_id AND pra.reviewer_id = r.reviewer_id AND pra.replaced_by IS NULL
+`
+
+type ReplaceReviewersParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
+	ReviewerIds    []string `json:"reviewer_ids"`
+	ReplacedBy     []string `json:"replaced_by"`
+}
+
+func (q *Queries) ReplaceReviewers(ctx context.Context, arg ReplaceReviewersParams) error {
+	_, err := q.db.Exec(ctx, replaceReviewers,
+		arg.PrRepositories,
+		arg.PrIds,
+		arg.ReviewerIds,
+		arg.ReplacedBy,
+	)
+	return err
+}
+
+const setMaxOpenReviews = `-- name: SetMaxOpenReviews :one
+INSERT INTO reviewer_settings (user_id, max_open_reviews)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET max_open_reviews = EXCLUDED.max_open_reviews
+RETURNING user_id, max_open_reviews, level
+`
+
+type SetMaxOpenReviewsParams struct {
+	UserID         string      `json:"user_id"`
+	MaxOpenReviews pgtype.Int4 `json:"max_open_reviews"`
+}
+
+func (q *Queries) SetMaxOpenReviews(ctx context.Context, arg SetMaxOpenReviewsParams) (ReviewerSetting, error) {
+	row := q.db.QueryRow(ctx, setMaxOpenReviews, arg.UserID, arg.MaxOpenReviews)
+	var i ReviewerSetting
+	err := row.Scan(&i.UserID, &i.MaxOpenReviews, &i.Level)
+	return i, err
+}
+
+const setPRMissingSenior = `-- name: SetPRMissingSenior :exec
+UPDATE pull_requests SET missing_senior = $3
+WHERE repository_key = $1 AND pull_request_id = $2
+`
+
+type SetPRMissingSeniorParams struct {
+	RepositoryKey string `json:"repository_key"`
+	PullRequestID string `json:"pull_request_id"`
+	MissingSenior bool   `json:"missing_senior"`
+}
+
+func (q *Queries) SetPRMissingSenior(ctx context.Context, arg SetPRMissingSeniorParams) error {
+	_, err := q.db.Exec(ctx, setPRMissingSenior, arg.RepositoryKey, arg.PullRequestID, arg.MissingSenior)
+	return err
+}
+
+const setPRsMissingSenior = `-- name: SetPRsMissingSenior :exec
+UPDATE pull_requests AS pr
+SET missing_senior = v.missing_senior
+FROM unnest($1::text[], $2::text[], $3::bool[]) AS v(repository_key, pull_request_id, missing_senior)
+WHERE pr.repository_key = v.repository_key AND pr.pull_request_id = v.pull_request_id
+`
+
+type SetPRsMissingSeniorParams struct {
+	PrRepositories []string `json:"pr_repositories"`
+	PrIds          []string `json:"pr_ids"`
+	MissingSenior  []bool   `json:"missing_senior"`
+}
+
+func (q *Queries) SetPRsMissingSenior(ctx context.Context, arg SetPRsMissingSeniorParams) error {
+	_, err := q.db.Exec(ctx, setPRsMissingSenior, arg.PrRepositories, arg.PrIds, arg.MissingSenior)
+	return err
+}
+
+const setRepositoryTeam = `-- name: SetRepositoryTeam :one
+INSERT INTO repositories (repository_name, team_name)
+VALUES ($1, $2)
+ON CONFLICT (repository_name) DO UPDATE
+SET team_name = EXCLUDED.team_name
+RETURNING repository_name, team_name
+`
+
+type SetRepositoryTeamParams struct {
+	RepositoryName string `json:"repository_name"`
+	TeamName       string `json:"team_name"`
+}
+
+func (q *Queries) SetRepositoryTeam(ctx context.Context, arg SetRepositoryTeamParams) (Repository, error) {
+	row := q.db.QueryRow(ctx, setRepositoryTeam, arg.RepositoryName, arg.TeamName)
+	var i Repository
+	err := row.Scan(&i.RepositoryName, &i.TeamName)
+	return i, err
+}
+
+const setTeamPairRule = `-- name: SetTeamPairRule :one
+INSERT INTO team_pair_rules (team_name, reviewer_id, author_id, kind)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (team_name, reviewer_id, author_id) DO UPDATE
+SET kind = EXCLUDED.kind
+RETURNING team_name, reviewer_id, author_id, kind
+`
+
+type SetTeamPairRuleParams struct {
+	TeamName   string `json:"team_name"`
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Kind       string `json:"kind"`
+}
+
+func (q *Queries) SetTeamPairRule(ctx context.Context, arg SetTeamPairRuleParams) (TeamPairRule, error) {
+	row := q.db.QueryRow(ctx, setTeamPairRule,
+		arg.TeamName,
+		arg.ReviewerID,
+		arg.AuthorID,
+		arg.Kind,
+	)
+	var i TeamPairRule
+	err := row.Scan(
+		&i.TeamName,
+		&i.ReviewerID,
+		&i.AuthorID,
+		&i.Kind,
+	)
+	return i, err
+}
+
+const setTeamRequireSenior = `-- name: SetTeamRequireSenior :one
+INSERT INTO team_settings (team_name, require_senior)
+VALUES ($1, $2)
+ON CONFLICT (team_name) DO UPDATE
+SET require_senior = EXCLUDED.require_senior
+RETURNING team_name, require_senior
+`
+
+type SetTeamRequireSeniorParams struct {
+	TeamName      string `json:"team_name"`
+	RequireSenior bool   `json:"require_senior"`
+}
+
+func (q *Queries) SetTeamRequireSenior(ctx context.Context, arg SetTeamRequireSeniorParams) (TeamSetting, error) {
+	row := q.db.QueryRow(ctx, setTeamRequireSenior, arg.TeamName, arg.RequireSenior)
+	var i TeamSetting
+	err := row.Scan(&i.TeamName, &i.RequireSenior)
+	return i, err
+}
+
+const setUserActivity = `-- name: SetUserActivity :one
+UPDATE users
+SET is_active = $2
+WHERE user_id = $1
+RETURNING user_id, username, is_active, team_name
+`
+
+type SetUserActivityParams struct {
+	UserID   string `json:"user_id"`
+	IsActive bool   `json:"is_active"`
+}
+
+func (q *Queries) SetUserActivity(ctx context.Context, arg SetUserActivityParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserActivity, arg.UserID, arg.IsActive)
+	var i User
+	err := row.Scan(
+		&i.UserID,
+		&i.Username,
+		&i.IsActive,
 		&i.TeamName,
 	)
 	return i, err
 }
 
+const setUserLevel = `-- name: SetUserLevel :one
+INSERT INTO reviewer_settings (user_id, level)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET level = EXCLUDED.level
+RETURNING user_id, max_open_reviews, level
+`
+
+type SetUserLevelParams struct {
+	UserID string `json:"user_id"`
+	Level  string `json:"level"`
+}
+
+func (q *Queries) SetUserLevel(ctx context.Context, arg SetUserLevelParams) (ReviewerSetting, error) {
+	row := q.db.QueryRow(ctx, setUserLevel, arg.UserID, arg.Level)
+	var i ReviewerSetting
+	err := row.Scan(&i.UserID, &i.MaxOpenReviews, &i.Level)
+	return i, err
+}
+
+const setUsersActivity = `-- name: SetUsersActivity :many
+UPDATE users AS u
+SET is_active = v.is_active
+FROM unnest($1::text[], $2::bool[]) AS v(user_id, is_active)
+WHERE u.user_id = v.user_id
+RETURNING u.user_id, u.username, u.is_active, u.team_name
+`
+
+type SetUsersActivityParams struct {
+	UserIds  []string `json:"user_ids"`
+	IsActive []bool   `json:"is_active"`
+}
+
+func (q *Queries) SetUsersActivity(ctx context.Context, arg SetUsersActivityParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, setUsersActivity, arg.UserIds, arg.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.IsActive,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const takeRateLimitToken = `-- name: TakeRateLimitToken :one
+INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, allowed, updated_at)
+VALUES ($1, $2::float8 - 1, true, now())
+ON CONFLICT (bucket_key) DO UPDATE
+SET
+    allowed = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * $3::float8) >= 1,
+    tokens = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * $3::float8)
+        - CASE WHEN LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at) * $3::float8) >= 1 THEN 1 ELSE 0 END,
+    updated_at = now()
+RETURNING tokens, allowed
+`
+
+type TakeRateLimitTokenParams struct {
+	BucketKey string  `json:"bucket_key"`
+	Burst     float64 `json:"burst"`
+	Rate      float64 `json:"rate"`
+}
+
+type TakeRateLimitTokenRow struct {
+	Tokens  float64 `json:"tokens"`
+	Allowed bool    `json:"allowed"`
+}
+
+func (q *Queries) TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error) {
+	row := q.db.QueryRow(ctx, takeRateLimitToken, arg.BucketKey, arg.Burst, arg.Rate)
+	var i TakeRateLimitTokenRow
+	err := row.Scan(&i.Tokens, &i.Allowed)
+	return i, err
+}
+
 const teamExists = `-- name: TeamExists :one
 SELECT EXISTS (
   SELECT 1 FROM users WHERE team_name = $1
@@ -452,3 +1528,27 @@ func (q *Queries) TeamExists(ctx context.Context, teamName string) (bool, error)
 	err := row.Scan(&exists)
 	return exists, err
 }
+
+const upsertCodeOwners = `-- name: UpsertCodeOwners :one
+INSERT INTO codeowners (repository, content)
+VALUES ($1, $2)
+ON CONFLICT (repository) DO UPDATE
+SET content = EXCLUDED.content, uploaded_at = now()
+RETURNING repository, content, uploaded_at
+`
+
+type UpsertCodeOwnersParams struct {
+	Repository string `json:"repository"`
+	Content    string `json:"content"`
+}
+
+func (q *Queries) UpsertCodeOwners(ctx context.Context, arg UpsertCodeOwnersParams) (Codeowner, error) {
+	row := q.db.QueryRow(ctx, upsertCodeOwners, arg.Repository, arg.Content)
+	var i Codeowner
+	err := row.Scan(
+		&i.Repository,
+		&i.Content,
+		&i.UploadedAt,
+	)
+	return i, err
+}
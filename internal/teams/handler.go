@@ -2,10 +2,12 @@
 package teams
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
 )
 
 // GetTeamByName handles the retrieval of a team by its name.
@@ -14,7 +16,7 @@ func (h *Handler) GetTeamByName(w http.ResponseWriter, r *http.Request) {
 
 	users, err := h.service.GetTeamByName(r.Context(), teamName)
 	if err != nil || len(users) == 0 {
-		errors.WriteAppError(w, "team not found", errors.ErrNotFound)
+		errors.WriteAppError(w, r, "team not found", errors.ErrNotFound)
 		return
 	}
 
@@ -37,15 +39,15 @@ func (h *Handler) GetTeamByName(w http.ResponseWriter, r *http.Request) {
 
 // CreateTeam handles the creation of a new team.
 func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
-	var req tempTeamParams
+	var req CreateTeamParams
 	if err := json.Read(r, &req); err != nil {
-		errors.WriteAppError(w, "invalid json in CreateTeam", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "invalid json in CreateTeam", errors.InvalidJSON(err))
 		return
 	}
 
 	users, err := h.service.CreateTeam(r.Context(), req)
 	if err != nil {
-		errors.WriteAppError(w, "error during creating team", err)
+		errors.WriteAppError(w, r, "error during creating team", err)
 		return
 	}
 
@@ -73,18 +75,152 @@ func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeactivateUsers(w http.ResponseWriter, r *http.Request) {
 	var req DeactivateUsersRequest
 	if err := json.Read(r, &req); err != nil {
-		errors.WriteAppError(w, "invalid json", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "invalid json", errors.InvalidJSON(err))
 		return
 	}
 
 	if len(req.Users) == 0 {
-		errors.WriteAppError(w, "users list is required", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "users list is required", errors.InvalidField("users", "must contain at least one user id"))
+		return
+	}
+	if len(req.Users) > MaxDeactivateUsers {
+		errors.WriteAppError(w, r, "users list is too long", errors.InvalidField("users", fmt.Sprintf("must contain at most %d user ids", MaxDeactivateUsers)))
+		return
+	}
+	for i, uid := range req.Users {
+		if uid == "" {
+			errors.WriteAppError(w, r, "empty user id", errors.InvalidField(fmt.Sprintf("users[%d]", i), "must not be empty"))
+			return
+		}
+	}
+
+	response, err := h.service.DeactivateUsers(r.Context(), req.Users, req.DryRun)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to deactivate users", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// UpsertMembers handles adding, updating and removing members of an existing team.
+func (h *Handler) UpsertMembers(w http.ResponseWriter, r *http.Request) {
+	var req UpsertMembersParams
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in UpsertMembers", errors.InvalidJSON(err))
+		return
+	}
+
+	response, err := h.service.UpsertMembers(r.Context(), req)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to upsert team members", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// GetFallbacks handles the retrieval of the fallback teams of a team.
+func (h *Handler) GetFallbacks(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.GetFallbacks(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to get fallback teams", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// SetFallbacks handles replacing the fallback teams of a team.
+func (h *Handler) SetFallbacks(w http.ResponseWriter, r *http.Request) {
+	var req FallbacksParams
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in SetFallbacks", errors.InvalidJSON(err))
+		return
+	}
+
+	response, err := h.service.SetFallbacks(r.Context(), req)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to set fallback teams", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// GetRules handles the retrieval of the reviewer rules of a team.
+func (h *Handler) GetRules(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.GetRules(r.Context(), r.URL.Query().Get("team_name"))
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to get team rules", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// SetRequireSenior handles switching the required senior rule of a team.
+func (h *Handler) SetRequireSenior(w http.ResponseWriter, r *http.Request) {
+	var req SetRequireSeniorRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in SetRequireSenior", errors.InvalidJSON(err))
+		return
+	}
+
+	if req.RequireSenior == nil {
+		errors.WriteAppError(w, r, "require_senior field is required", errors.InvalidField("require_senior", "is required"))
+		return
+	}
+
+	response, err := h.service.SetRequireSenior(r.Context(), repo.SetTeamRequireSeniorParams{
+		TeamName:      req.TeamName,
+		RequireSenior: *req.RequireSenior,
+	})
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to set required senior rule", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// SetPairRule handles adding or changing a pair rule of a team.
+func (h *Handler) SetPairRule(w http.ResponseWriter, r *http.Request) {
+	var req PairRuleRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in SetPairRule", errors.InvalidJSON(err))
+		return
+	}
+
+	response, err := h.service.SetPairRule(r.Context(), repo.SetTeamPairRuleParams{
+		TeamName:   req.TeamName,
+		ReviewerID: req.ReviewerID,
+		AuthorID:   req.AuthorID,
+		Kind:       req.Kind,
+	})
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to set pair rule", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// DeletePairRule handles removing a pair rule of a team.
+func (h *Handler) DeletePairRule(w http.ResponseWriter, r *http.Request) {
+	var req DeletePairRuleRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in DeletePairRule", errors.InvalidJSON(err))
 		return
 	}
 
-	response, err := h.service.DeactivateUsers(r.Context(), req.Users)
+	response, err := h.service.DeletePairRule(r.Context(), repo.DeleteTeamPairRuleParams{
+		TeamName:   req.TeamName,
+		ReviewerID: req.ReviewerID,
+		AuthorID:   req.AuthorID,
+	})
 	if err != nil {
-		errors.WriteAppError(w, "failed to deactivate users", err)
+		errors.WriteAppError(w, r, "failed to delete pair rule", err)
 		return
 	}
 
@@ -2,31 +2,44 @@ package teams
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"maps"
 	"math/rand"
+	"slices"
+	"time"
 
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
-	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5"
 )
 
 func (s *svc) GetTeamByName(ctx context.Context, teamName string) ([]repo.User, error) {
 	return s.repo.GetTeam(ctx, teamName)
 }
 
-func (s *svc) CreateTeam(ctx context.Context, tempTeam tempTeamParams) ([]repo.User, error) {
+func (s *svc) CreateTeam(ctx context.Context, tempTeam CreateTeamParams) ([]repo.User, error) {
 	// validation
+	var details []errors.FieldError
 	if tempTeam.TeamName == "" {
-		return nil, errors.ErrInvalidInput
+		details = append(details, errors.FieldError{Field: "team_name", Reason: "must not be empty"})
 	}
 	if len(tempTeam.Members) == 0 {
-		return nil, errors.ErrInvalidInput
+		details = append(details, errors.FieldError{Field: "members", Reason: "must contain at least one member"})
 	}
-	for _, u := range tempTeam.Members {
+	if len(tempTeam.Members) > MaxTeamMembers {
+		details = append(details, errors.FieldError{Field: "members", Reason: fmt.Sprintf("must contain at most %d members", MaxTeamMembers)})
+	}
+	for i, u := range tempTeam.Members {
 		if u.Username == "" {
-			return nil, errors.ErrInvalidInput
+			details = append(details, errors.FieldError{Field: fmt.Sprintf("members[%d].username", i), Reason: "must not be empty"})
 		}
 	}
+	if len(details) > 0 {
+		return nil, errors.ErrInvalidInput.WithDetails(details...)
+	}
 
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
@@ -66,140 +79,730 @@ func (s *svc) CreateTeam(ctx context.Context, tempTeam tempTeamParams) ([]repo.U
 	return createdUsers, nil
 }
 
-func (s *svc) DeactivateUsers(ctx context.Context, userIDs []string) (DeactivateUsersResponse, error) {
+// GetFallbacks returns the fallback teams of the team in the order they are tried.
+func (s *svc) GetFallbacks(ctx context.Context, teamName string) (FallbacksResponse, error) {
+	if teamName == "" {
+		return FallbacksResponse{}, errors.InvalidField("team_name", "must not be empty")
+	}
+
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return FallbacksResponse{}, err
+	}
+	if !exists {
+		return FallbacksResponse{}, errors.ErrNotFound.WithDetails(errors.FieldError{Field: "team_name", Reason: "team not found"})
+	}
+
+	fallbacks, err := s.repo.GetTeamFallbacks(ctx, teamName)
+	if err != nil {
+		return FallbacksResponse{}, err
+	}
+
+	return FallbacksResponse{TeamName: teamName, FallbackTeams: append([]string{}, fallbacks...)}, nil
+}
+
+// SetFallbacks replaces the ordered list of teams that supply reviewers to new PRs
+// of the team when it has too few candidates itself.
+func (s *svc) SetFallbacks(ctx context.Context, params FallbacksParams) (FallbacksResponse, error) {
+	// validation
+	var details []errors.FieldError
+	if params.TeamName == "" {
+		details = append(details, errors.FieldError{Field: "team_name", Reason: "must not be empty"})
+	}
+	if len(params.FallbackTeams) > MaxFallbackTeams {
+		details = append(details, errors.FieldError{Field: "fallback_teams", Reason: fmt.Sprintf("must contain at most %d teams", MaxFallbackTeams)})
+	}
+	for i, team := range params.FallbackTeams {
+		field := fmt.Sprintf("fallback_teams[%d]", i)
+		switch {
+		case team == "":
+			details = append(details, errors.FieldError{Field: field, Reason: "must not be empty"})
+		case team == params.TeamName:
+			details = append(details, errors.FieldError{Field: field, Reason: "must not be the team itself"})
+		case slices.Contains(params.FallbackTeams[:i], team):
+			details = append(details, errors.FieldError{Field: field, Reason: "is duplicated"})
+		}
+	}
+	if len(details) > 0 {
+		return FallbacksResponse{}, errors.ErrInvalidInput.WithDetails(details...)
+	}
+
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return DeactivateUsersResponse{}, errors.InternalError
+		return FallbacksResponse{}, errors.InternalError
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
 	qtx := s.repo.WithTx(tx)
 
-	deactivatedMap := make(map[string]bool)
-	for _, uid := range userIDs {
-		deactivatedMap[uid] = true
+	exists, err := qtx.TeamExists(ctx, params.TeamName)
+	if err != nil {
+		return FallbacksResponse{}, err
+	}
+	if !exists {
+		return FallbacksResponse{}, errors.ErrNotFound.WithDetails(errors.FieldError{Field: "team_name", Reason: "team not found"})
 	}
 
-	updatedPRsMap := make(map[string]struct{})
+	// a team exists while it has members
+	members, err := qtx.GetUsersByTeams(ctx, params.FallbackTeams)
+	if err != nil {
+		return FallbacksResponse{}, err
+	}
+	found := make(map[string]bool)
+	for _, m := range members {
+		found[m.TeamName] = true
+	}
+	for i, team := range params.FallbackTeams {
+		if !found[team] {
+			details = append(details, errors.FieldError{Field: fmt.Sprintf("fallback_teams[%d]", i), Reason: "team not found"})
+		}
+	}
+	if len(details) > 0 {
+		return FallbacksResponse{}, errors.ErrNotFound.WithDetails(details...)
+	}
 
-	for _, uid := range userIDs {
-		// Get user to find team name
-		user, err := qtx.GetUser(ctx, uid)
+	if err := qtx.DeleteTeamFallbacks(ctx, params.TeamName); err != nil {
+		return FallbacksResponse{}, err
+	}
+	if len(params.FallbackTeams) > 0 {
+		err := qtx.AddTeamFallbacks(ctx, repo.AddTeamFallbacksParams{TeamName: params.TeamName, FallbackTeams: params.FallbackTeams})
 		if err != nil {
-			return DeactivateUsersResponse{}, err
+			return FallbacksResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return FallbacksResponse{}, errors.InternalError
+	}
+
+	return FallbacksResponse{TeamName: params.TeamName, FallbackTeams: append([]string{}, params.FallbackTeams...)}, nil
+}
+
+// GetRules returns the reviewer rules of the team, a team without settings has none.
+func (s *svc) GetRules(ctx context.Context, teamName string) (RulesResponse, error) {
+	if teamName == "" {
+		return RulesResponse{}, errors.InvalidField("team_name", "must not be empty")
+	}
+
+	if err := s.checkTeam(ctx, teamName); err != nil {
+		return RulesResponse{}, err
+	}
+
+	return s.rules(ctx, teamName)
+}
+
+// SetRequireSenior switches the rule that PRs of the team's authors need a senior reviewer.
+// Reviewers of open PRs are not changed.
+func (s *svc) SetRequireSenior(ctx context.Context, params repo.SetTeamRequireSeniorParams) (RulesResponse, error) {
+	if params.TeamName == "" {
+		return RulesResponse{}, errors.InvalidField("team_name", "must not be empty")
+	}
+
+	if err := s.checkTeam(ctx, params.TeamName); err != nil {
+		return RulesResponse{}, err
+	}
+
+	if _, err := s.repo.SetTeamRequireSenior(ctx, params); err != nil {
+		return RulesResponse{}, err
+	}
+
+	return s.rules(ctx, params.TeamName)
+}
+
+// SetPairRule makes the reviewer never assigned to, or preferred for, the PRs by
+// the author, a member of the team. The rule applies while the author is in the
+// team, reviewers of open PRs are not changed.
+func (s *svc) SetPairRule(ctx context.Context, params repo.SetTeamPairRuleParams) (RulesResponse, error) {
+	// validation
+	details := validatePair(params.TeamName, params.ReviewerID, params.AuthorID)
+	if params.Kind != domain.PairNever && params.Kind != domain.PairPrefer {
+		details = append(details, errors.FieldError{Field: "kind", Reason: "must be never or prefer"})
+	}
+	if len(details) > 0 {
+		return RulesResponse{}, errors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	if err := s.checkTeam(ctx, params.TeamName); err != nil {
+		return RulesResponse{}, err
+	}
+
+	users, err := s.repo.GetUsersByIDs(ctx, []string{params.ReviewerID, params.AuthorID})
+	if err != nil {
+		return RulesResponse{}, err
+	}
+	found := make(map[string]repo.User, len(users))
+	for _, u := range users {
+		found[u.UserID] = u
+	}
+	for _, f := range []struct{ field, id string }{{"reviewer_id", params.ReviewerID}, {"author_id", params.AuthorID}} {
+		if _, ok := found[f.id]; !ok {
+			details = append(details, errors.FieldError{Field: f.field, Reason: "user not found"})
+		}
+	}
+	if len(details) > 0 {
+		return RulesResponse{}, errors.ErrNotFound.WithDetails(details...)
+	}
+	if found[params.AuthorID].TeamName != params.TeamName {
+		return RulesResponse{}, errors.InvalidField("author_id", "must be a member of the team")
+	}
+
+	if _, err := s.repo.SetTeamPairRule(ctx, params); err != nil {
+		return RulesResponse{}, err
+	}
+
+	return s.rules(ctx, params.TeamName)
+}
+
+// DeletePairRule removes the pair rule of the reviewer and the author.
+func (s *svc) DeletePairRule(ctx context.Context, params repo.DeleteTeamPairRuleParams) (RulesResponse, error) {
+	if details := validatePair(params.TeamName, params.ReviewerID, params.AuthorID); len(details) > 0 {
+		return RulesResponse{}, errors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	if _, err := s.repo.DeleteTeamPairRule(ctx, params); err != nil {
+		if stderrors.Is(err, pgx.ErrNoRows) {
+			return RulesResponse{}, errors.ErrNotFound.WithDetails(errors.FieldError{Field: "reviewer_id", Reason: "pair rule not found"})
 		}
+		return RulesResponse{}, err
+	}
+
+	return s.rules(ctx, params.TeamName)
+}
+
+func validatePair(teamName, reviewerID, authorID string) []errors.FieldError {
+	var details []errors.FieldError
+	if teamName == "" {
+		details = append(details, errors.FieldError{Field: "team_name", Reason: "must not be empty"})
+	}
+	if reviewerID == "" {
+		details = append(details, errors.FieldError{Field: "reviewer_id", Reason: "must not be empty"})
+	}
+	if authorID == "" {
+		details = append(details, errors.FieldError{Field: "author_id", Reason: "must not be empty"})
+	}
+	if reviewerID != "" && reviewerID == authorID {
+		details = append(details, errors.FieldError{Field: "reviewer_id", Reason: "must differ from author_id"})
+	}
 
-		// 1. Deactivate user
-		_, err = qtx.SetUserActivity(ctx, repo.SetUserActivityParams{
-			UserID:   uid,
-			IsActive: false,
+	return details
+}
+
+// checkTeam fails with NOT_FOUND if the team has no members.
+func (s *svc) checkTeam(ctx context.Context, teamName string) error {
+	exists, err := s.repo.TeamExists(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.ErrNotFound.WithDetails(errors.FieldError{Field: "team_name", Reason: "team not found"})
+	}
+
+	return nil
+}
+
+// rules reads the settings and the pair rules of the team.
+func (s *svc) rules(ctx context.Context, teamName string) (RulesResponse, error) {
+	settings, err := s.repo.GetTeamSettings(ctx, teamName)
+	if err != nil && !stderrors.Is(err, pgx.ErrNoRows) {
+		return RulesResponse{}, err
+	}
+	pairs, err := s.repo.GetTeamPairRules(ctx, teamName)
+	if err != nil {
+		return RulesResponse{}, err
+	}
+
+	response := RulesResponse{TeamName: teamName, RequireSenior: settings.RequireSenior, PairRules: make([]PairRule, len(pairs))}
+	for i, p := range pairs {
+		response.PairRules[i] = PairRule{ReviewerID: p.ReviewerID, AuthorID: p.AuthorID, Kind: p.Kind}
+	}
+
+	return response, nil
+}
+
+// UpsertMembers adds, updates and removes members of an existing team in one
+// transaction. Like CreateTeam it moves users from other teams. A removed member
+// is deleted, unless PRs or reviews refer to them: then they stay and are
+// reported as in use, deactivating them keeps the history.
+func (s *svc) UpsertMembers(ctx context.Context, params UpsertMembersParams) (UpsertMembersResponse, error) {
+	// validation
+	var details []errors.FieldError
+	if params.TeamName == "" {
+		details = append(details, errors.FieldError{Field: "team_name", Reason: "must not be empty"})
+	}
+	if len(params.Members) == 0 && len(params.Remove) == 0 {
+		details = append(details, errors.FieldError{Field: "members", Reason: "members or remove must not be empty"})
+	}
+	if len(params.Members) > MaxTeamMembers {
+		details = append(details, errors.FieldError{Field: "members", Reason: fmt.Sprintf("must contain at most %d members", MaxTeamMembers)})
+	}
+	if len(params.Remove) > MaxTeamMembers {
+		details = append(details, errors.FieldError{Field: "remove", Reason: fmt.Sprintf("must contain at most %d user ids", MaxTeamMembers)})
+	}
+	seen := make(map[string]bool)
+	checkID := func(field, id string) {
+		switch {
+		case id == "":
+			details = append(details, errors.FieldError{Field: field, Reason: "must not be empty"})
+		case seen[id]:
+			details = append(details, errors.FieldError{Field: field, Reason: "is duplicated"})
+		}
+		seen[id] = true
+	}
+	for i, u := range params.Members {
+		checkID(fmt.Sprintf("members[%d].user_id", i), u.UserID)
+		if u.Username == "" {
+			details = append(details, errors.FieldError{Field: fmt.Sprintf("members[%d].username", i), Reason: "must not be empty"})
+		}
+	}
+	for i, id := range params.Remove {
+		checkID(fmt.Sprintf("remove[%d]", i), id)
+	}
+	if len(details) > 0 {
+		return UpsertMembersResponse{}, errors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return UpsertMembersResponse{}, errors.InternalError
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.repo.WithTx(tx)
+
+	exists, err := qtx.TeamExists(ctx, params.TeamName)
+	if err != nil {
+		return UpsertMembersResponse{}, err
+	}
+	if !exists {
+		return UpsertMembersResponse{}, errors.ErrNotFound.WithDetails(errors.FieldError{Field: "team_name", Reason: "team not found"})
+	}
+
+	users, err := qtx.GetUsersByIDs(ctx, slices.Collect(maps.Keys(seen)))
+	if err != nil {
+		return UpsertMembersResponse{}, err
+	}
+	current := make(map[string]repo.User, len(users))
+	for _, u := range users {
+		current[u.UserID] = u
+	}
+
+	response := UpsertMembersResponse{Results: make([]MemberResult, 0, len(params.Members)+len(params.Remove))}
+	for _, m := range params.Members {
+		result := MemberResult{UserID: m.UserID, Result: MemberUpdated}
+		u, ok := current[m.UserID]
+		switch {
+		case !ok:
+			result.Result = MemberAdded
+		case u.TeamName != params.TeamName:
+			result.Result, result.FromTeam = MemberMoved, u.TeamName
+		case u.Username == m.Username && u.IsActive == m.IsActive:
+			result.Result = MemberUnchanged
+		}
+		response.Results = append(response.Results, result)
+		if result.Result == MemberUnchanged {
+			continue
+		}
+
+		_, err := qtx.CreateUser(ctx, repo.CreateUserParams{
+			UserID:   m.UserID,
+			Username: m.Username,
+			IsActive: m.IsActive,
+			TeamName: params.TeamName,
 		})
 		if err != nil {
-			return DeactivateUsersResponse{}, err
+			return UpsertMembersResponse{}, err
 		}
+	}
 
-		// 2. Get active assignments
-		prs, err := qtx.GetPRsByReviewer(ctx, uid)
-		if err != nil {
-			return DeactivateUsersResponse{}, err
+	// members with PRs or reviews cannot be deleted
+	var members []string
+	for _, id := range params.Remove {
+		if u, ok := current[id]; ok && u.TeamName == params.TeamName {
+			members = append(members, id)
+		}
+	}
+	inUse, err := qtx.GetUsersWithHistory(ctx, members)
+	if err != nil {
+		return UpsertMembersResponse{}, err
+	}
+
+	var removed []string
+	for _, id := range params.Remove {
+		result := MemberResult{UserID: id, Result: MemberRemoved}
+		switch {
+		case !slices.Contains(members, id):
+			result.Result = MemberNotFound
+		case slices.Contains(inUse, id):
+			result.Result = MemberInUse
+		default:
+			removed = append(removed, id)
+		}
+		response.Results = append(response.Results, result)
+	}
+	if len(removed) > 0 {
+		if err := qtx.DeleteUsers(ctx, removed); err != nil {
+			return UpsertMembersResponse{}, err
 		}
+	}
 
-		for _, pr := range prs {
-			updatedPRsMap[pr.PullRequestID] = struct{}{}
+	team, err := qtx.GetTeam(ctx, params.TeamName)
+	if err != nil {
+		return UpsertMembersResponse{}, err
+	}
+	response.Team = TeamResponse{TeamName: params.TeamName, Members: make([]TeamMember, len(team))}
+	for i, u := range team {
+		response.Team.Members[i] = TeamMember{UserID: u.UserID, Username: u.Username, IsActive: u.IsActive}
+	}
 
-			// 3. Find replacement
-			candidates, err := qtx.GetActiveTeamMembersExcept(ctx, repo.GetActiveTeamMembersExceptParams{
-				TeamName: user.TeamName,
-				UserID:   pr.AuthorID,
+	if err := tx.Commit(ctx); err != nil {
+		return UpsertMembersResponse{}, errors.InternalError
+	}
+
+	return response, nil
+}
+
+// DeactivateUsers loads everything it needs with a few set-based queries,
+// plans the replacements in memory and writes them in batches, so the number
+// of statements does not grow with the number of users or PRs.
+func (s *svc) DeactivateUsers(ctx context.Context, userIDs []string, dryRun bool) (DeactivateUsersResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return DeactivateUsersResponse{}, errors.InternalError
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.repo.WithTx(tx)
+
+	// 1. Find the users and their teams
+	users, err := qtx.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	teamOf := make(map[string]string, len(users))
+	for _, u := range users {
+		teamOf[u.UserID] = u.TeamName
+	}
+	for i, uid := range userIDs {
+		if _, ok := teamOf[uid]; !ok {
+			return DeactivateUsersResponse{}, errors.ErrNotFound.WithDetails(errors.FieldError{
+				Field:  fmt.Sprintf("users[%d]", i),
+				Reason: "user not found",
 			})
-			if err != nil {
-				return DeactivateUsersResponse{}, err
-			}
+		}
+	}
 
-			currentReviewers, err := qtx.GetPRReviewers(ctx, pr.PullRequestID)
-			if err != nil {
-				return DeactivateUsersResponse{}, err
-			}
-			currentReviewerMap := make(map[string]bool)
-			for _, r := range currentReviewers {
-				currentReviewerMap[r] = true
+	// 2. Deactivate them
+	if err := qtx.DeactivateUsers(ctx, userIDs); err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+
+	// 3-6. Hand over their open reviews
+	response, err := handOver(ctx, qtx, userIDs, teamOf)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	response.DryRun = dryRun
+
+	// the deferred rollback discards the plan
+	if dryRun {
+		return response, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return DeactivateUsersResponse{}, errors.InternalError
+	}
+
+	return response, nil
+}
+
+// ReleaseAbsentReviews hands over the open reviews of users whose absence has
+// begun, like DeactivateUsers does, but leaves them active. Every absence is
+// handled once, reviews assigned to the user later on are not taken away.
+func (s *svc) ReleaseAbsentReviews(ctx context.Context) (DeactivateUsersResponse, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return DeactivateUsersResponse{}, errors.InternalError
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.repo.WithTx(tx)
+
+	started, err := qtx.ReleaseStartedAbsences(ctx)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	userIDs := slices.Compact(slices.Sorted(slices.Values(started)))
+	if len(userIDs) == 0 {
+		return DeactivateUsersResponse{Reassignments: []Reassignment{}, Users: []*UserReport{}}, nil
+	}
+
+	users, err := qtx.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	teamOf := make(map[string]string, len(users))
+	for _, u := range users {
+		teamOf[u.UserID] = u.TeamName
+	}
+
+	response, err := handOver(ctx, qtx, userIDs, teamOf)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return DeactivateUsersResponse{}, errors.InternalError
+	}
+
+	return response, nil
+}
+
+// handOver reassigns the reviews of the leaving users on open PRs to other
+// active and present members of their teams below their review limit, reviews
+// nobody can take over are dropped. Members ruled out for the PR's author never
+// take over, a senior does when the PR requires one and no other reviewer is,
+// then preferred members do. teamOf maps every leaving user to their team.
+func handOver(ctx context.Context, qtx *repo.Queries, userIDs []string, teamOf map[string]string) (DeactivateUsersResponse, error) {
+	// 3. Load their reviews, only open PRs are touched: reviewers of merged
+	// PRs are history and never change. The open ones are locked, so a PR
+	// merged concurrently is either seen as merged or waits for the commit.
+	reviews, err := qtx.GetPRsByReviewers(ctx, userIDs)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	prs := make(map[domain.PRKey]repo.GetPRsByReviewersRow)
+	var keys repo.LockOpenPRsParams
+	for _, r := range reviews {
+		key := domain.PRKey{Repository: r.RepositoryKey, PullRequestID: r.PullRequestID}
+		if _, ok := prs[key]; !ok {
+			keys.PrRepositories = append(keys.PrRepositories, key.Repository)
+			keys.PrIds = append(keys.PrIds, key.PullRequestID)
+		}
+		prs[key] = r
+	}
+
+	locked, err := qtx.LockOpenPRs(ctx, keys)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	prKeys := make([]domain.PRKey, len(locked))
+	keys = repo.LockOpenPRsParams{}
+	for i, l := range locked {
+		prKeys[i] = domain.PRKey{Repository: l.RepositoryKey, PullRequestID: l.PullRequestID}
+		keys.PrRepositories = append(keys.PrRepositories, l.RepositoryKey)
+		keys.PrIds = append(keys.PrIds, l.PullRequestID)
+	}
+
+	assigned, err := qtx.GetReviewersByPRs(ctx, repo.GetReviewersByPRsParams(keys))
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	reviewers := make(map[domain.PRKey][]string, len(prKeys))
+	for _, a := range assigned {
+		key := domain.PRKey{Repository: a.PrRepository, PullRequestID: a.PrID}
+		reviewers[key] = append(reviewers[key], a.ReviewerID)
+	}
+	requiringRows, err := qtx.GetPRsRequiringSenior(ctx, repo.GetPRsRequiringSeniorParams(keys))
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	requiring := make(map[domain.PRKey]bool, len(requiringRows))
+	for _, r := range requiringRows {
+		requiring[domain.PRKey{Repository: r.RepositoryKey, PullRequestID: r.PullRequestID}] = true
+	}
+	var authorIDs []string
+	for _, key := range prKeys {
+		authorIDs = append(authorIDs, prs[key].AuthorID)
+	}
+	pairs, err := qtx.GetPairRulesByAuthors(ctx, slices.Compact(slices.Sorted(slices.Values(authorIDs))))
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	rules := make(map[string]domain.PairRules)
+	for _, p := range pairs {
+		if rules[p.AuthorID] == nil {
+			rules[p.AuthorID] = make(domain.PairRules)
+		}
+		rules[p.AuthorID][p.ReviewerID] = p.Kind
+	}
+
+	// 4. Load the candidate pools: active members of the teams who are
+	// neither leaving nor absent, how many more reviews they accept and
+	// which of them and of the current reviewers are seniors
+	teamNames := slices.Compact(slices.Sorted(maps.Values(teamOf)))
+	members, err := qtx.GetUsersByTeams(ctx, teamNames)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	memberIDs := make([]string, len(members))
+	for i, m := range members {
+		memberIDs[i] = m.UserID
+	}
+	absent, err := qtx.GetAbsentUsers(ctx, memberIDs)
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	reviewerIDs := make([]string, len(assigned))
+	for i, a := range assigned {
+		reviewerIDs[i] = a.ReviewerID
+	}
+	load, err := qtx.GetReviewLoad(ctx, slices.Concat(memberIDs, reviewerIDs))
+	if err != nil {
+		return DeactivateUsersResponse{}, err
+	}
+	// room is the number of reviews a capped member can still take
+	room := make(map[string]int64)
+	senior := make(map[string]bool)
+	for _, l := range load {
+		senior[l.UserID] = domain.IsSenior(l.Level)
+		if l.MaxOpenReviews.Valid {
+			room[l.UserID] = max(0, int64(l.MaxOpenReviews.Int32)-l.OpenReviews)
+		}
+	}
+	active := make(map[string][]string)
+	for _, m := range members {
+		if _, leaving := teamOf[m.UserID]; m.IsActive && !leaving && !slices.Contains(absent, m.UserID) {
+			active[m.TeamName] = append(active[m.TeamName], m.UserID)
+		}
+	}
+
+	// 5. Plan the replacements
+	response := DeactivateUsersResponse{Reassignments: []Reassignment{}, Users: []*UserReport{}}
+	reports := make(map[string]*UserReport, len(userIDs))
+	for _, uid := range userIDs {
+		if reports[uid] == nil {
+			reports[uid] = &UserReport{UserID: uid, Reassigned: []string{}, LostReviewer: []string{}, Skipped: []string{}}
+			response.Users = append(response.Users, reports[uid])
+		}
+	}
+	for _, r := range reviews {
+		if !slices.Contains(prKeys, domain.PRKey{Repository: r.RepositoryKey, PullRequestID: r.PullRequestID}) {
+			reports[r.ReviewerID].Skipped = append(reports[r.ReviewerID].Skipped, r.PullRequestID)
+		}
+	}
+
+	var (
+		replaced repo.ReplaceReviewersParams
+		added    repo.AssignReviewersParams
+		removed  repo.DeleteReviewersParams
+		missing  repo.SetPRsMissingSeniorParams
+	)
+	hasSenior := func(ids []string) bool {
+		return slices.ContainsFunc(ids, func(id string) bool { return senior[id] })
+	}
+	for _, key := range prKeys {
+		pr := prs[key]
+		prID := key.PullRequestID
+		requireSenior := requiring[key]
+		for _, uid := range slices.Clone(reviewers[key]) {
+			if _, leaving := teamOf[uid]; !leaving {
+				continue
 			}
 
-			var validCandidates []string
-			for _, c := range candidates {
-				if deactivatedMap[c.UserID] {
+			var candidates []string
+			for _, c := range active[teamOf[uid]] {
+				if r, capped := room[c]; capped && r == 0 {
 					continue
 				}
-				if currentReviewerMap[c.UserID] {
-					continue
-				}
-				if c.UserID == uid {
-					continue
+				if c != pr.AuthorID && !slices.Contains(reviewers[key], c) && !rules[pr.AuthorID].Never(c) {
+					candidates = append(candidates, c)
 				}
-				validCandidates = append(validCandidates, c.UserID)
 			}
 
-			if len(validCandidates) > 0 {
-				idx := rand.Intn(len(validCandidates))
-				newReviewerID := validCandidates[idx]
-
-				_, err = qtx.ReplaceReviewer(ctx, repo.ReplaceReviewerParams{
-					PrID:       pr.PullRequestID,
-					ReviewerID: uid,
-					ReplacedBy: pgtype.Text{String: newReviewerID, Valid: true},
-				})
-				if err != nil {
-					return DeactivateUsersResponse{}, err
+			reassignment := Reassignment{PullRequestID: prID, Repository: pr.Repository.String, OldReviewerID: uid}
+			reviewers[key] = slices.DeleteFunc(reviewers[key], func(id string) bool { return id == uid })
+			if requireSenior && !hasSenior(reviewers[key]) && hasSenior(candidates) {
+				candidates = slices.DeleteFunc(candidates, func(id string) bool { return !senior[id] })
+			}
+			if slices.ContainsFunc(candidates, rules[pr.AuthorID].Prefers) {
+				candidates = slices.DeleteFunc(candidates, func(id string) bool { return !rules[pr.AuthorID].Prefers(id) })
+			}
+			if len(candidates) > 0 {
+				reassignment.NewReviewerID = candidates[rand.Intn(len(candidates))]
+				reviewers[key] = append(reviewers[key], reassignment.NewReviewerID)
+				if _, capped := room[reassignment.NewReviewerID]; capped {
+					room[reassignment.NewReviewerID]--
 				}
 
-				_, err = qtx.AssignReviewer(ctx, repo.AssignReviewerParams{
-					PrID:       pr.PullRequestID,
-					ReviewerID: newReviewerID,
-				})
-				if err != nil {
-					return DeactivateUsersResponse{}, err
-				}
+				replaced.PrRepositories = append(replaced.PrRepositories, key.Repository)
+				replaced.PrIds = append(replaced.PrIds, prID)
+				replaced.ReviewerIds = append(replaced.ReviewerIds, uid)
+				replaced.ReplacedBy = append(replaced.ReplacedBy, reassignment.NewReviewerID)
+				added.PrRepositories = append(added.PrRepositories, key.Repository)
+				added.PrIds = append(added.PrIds, prID)
+				added.ReviewerIds = append(added.ReviewerIds, reassignment.NewReviewerID)
+				reports[uid].Reassigned = append(reports[uid].Reassigned, prID)
 			} else {
-				err = qtx.DeleteReviewer(ctx, repo.DeleteReviewerParams{
-					PrID:       pr.PullRequestID,
-					ReviewerID: uid,
-				})
-				if err != nil {
-					return DeactivateUsersResponse{}, err
-				}
+				removed.PrRepositories = append(removed.PrRepositories, key.Repository)
+				removed.PrIds = append(removed.PrIds, prID)
+				removed.ReviewerIds = append(removed.ReviewerIds, uid)
+				reports[uid].LostReviewer = append(reports[uid].LostReviewer, prID)
 			}
-		}
-	}
-
-	var response DeactivateUsersResponse
-	for prID := range updatedPRsMap {
-		pr, err := qtx.GetPR(ctx, prID)
-		if err != nil {
-			return DeactivateUsersResponse{}, err
+			response.Reassignments = append(response.Reassignments, reassignment)
 		}
 
-		reviewers, err := qtx.GetPRReviewers(ctx, prID)
-		if err != nil {
-			return DeactivateUsersResponse{}, err
-		}
-
-		status := "UNKNOWN"
-		if pr.Status.Valid {
-			status = string(pr.Status.PrStatusEnum)
-		}
+		missingSenior := requireSenior && !hasSenior(reviewers[key])
+		missing.PrRepositories = append(missing.PrRepositories, key.Repository)
+		missing.PrIds = append(missing.PrIds, prID)
+		missing.MissingSenior = append(missing.MissingSenior, missingSenior)
 
 		response.UpdatedPRs = append(response.UpdatedPRs, domain.PRWithReviewers{
 			PullRequestID:     pr.PullRequestID,
 			PullRequestName:   pr.PullRequestName,
 			AuthorID:          pr.AuthorID,
-			Status:            status,
-			AssignedReviewers: reviewers,
+			Status:            string(repo.PrStatusEnumOPEN),
+			Repository:        pr.Repository.String,
+			AssignedReviewers: reviewers[key],
+			MissingSenior:     missingSenior,
 		})
 	}
 
-	if err := tx.Commit(ctx); err != nil {
-		return DeactivateUsersResponse{}, errors.InternalError
+	// 6. Apply them in batches
+	if len(replaced.PrIds) > 0 {
+		if err := qtx.ReplaceReviewers(ctx, replaced); err != nil {
+			return DeactivateUsersResponse{}, err
+		}
+		if err := qtx.AssignReviewers(ctx, added); err != nil {
+			return DeactivateUsersResponse{}, err
+		}
+	}
+	if len(removed.PrIds) > 0 {
+		if err := qtx.DeleteReviewers(ctx, removed); err != nil {
+			return DeactivateUsersResponse{}, err
+		}
+	}
+	if len(missing.PrIds) > 0 {
+		if err := qtx.SetPRsMissingSenior(ctx, missing); err != nil {
+			return DeactivateUsersResponse{}, err
+		}
 	}
 
 	return response, nil
 }
+
+// AbsenceWorker runs ReleaseAbsentReviews every interval.
+func AbsenceWorker(service Service, interval time.Duration) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				res, err := service.ReleaseAbsentReviews(ctx)
+				if err != nil {
+					slog.Error("failed to reassign reviews of absent users", "error", err)
+					continue
+				}
+				if len(res.Users) > 0 {
+					slog.Info("reassigned reviews of absent users",
+						"users", len(res.Users),
+						"reassignments", len(res.Reassignments),
+					)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package teams_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/pr"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/pgtest"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
+	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/teams"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// roundTrip is the simulated network latency of a statement,
+// the in-memory database alone would hide the cost of chatty code.
+const roundTrip = 200 * time.Microsecond
+
+// BenchmarkDeactivateUsers deactivates 10 of 50 team members who review
+// 200 open PRs between them. Dry runs roll back, so every iteration does
+// the same work. "loop" is the per-user implementation the batched one
+// replaced, kept as the baseline:
+//
+//	go test ./internal/teams -run '^$' -bench DeactivateUsers -count 10 > bench.txt
+//	benchstat -col /impl bench.txt
+//
+// On memdb every statement costs roundTrip; with TEST_DATABASE_URL set the
+// benchmark measures the real queries instead.
+func BenchmarkDeactivateUsers(b *testing.B) {
+	ctx := context.Background()
+	db := newSlowDB(b)
+	q := repo.New(db)
+
+	team := teams.CreateTeamParams{TeamName: "backend"}
+	for i := range 50 {
+		id := fmt.Sprintf("u%02d", i)
+		team.Members = append(team.Members, teams.MemberParams{UserID: id, Username: id, IsActive: true})
+	}
+	_, err := teams.NewService(q, db).CreateTeam(ctx, team)
+	require.NoError(b, err)
+
+	prs := pr.NewService(q, db, 2)
+	for i := range 200 {
+		_, err := prs.CreatePR(ctx, pr.CreatePRRequest{CreatePRParams: repo.CreatePRParams{
+			PullRequestID:   fmt.Sprintf("pr-%03d", i),
+			PullRequestName: "change",
+			AuthorID:        team.Members[i%len(team.Members)].UserID,
+		}})
+		require.NoError(b, err)
+	}
+
+	leaving := make([]string, 10)
+	for i := range leaving {
+		leaving[i] = team.Members[i].UserID
+	}
+
+	svc := teams.NewService(q, db)
+	impls := []struct {
+		name       string
+		deactivate func() (teams.DeactivateUsersResponse, error)
+	}{
+		{"impl=loop", func() (teams.DeactivateUsersResponse, error) { return deactivateUsersLoop(ctx, q, db, leaving) }},
+		{"impl=batched", func() (teams.DeactivateUsersResponse, error) { return svc.DeactivateUsers(ctx, leaving, true) }},
+	}
+	for _, impl := range impls {
+		b.Run(impl.name, func(b *testing.B) {
+			db.statements.Store(0)
+			b.ResetTimer()
+			for range b.N {
+				res, err := impl.deactivate()
+				require.NoError(b, err)
+				require.NotEmpty(b, res.Reassignments)
+			}
+			b.ReportMetric(float64(db.statements.Load())/float64(b.N), "statements/op")
+		})
+	}
+}
+
+// deactivateUsersLoop is DeactivateUsers as it was before the batched queries:
+// a handful of statements per user and per PR. It always rolls back.
+func deactivateUsersLoop(ctx context.Context, q *repo.Queries, db postgres.DB, userIDs []string) (teams.DeactivateUsersResponse, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return teams.DeactivateUsersResponse{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := q.WithTx(tx)
+
+	deactivatedMap := make(map[string]bool)
+	for _, uid := range userIDs {
+		deactivatedMap[uid] = true
+	}
+
+	updatedPRsMap := make(map[domain.PRKey]struct{})
+	response := teams.DeactivateUsersResponse{Reassignments: []teams.Reassignment{}, DryRun: true}
+
+	for _, uid := range userIDs {
+		user, err := qtx.GetUser(ctx, uid)
+		if err != nil {
+			return teams.DeactivateUsersResponse{}, err
+		}
+
+		_, err = qtx.SetUserActivity(ctx, repo.SetUserActivityParams{UserID: uid, IsActive: false})
+		if err != nil {
+			return teams.DeactivateUsersResponse{}, err
+		}
+
+		prs, err := qtx.GetPRsByReviewer(ctx, repo.GetPRsByReviewerParams{ReviewerID: uid})
+		if err != nil {
+			return teams.DeactivateUsersResponse{}, err
+		}
+
+		for _, pr := range prs {
+			updatedPRsMap[domain.PRKey{Repository: pr.RepositoryKey, PullRequestID: pr.PullRequestID}] = struct{}{}
+
+			candidates, err := qtx.GetActiveTeamMembersExcept(ctx, repo.GetActiveTeamMembersExceptParams{
+				TeamName: user.TeamName,
+				UserID:   pr.AuthorID,
+			})
+			if err != nil {
+				return teams.DeactivateUsersResponse{}, err
+			}
+
+			currentReviewers, err := qtx.GetPRReviewers(ctx, repo.GetPRReviewersParams{PrRepository: pr.RepositoryKey, PrID: pr.PullRequestID})
+			if err != nil {
+				return teams.DeactivateUsersResponse{}, err
+			}
+			currentReviewerMap := make(map[string]bool)
+			for _, r := range currentReviewers {
+				currentReviewerMap[r] = true
+			}
+
+			var validCandidates []string
+			for _, c := range candidates {
+				if deactivatedMap[c.UserID] || currentReviewerMap[c.UserID] || c.UserID == uid {
+					continue
+				}
+				validCandidates = append(validCandidates, c.UserID)
+			}
+
+			if len(validCandidates) > 0 {
+				newReviewerID := validCandidates[rand.Intn(len(validCandidates))]
+
+				_, err = qtx.ReplaceReviewer(ctx, repo.ReplaceReviewerParams{
+					PrRepository: pr.RepositoryKey,
+					PrID:         pr.PullRequestID,
+					ReviewerID:   uid,
+					ReplacedBy:   pgtype.Text{String: newReviewerID, Valid: true},
+				})
+				if err != nil {
+					return teams.DeactivateUsersResponse{}, err
+				}
+
+				_, err = qtx.AssignReviewer(ctx, repo.AssignReviewerParams{
+					PrRepository: pr.RepositoryKey,
+					PrID:         pr.PullRequestID,
+					ReviewerID:   newReviewerID,
+				})
+				if err != nil {
+					return teams.DeactivateUsersResponse{}, err
+				}
+
+				response.Reassignments = append(response.Reassignments, teams.Reassignment{
+					PullRequestID: pr.PullRequestID,
+					OldReviewerID: uid,
+					NewReviewerID: newReviewerID,
+				})
+			} else {
+				err = qtx.DeleteReviewer(ctx, repo.DeleteReviewerParams{
+					PrRepository: pr.RepositoryKey,
+					PrID:         pr.PullRequestID,
+					ReviewerID:   uid,
+				})
+				if err != nil {
+					return teams.DeactivateUsersResponse{}, err
+				}
+
+				response.Reassignments = append(response.Reassignments, teams.Reassignment{
+					PullRequestID: pr.PullRequestID,
+					OldReviewerID: uid,
+				})
+			}
+		}
+	}
+
+	for key := range updatedPRsMap {
+		pr, err := qtx.GetPR(ctx, repo.GetPRParams{RepositoryKey: key.Repository, PullRequestID: key.PullRequestID})
+		if err != nil {
+			return teams.DeactivateUsersResponse{}, err
+		}
+
+		reviewers, err := qtx.GetPRReviewers(ctx, repo.GetPRReviewersParams{PrRepository: key.Repository, PrID: key.PullRequestID})
+		if err != nil {
+			return teams.DeactivateUsersResponse{}, err
+		}
+
+		response.UpdatedPRs = append(response.UpdatedPRs, domain.PRWithReviewers{
+			PullRequestID:     pr.PullRequestID,
+			PullRequestName:   pr.PullRequestName,
+			AuthorID:          pr.AuthorID,
+			Status:            string(pr.Status.PrStatusEnum),
+			AssignedReviewers: reviewers,
+		})
+	}
+
+	return response, nil
+}
+
+// TestReleaseAbsentReviews hands over the review of a user whose absence has
+// begun once, absences that have not begun yet are left alone.
+func TestReleaseAbsentReviews(t *testing.T) {
+	ctx := context.Background()
+	db := pgtest.Open(t)
+	q := repo.New(db)
+	svc := teams.NewService(q, db)
+
+	_, err := svc.CreateTeam(ctx, teams.CreateTeamParams{TeamName: "backend", Members: []teams.MemberParams{
+		{UserID: "author", Username: "Author", IsActive: true},
+		{UserID: "r1", Username: "R1", IsActive: true},
+		{UserID: "r2", Username: "R2", IsActive: true},
+	}})
+	require.NoError(t, err)
+	created, err := pr.NewService(q, db, 1).CreatePR(ctx, pr.CreatePRRequest{CreatePRParams: repo.CreatePRParams{PullRequestID: "pr-1", PullRequestName: "change", AuthorID: "author"}})
+	require.NoError(t, err)
+	require.Len(t, created.PR.AssignedReviewers, 1)
+	away := created.PR.AssignedReviewers[0]
+	other := map[string]string{"r1": "r2", "r2": "r1"}[away]
+
+	now := time.Now()
+	_, err = q.CreateAbsence(ctx, repo.CreateAbsenceParams{
+		UserID:   other,
+		StartsAt: pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+		EndsAt:   pgtype.Timestamptz{Time: now.Add(2 * time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	_, err = q.CreateAbsence(ctx, repo.CreateAbsenceParams{
+		UserID:   away,
+		StartsAt: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+		EndsAt:   pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	res, err := svc.ReleaseAbsentReviews(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []teams.Reassignment{{PullRequestID: "pr-1", OldReviewerID: away, NewReviewerID: other}}, res.Reassignments)
+
+	reviewers, err := q.GetPRReviewers(ctx, repo.GetPRReviewersParams{PrID: "pr-1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{other}, reviewers)
+	user, err := q.GetUser(ctx, away)
+	require.NoError(t, err)
+	require.True(t, user.IsActive)
+
+	// every absence is handled once
+	res, err = svc.ReleaseAbsentReviews(ctx)
+	require.NoError(t, err)
+	require.Empty(t, res.Users)
+}
+
+// slowDB counts statements. On memdb it also delays each by roundTrip,
+// PostgreSQL has a real one.
+type slowDB struct {
+	pgtest.DB
+	delay      time.Duration
+	statements atomic.Int64
+}
+
+func newSlowDB(tb testing.TB) *slowDB {
+	db := &slowDB{DB: pgtest.Open(tb)}
+	if !pgtest.Real() {
+		db.delay = roundTrip
+	}
+
+	return db
+}
+
+func (db *slowDB) wait() {
+	db.statements.Add(1)
+	time.Sleep(db.delay)
+}
+
+func (db *slowDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	db.wait()
+	return db.DB.Exec(ctx, sql, args...)
+}
+
+func (db *slowDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	db.wait()
+	return db.DB.Query(ctx, sql, args...)
+}
+
+func (db *slowDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	db.wait()
+	return db.DB.QueryRow(ctx, sql, args...)
+}
+
+func (db *slowDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := db.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slowTx{Tx: tx, db: db}, nil
+}
+
+type slowTx struct {
+	pgx.Tx
+	db *slowDB
+}
+
+func (tx *slowTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tx.db.wait()
+	return tx.Tx.Exec(ctx, sql, args...)
+}
+
+func (tx *slowTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	tx.db.wait()
+	return tx.Tx.Query(ctx, sql, args...)
+}
+
+func (tx *slowTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	tx.db.wait()
+	return tx.Tx.QueryRow(ctx, sql, args...)
+}
+
+var _ postgres.DB = (*slowDB)(nil)
@@ -5,15 +5,37 @@ import (
 	"context"
 
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
-	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Limits of the request lists, the team sizes of the service are moderate (up to 200 users in total).
+const (
+	// MaxTeamMembers is the maximum number of members in a CreateTeam request.
+	MaxTeamMembers = 200
+	// MaxDeactivateUsers is the maximum number of users deactivated at once.
+	MaxDeactivateUsers = 200
+	// MaxFallbackTeams is the maximum number of fallback teams of a team.
+	MaxFallbackTeams = 10
 )
 
 // Service defines the interface for the teams service.
 type Service interface {
 	GetTeamByName(ctx context.Context, teamName string) ([]repo.User, error)
-	CreateTeam(ctx context.Context, tempTeam tempTeamParams) ([]repo.User, error)
-	DeactivateUsers(ctx context.Context, userIDs []string) (DeactivateUsersResponse, error)
+	CreateTeam(ctx context.Context, tempTeam CreateTeamParams) ([]repo.User, error)
+	// DeactivateUsers rolls everything back when dryRun is set and only reports the plan.
+	DeactivateUsers(ctx context.Context, userIDs []string, dryRun bool) (DeactivateUsersResponse, error)
+	UpsertMembers(ctx context.Context, params UpsertMembersParams) (UpsertMembersResponse, error)
+	// ReleaseAbsentReviews is run by AbsenceWorker, the response has no DryRun.
+	ReleaseAbsentReviews(ctx context.Context) (DeactivateUsersResponse, error)
+	GetFallbacks(ctx context.Context, teamName string) (FallbacksResponse, error)
+	// SetFallbacks replaces the fallback teams, an empty list removes them.
+	SetFallbacks(ctx context.Context, params FallbacksParams) (FallbacksResponse, error)
+	GetRules(ctx context.Context, teamName string) (RulesResponse, error)
+	SetRequireSenior(ctx context.Context, params repo.SetTeamRequireSeniorParams) (RulesResponse, error)
+	// SetPairRule adds the pair rule or changes its kind.
+	SetPairRule(ctx context.Context, params repo.SetTeamPairRuleParams) (RulesResponse, error)
+	DeletePairRule(ctx context.Context, params repo.DeleteTeamPairRuleParams) (RulesResponse, error)
 }
 
 // Handler handles HTTP requests for the teams service.
@@ -30,36 +52,61 @@ func NewHandler(service Service) *Handler {
 
 type svc struct {
 	repo *repo.Queries
-	db   *pgxpool.Pool
+	db   postgres.DB
 }
 
 // NewService creates a new teams service.
-func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
+func NewService(repo *repo.Queries, db postgres.DB) Service {
 	return &svc{
 		repo: repo,
 		db:   db,
 	}
 }
 
-type tempUserParams struct {
+// MemberParams is a member of a created team or of a members upsert.
+type MemberParams struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
 }
 
-type tempTeamParams struct {
-	TeamName string           `json:"team_name"`
-	Members  []tempUserParams `json:"members"`
+// CreateTeamParams represents the request body for creating a team with its members.
+type CreateTeamParams struct {
+	TeamName string         `json:"team_name"`
+	Members  []MemberParams `json:"members"`
 }
 
 // DeactivateUsersRequest represents the request body for mass user deactivation.
 type DeactivateUsersRequest struct {
-	Users []string `json:"users"`
+	Users  []string `json:"users"`
+	DryRun bool     `json:"dry_run"`
 }
 
 // DeactivateUsersResponse represents the response for mass user deactivation.
 type DeactivateUsersResponse struct {
-	UpdatedPRs []domain.PRWithReviewers `json:"updated_prs"`
+	UpdatedPRs    []domain.PRWithReviewers `json:"updated_prs"`
+	Reassignments []Reassignment           `json:"reassignments"`
+	Users         []*UserReport            `json:"users"`
+	DryRun        bool                     `json:"dry_run"`
+}
+
+// UserReport lists what happened to the reviews of a deactivated user:
+// the open PRs another reviewer took over, the open PRs left with one
+// reviewer less and the merged PRs that were not touched.
+type UserReport struct {
+	UserID       string   `json:"user_id"`
+	Reassigned   []string `json:"reassigned"`
+	LostReviewer []string `json:"lost_reviewer"`
+	Skipped      []string `json:"skipped"`
+}
+
+// Reassignment is a review taken from a deactivated user,
+// NewReviewerID is empty when nobody could take it over.
+type Reassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	Repository    string `json:"repository,omitempty"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
 }
 
 // CreateTeamResponse represents the response for creating a team.
@@ -79,3 +126,83 @@ type TeamResponse struct {
 	TeamName string       `json:"team_name"`
 	Members  []TeamMember `json:"members"`
 }
+
+// UpsertMembersParams represents the request body for changing the members of an existing team.
+type UpsertMembersParams struct {
+	TeamName string         `json:"team_name"`
+	Members  []MemberParams `json:"members"`
+	Remove   []string       `json:"remove"`
+}
+
+// Results of an upserted or removed member.
+const (
+	MemberAdded     = "added"
+	MemberMoved     = "moved"
+	MemberUpdated   = "updated"
+	MemberUnchanged = "unchanged"
+	MemberRemoved   = "removed"
+	MemberNotFound  = "not_found"
+	MemberInUse     = "in_use"
+)
+
+// MemberResult is the outcome for one member, FromTeam is set for moved ones.
+type MemberResult struct {
+	UserID   string `json:"user_id"`
+	Result   string `json:"result"`
+	FromTeam string `json:"from_team,omitempty"`
+}
+
+// UpsertMembersResponse represents the team after the change and the outcome per member.
+type UpsertMembersResponse struct {
+	Team    TeamResponse   `json:"team"`
+	Results []MemberResult `json:"results"`
+}
+
+// FallbacksParams represents the request body for setting the fallback teams of a team.
+type FallbacksParams struct {
+	TeamName      string   `json:"team_name"`
+	FallbackTeams []string `json:"fallback_teams"`
+}
+
+// FallbacksResponse lists the teams new PRs of the team draw missing reviewers from, in order.
+type FallbacksResponse struct {
+	TeamName      string   `json:"team_name"`
+	FallbackTeams []string `json:"fallback_teams"`
+}
+
+// SetRequireSeniorRequest represents the request body for switching the required senior rule of a team.
+type SetRequireSeniorRequest struct {
+	TeamName      string `json:"team_name"`
+	RequireSenior *bool  `json:"require_senior"`
+}
+
+// PairRuleRequest represents the request body for adding or changing a pair rule of a team.
+type PairRuleRequest struct {
+	TeamName   string `json:"team_name"`
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Kind       string `json:"kind"`
+}
+
+// DeletePairRuleRequest represents the request body for removing a pair rule of a team.
+type DeletePairRuleRequest struct {
+	TeamName   string `json:"team_name"`
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+}
+
+// PairRule is a rule for the PRs of a team member: the reviewer is never
+// assigned to them (domain.PairNever) or preferred for them (domain.PairPrefer).
+type PairRule struct {
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Kind       string `json:"kind"`
+}
+
+// RulesResponse lists the reviewer rules of a team. With RequireSenior every PR
+// of the team's authors gets a senior or maintainer reviewer when one is available.
+type RulesResponse struct {
+	TeamName      string     `json:"team_name"`
+	RequireSenior bool       `json:"require_senior"`
+	PairRules     []PairRule `json:"pair_rules"`
+}
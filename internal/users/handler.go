@@ -2,23 +2,26 @@
 package users
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
 	"github.com/Joskmo/avito-trainee-assignment-api/internal/json"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // SetUserActivity handles the request to set the activity status of a user.
 func (h *Handler) SetUserActivity(w http.ResponseWriter, r *http.Request) {
 	var req SetUserActivityRequest
 	if err := json.Read(r, &req); err != nil {
-		errors.WriteAppError(w, "invalid json in SetUserActivity", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "invalid json in SetUserActivity", errors.InvalidJSON(err))
 		return
 	}
 
 	if req.IsActive == nil {
-		errors.WriteAppError(w, "is_active field is required", errors.ErrInvalidInput)
+		errors.WriteAppError(w, r, "is_active field is required", errors.InvalidField("is_active", "is required"))
 		return
 	}
 
@@ -28,9 +31,148 @@ func (h *Handler) SetUserActivity(w http.ResponseWriter, r *http.Request) {
 	}
 	user, err := h.service.SetUserActivity(r.Context(), params)
 	if err != nil {
-		errors.WriteAppError(w, "failed to set user activity", err)
+		errors.WriteAppError(w, r, "failed to set user activity", err)
 		return
 	}
 	response := SetUserActivityResponse{User: user}
 	json.Write(w, http.StatusOK, response)
 }
+
+// BulkSetUserActivity handles the request to set the activity status of many users.
+func (h *Handler) BulkSetUserActivity(w http.ResponseWriter, r *http.Request) {
+	var req BulkSetUserActivityRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in BulkSetUserActivity", errors.InvalidJSON(err))
+		return
+	}
+
+	items := make([]repo.SetUserActivityParams, len(req.Users))
+	for i, u := range req.Users {
+		if u.IsActive == nil {
+			errors.WriteAppError(w, r, "is_active field is required", errors.InvalidField(fmt.Sprintf("users[%d].is_active", i), "is required"))
+			return
+		}
+		items[i] = repo.SetUserActivityParams{UserID: u.UserID, IsActive: *u.IsActive}
+	}
+
+	response, err := h.service.BulkSetUserActivity(r.Context(), items)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to set activity of users", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// AddAbsence handles the request to schedule an absence of a user.
+func (h *Handler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	var req AddAbsenceRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in AddAbsence", errors.InvalidJSON(err))
+		return
+	}
+
+	startsAt, err := parseTime("starts_at", req.StartsAt)
+	if err != nil {
+		errors.WriteAppError(w, r, "invalid starts_at", err)
+		return
+	}
+	endsAt, err := parseTime("ends_at", req.EndsAt)
+	if err != nil {
+		errors.WriteAppError(w, r, "invalid ends_at", err)
+		return
+	}
+
+	absence, err := h.service.AddAbsence(r.Context(), repo.CreateAbsenceParams{
+		UserID:   req.UserID,
+		StartsAt: startsAt,
+		EndsAt:   endsAt,
+	})
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to add absence", err)
+		return
+	}
+
+	json.Write(w, http.StatusCreated, AbsenceResponse{Absence: absence})
+}
+
+// ListAbsences handles the request to list the absences of a user.
+func (h *Handler) ListAbsences(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListAbsences(r.Context(), r.URL.Query().Get("user_id"))
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to list absences", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// DeleteAbsence handles the request to cancel an absence.
+func (h *Handler) DeleteAbsence(w http.ResponseWriter, r *http.Request) {
+	var req DeleteAbsenceRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in DeleteAbsence", errors.InvalidJSON(err))
+		return
+	}
+
+	absence, err := h.service.DeleteAbsence(r.Context(), req.AbsenceID)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to delete absence", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, AbsenceResponse{Absence: absence})
+}
+
+// parseTime parses an RFC 3339 date-time of the request body, an empty value is left unset.
+func parseTime(field, value string) (pgtype.Timestamptz, error) {
+	if value == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return pgtype.Timestamptz{}, errors.InvalidField(field, "must be an RFC 3339 date-time")
+	}
+
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
+
+// SetMaxOpenReviews handles the request to limit the open reviews of a user.
+func (h *Handler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var req SetMaxOpenReviewsRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in SetMaxOpenReviews", errors.InvalidJSON(err))
+		return
+	}
+
+	params := repo.SetMaxOpenReviewsParams{UserID: req.UserID}
+	if req.MaxOpenReviews != nil {
+		params.MaxOpenReviews = pgtype.Int4{Int32: *req.MaxOpenReviews, Valid: true}
+	}
+
+	response, err := h.service.SetMaxOpenReviews(r.Context(), params)
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to set max open reviews", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
+
+// SetLevel handles the request to set the level of a user.
+func (h *Handler) SetLevel(w http.ResponseWriter, r *http.Request) {
+	var req SetLevelRequest
+	if err := json.Read(r, &req); err != nil {
+		errors.WriteAppError(w, r, "invalid json in SetLevel", errors.InvalidJSON(err))
+		return
+	}
+
+	response, err := h.service.SetLevel(r.Context(), repo.SetUserLevelParams{UserID: req.UserID, Level: req.Level})
+	if err != nil {
+		errors.WriteAppError(w, r, "failed to set user level", err)
+		return
+	}
+
+	json.Write(w, http.StatusOK, response)
+}
@@ -3,7 +3,11 @@ package users
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"time"
 
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/domain"
 	apperrors "github.com/Joskmo/avito-trainee-assignment-api/internal/errors"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
 	"github.com/jackc/pgx/v5"
@@ -12,7 +16,7 @@ import (
 func (s *svc) SetUserActivity(ctx context.Context, userActivityParams repo.SetUserActivityParams) (repo.User, error) {
 	// validation
 	if userActivityParams.UserID == "" {
-		return repo.User{}, apperrors.ErrInvalidInput
+		return repo.User{}, apperrors.InvalidField("user_id", "must not be empty")
 	}
 
 	user, err := s.repo.SetUserActivity(ctx, userActivityParams)
@@ -25,3 +29,230 @@ func (s *svc) SetUserActivity(ctx context.Context, userActivityParams repo.SetUs
 
 	return user, nil
 }
+
+// BulkSetUserActivity sets the activity flags in one transaction. Unknown users
+// are reported and skipped, they do not fail the request.
+func (s *svc) BulkSetUserActivity(ctx context.Context, items []repo.SetUserActivityParams) (BulkSetUserActivityResponse, error) {
+	// validation
+	var details []apperrors.FieldError
+	if len(items) == 0 {
+		details = append(details, apperrors.FieldError{Field: "users", Reason: "must contain at least one user"})
+	}
+	if len(items) > MaxBulkUsers {
+		details = append(details, apperrors.FieldError{Field: "users", Reason: fmt.Sprintf("must contain at most %d users", MaxBulkUsers)})
+	}
+	ids := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		switch {
+		case item.UserID == "":
+			details = append(details, apperrors.FieldError{Field: fmt.Sprintf("users[%d].user_id", i), Reason: "must not be empty"})
+		case seen[item.UserID]:
+			details = append(details, apperrors.FieldError{Field: fmt.Sprintf("users[%d].user_id", i), Reason: "is duplicated"})
+		}
+		seen[item.UserID] = true
+		ids = append(ids, item.UserID)
+	}
+	if len(details) > 0 {
+		return BulkSetUserActivityResponse{}, apperrors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return BulkSetUserActivityResponse{}, apperrors.InternalError
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.repo.WithTx(tx)
+
+	users, err := qtx.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return BulkSetUserActivityResponse{}, err
+	}
+	current := make(map[string]repo.User, len(users))
+	for _, u := range users {
+		current[u.UserID] = u
+	}
+
+	// only the flags that change are written
+	var changes repo.SetUsersActivityParams
+	for _, item := range items {
+		if u, ok := current[item.UserID]; ok && u.IsActive != item.IsActive {
+			changes.UserIds = append(changes.UserIds, item.UserID)
+			changes.IsActive = append(changes.IsActive, item.IsActive)
+		}
+	}
+	if len(changes.UserIds) > 0 {
+		updated, err := qtx.SetUsersActivity(ctx, changes)
+		if err != nil {
+			return BulkSetUserActivityResponse{}, err
+		}
+		for _, u := range updated {
+			current[u.UserID] = u
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BulkSetUserActivityResponse{}, apperrors.InternalError
+	}
+
+	response := BulkSetUserActivityResponse{Results: make([]BulkResult, len(items))}
+	for i, item := range items {
+		u, ok := current[item.UserID]
+		switch {
+		case !ok:
+			response.Results[i] = BulkResult{UserID: item.UserID, Result: ResultNotFound}
+		case slices.Contains(changes.UserIds, item.UserID):
+			response.Results[i] = BulkResult{UserID: item.UserID, Result: ResultUpdated, User: &u}
+		default:
+			response.Results[i] = BulkResult{UserID: item.UserID, Result: ResultUnchanged, User: &u}
+		}
+	}
+
+	return response, nil
+}
+
+// AddAbsence schedules an absence, during it the user gets no new reviews.
+func (s *svc) AddAbsence(ctx context.Context, params repo.CreateAbsenceParams) (repo.UserAbsence, error) {
+	// validation
+	var details []apperrors.FieldError
+	if params.UserID == "" {
+		details = append(details, apperrors.FieldError{Field: "user_id", Reason: "must not be empty"})
+	}
+	if !params.StartsAt.Valid {
+		details = append(details, apperrors.FieldError{Field: "starts_at", Reason: "is required"})
+	}
+	switch {
+	case !params.EndsAt.Valid:
+		details = append(details, apperrors.FieldError{Field: "ends_at", Reason: "is required"})
+	case params.StartsAt.Valid && !params.EndsAt.Time.After(params.StartsAt.Time):
+		details = append(details, apperrors.FieldError{Field: "ends_at", Reason: "must be after starts_at"})
+	case !params.EndsAt.Time.After(time.Now()):
+		details = append(details, apperrors.FieldError{Field: "ends_at", Reason: "must be in the future"})
+	}
+	if len(details) > 0 {
+		return repo.UserAbsence{}, apperrors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	if _, err := s.repo.GetUser(ctx, params.UserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.UserAbsence{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "user_id", Reason: "user not found"})
+		}
+		return repo.UserAbsence{}, err
+	}
+
+	return s.repo.CreateAbsence(ctx, params)
+}
+
+// ListAbsences returns the absences of the user, past ones included.
+func (s *svc) ListAbsences(ctx context.Context, userID string) (ListAbsencesResponse, error) {
+	if userID == "" {
+		return ListAbsencesResponse{}, apperrors.InvalidField("user_id", "must not be empty")
+	}
+
+	if _, err := s.repo.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ListAbsencesResponse{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "user_id", Reason: "user not found"})
+		}
+		return ListAbsencesResponse{}, err
+	}
+
+	absences, err := s.repo.GetUserAbsences(ctx, userID)
+	if err != nil {
+		return ListAbsencesResponse{}, err
+	}
+	if absences == nil {
+		absences = []repo.UserAbsence{}
+	}
+
+	return ListAbsencesResponse{UserID: userID, Absences: absences}, nil
+}
+
+// DeleteAbsence cancels an absence, reviews reassigned when it began stay with their new reviewers.
+func (s *svc) DeleteAbsence(ctx context.Context, absenceID string) (repo.UserAbsence, error) {
+	if absenceID == "" {
+		return repo.UserAbsence{}, apperrors.InvalidField("absence_id", "must not be empty")
+	}
+
+	absence, err := s.repo.DeleteAbsence(ctx, absenceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return repo.UserAbsence{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "absence_id", Reason: "absence not found"})
+		}
+		return repo.UserAbsence{}, err
+	}
+
+	return absence, nil
+}
+
+// SetMaxOpenReviews limits how many reviews of open PRs the user may have, a user
+// at the limit is not picked as a reviewer. Reviews above a lowered limit are kept.
+func (s *svc) SetMaxOpenReviews(ctx context.Context, params repo.SetMaxOpenReviewsParams) (ReviewLoad, error) {
+	// validation
+	var details []apperrors.FieldError
+	if params.UserID == "" {
+		details = append(details, apperrors.FieldError{Field: "user_id", Reason: "must not be empty"})
+	}
+	if params.MaxOpenReviews.Valid && params.MaxOpenReviews.Int32 < 0 {
+		details = append(details, apperrors.FieldError{Field: "max_open_reviews", Reason: "must not be negative"})
+	}
+	if len(details) > 0 {
+		return ReviewLoad{}, apperrors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	if _, err := s.repo.GetUser(ctx, params.UserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ReviewLoad{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "user_id", Reason: "user not found"})
+		}
+		return ReviewLoad{}, err
+	}
+
+	if _, err := s.repo.SetMaxOpenReviews(ctx, params); err != nil {
+		return ReviewLoad{}, err
+	}
+
+	load, err := s.repo.GetReviewLoad(ctx, []string{params.UserID})
+	if err != nil {
+		return ReviewLoad{}, err
+	}
+	if len(load) == 0 {
+		return ReviewLoad{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "user_id", Reason: "user not found"})
+	}
+
+	response := ReviewLoad{UserID: params.UserID, OpenReviews: load[0].OpenReviews}
+	if load[0].MaxOpenReviews.Valid {
+		response.MaxOpenReviews = &load[0].MaxOpenReviews.Int32
+	}
+
+	return response, nil
+}
+
+// SetLevel sets the level of the user, teams requiring a senior reviewer
+// count senior and maintainer users as one.
+func (s *svc) SetLevel(ctx context.Context, params repo.SetUserLevelParams) (LevelResponse, error) {
+	// validation
+	var details []apperrors.FieldError
+	if params.UserID == "" {
+		details = append(details, apperrors.FieldError{Field: "user_id", Reason: "must not be empty"})
+	}
+	if !slices.Contains([]string{domain.LevelRegular, domain.LevelSenior, domain.LevelMaintainer}, params.Level) {
+		details = append(details, apperrors.FieldError{Field: "level", Reason: "must be regular, senior or maintainer"})
+	}
+	if len(details) > 0 {
+		return LevelResponse{}, apperrors.ErrInvalidInput.WithDetails(details...)
+	}
+
+	if _, err := s.repo.GetUser(ctx, params.UserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return LevelResponse{}, apperrors.ErrNotFound.WithDetails(apperrors.FieldError{Field: "user_id", Reason: "user not found"})
+		}
+		return LevelResponse{}, err
+	}
+
+	setting, err := s.repo.SetUserLevel(ctx, params)
+	if err != nil {
+		return LevelResponse{}, err
+	}
+
+	return LevelResponse{UserID: setting.UserID, Level: setting.Level}, nil
+}
@@ -4,13 +4,22 @@ package users
 import (
 	"context"
 
+	"github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres"
 	repo "github.com/Joskmo/avito-trainee-assignment-api/internal/storage/postgres/sqlc"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// MaxBulkUsers is the maximum number of users in a bulk request.
+const MaxBulkUsers = 200
+
 // Service defines the interface for the users service.
 type Service interface {
 	SetUserActivity(ctx context.Context, userActivityParams repo.SetUserActivityParams) (repo.User, error)
+	BulkSetUserActivity(ctx context.Context, items []repo.SetUserActivityParams) (BulkSetUserActivityResponse, error)
+	AddAbsence(ctx context.Context, params repo.CreateAbsenceParams) (repo.UserAbsence, error)
+	ListAbsences(ctx context.Context, userID string) (ListAbsencesResponse, error)
+	DeleteAbsence(ctx context.Context, absenceID string) (repo.UserAbsence, error)
+	SetMaxOpenReviews(ctx context.Context, params repo.SetMaxOpenReviewsParams) (ReviewLoad, error)
+	SetLevel(ctx context.Context, params repo.SetUserLevelParams) (LevelResponse, error)
 }
 
 // Handler handles HTTP requests for the users service.
@@ -27,11 +36,11 @@ func NewHandler(service Service) *Handler {
 
 type svc struct {
 	repo *repo.Queries
-	db   *pgxpool.Pool
+	db   postgres.DB
 }
 
 // NewService creates a new users service.
-func NewService(repo *repo.Queries, db *pgxpool.Pool) Service {
+func NewService(repo *repo.Queries, db postgres.DB) Service {
 	return &svc{
 		repo: repo,
 		db:   db,
@@ -48,3 +57,77 @@ type SetUserActivityRequest struct {
 type SetUserActivityResponse struct {
 	User repo.User `json:"user"`
 }
+
+// BulkSetUserActivityRequest represents the request for setting the activity of many users.
+type BulkSetUserActivityRequest struct {
+	Users []SetUserActivityRequest `json:"users"`
+}
+
+// Results of an item of a bulk request.
+const (
+	ResultUpdated   = "updated"
+	ResultUnchanged = "unchanged"
+	ResultNotFound  = "not_found"
+)
+
+// BulkResult is the outcome of one item, User is the user after the change.
+type BulkResult struct {
+	UserID string     `json:"user_id"`
+	Result string     `json:"result"`
+	User   *repo.User `json:"user,omitempty"`
+}
+
+// BulkSetUserActivityResponse represents the response for setting the activity of many users.
+type BulkSetUserActivityResponse struct {
+	Results []BulkResult `json:"results"`
+}
+
+// AddAbsenceRequest represents the request for scheduling an absence.
+// The dates are RFC 3339 date-times, the absence ends right before EndsAt.
+type AddAbsenceRequest struct {
+	UserID   string `json:"user_id"`
+	StartsAt string `json:"starts_at"`
+	EndsAt   string `json:"ends_at"`
+}
+
+// DeleteAbsenceRequest represents the request for cancelling an absence.
+type DeleteAbsenceRequest struct {
+	AbsenceID string `json:"absence_id"`
+}
+
+// AbsenceResponse represents the response with a single absence.
+type AbsenceResponse struct {
+	Absence repo.UserAbsence `json:"absence"`
+}
+
+// ListAbsencesResponse represents the response for listing the absences of a user.
+type ListAbsencesResponse struct {
+	UserID   string             `json:"user_id"`
+	Absences []repo.UserAbsence `json:"absences"`
+}
+
+// SetMaxOpenReviewsRequest represents the request for limiting the open reviews of a user,
+// a null or missing limit removes it.
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int32 `json:"max_open_reviews"`
+}
+
+// ReviewLoad is how many open reviews a user has and accepts, MaxOpenReviews is nil without a limit.
+type ReviewLoad struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int32 `json:"max_open_reviews"`
+	OpenReviews    int64  `json:"open_reviews"`
+}
+
+// SetLevelRequest represents the request for setting the level of a user.
+type SetLevelRequest struct {
+	UserID string `json:"user_id"`
+	Level  string `json:"level"`
+}
+
+// LevelResponse represents the response for setting the level of a user.
+type LevelResponse struct {
+	UserID string `json:"user_id"`
+	Level  string `json:"level"`
+}
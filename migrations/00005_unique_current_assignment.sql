@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A replaced reviewer may be assigned to the same PR again later,
+-- only the current assignments have to be unique.
+ALTER TABLE pr_reviewer_assignment DROP CONSTRAINT IF EXISTS pr_reviewer_assignment_pr_id_reviewer_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS pr_reviewer_assignment_current_key
+    ON pr_reviewer_assignment (pr_id, reviewer_id)
+    WHERE replaced_by IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- The old constraint allows one row per reviewer and PR: of a reviewer assigned
+-- to the same PR again only the current (or else the latest) assignment is kept,
+-- the older history rows are deleted.
+DELETE FROM pr_reviewer_assignment
+WHERE assignment_id IN (
+    SELECT assignment_id
+    FROM (
+        SELECT assignment_id,
+               row_number() OVER (
+                   PARTITION BY pr_id, reviewer_id
+                   ORDER BY replaced_by IS NULL DESC, assigned_at DESC, assignment_id DESC
+               ) AS n
+        FROM pr_reviewer_assignment
+    ) ranked
+    WHERE n > 1
+);
+DROP INDEX IF EXISTS pr_reviewer_assignment_current_key;
+ALTER TABLE pr_reviewer_assignment ADD CONSTRAINT pr_reviewer_assignment_pr_id_reviewer_id_key UNIQUE (pr_id, reviewer_id);
+-- +goose StatementEnd
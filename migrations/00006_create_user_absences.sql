@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE IF NOT EXISTS absence_seq;
+CREATE TABLE IF NOT EXISTS user_absences (
+    absence_id TEXT PRIMARY KEY DEFAULT ('abs' || nextval('absence_seq')),
+    user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    -- set once the open reviews of the user were handed over when the absence began
+    reviews_released BOOLEAN NOT NULL DEFAULT FALSE,
+    CHECK (ends_at > starts_at)
+);
+CREATE INDEX IF NOT EXISTS user_absences_user_id_idx ON user_absences (user_id, ends_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_absences;
+DROP SEQUENCE IF EXISTS absence_seq;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name TEXT NOT NULL,
+    -- teams are tried in ascending position
+    position INTEGER NOT NULL,
+    fallback_team TEXT NOT NULL CHECK (fallback_team <> team_name),
+    PRIMARY KEY (team_name, fallback_team),
+    UNIQUE (team_name, position)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_fallbacks;
+-- +goose StatementEnd
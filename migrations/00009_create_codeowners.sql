@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS codeowners (
+    repository TEXT PRIMARY KEY,
+    -- the uploaded CODEOWNERS file, parsed when reviewers are picked
+    content TEXT NOT NULL,
+    uploaded_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS codeowners;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS repositories (
+    repository_name TEXT PRIMARY KEY,
+    -- teams only exist through their members, so the owning team is not a foreign key
+    team_name TEXT NOT NULL
+);
+-- PRs created before repositories existed have none
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository TEXT REFERENCES repositories(repository_name);
+CREATE INDEX IF NOT EXISTS pull_requests_repository_idx ON pull_requests (repository);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS pull_requests_repository_idx;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository;
+DROP TABLE IF EXISTS repositories;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- senior and maintainer reviewers satisfy the required senior rule
+ALTER TABLE reviewer_settings ADD COLUMN IF NOT EXISTS level TEXT NOT NULL DEFAULT 'regular'
+    CHECK (level IN ('regular', 'senior', 'maintainer'));
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_name TEXT PRIMARY KEY,
+    -- every PR of the team's authors needs a senior or maintainer reviewer when possible
+    require_senior BOOLEAN NOT NULL DEFAULT FALSE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_settings;
+ALTER TABLE reviewer_settings DROP COLUMN IF EXISTS level;
+-- +goose StatementEnd
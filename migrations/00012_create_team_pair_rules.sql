@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rules of a team for the PRs of its authors: never assign the reviewer to PRs
+-- by the author, or prefer the reviewer for them
+CREATE TABLE IF NOT EXISTS team_pair_rules (
+    team_name TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    author_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('never', 'prefer')),
+    PRIMARY KEY (team_name, reviewer_id, author_id),
+    CHECK (reviewer_id <> author_id)
+);
+CREATE INDEX IF NOT EXISTS team_pair_rules_author_idx ON team_pair_rules (author_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_pair_rules;
+-- +goose StatementEnd
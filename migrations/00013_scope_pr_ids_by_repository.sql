@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- PR ids are unique within their repository, the PRs without a repository
+-- share the '' key. repository stays nullable to keep its foreign key.
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS repository_key TEXT NOT NULL
+    GENERATED ALWAYS AS (COALESCE(repository, '')) STORED;
+ALTER TABLE pr_reviewer_assignment ADD COLUMN IF NOT EXISTS pr_repository TEXT NOT NULL DEFAULT '';
+UPDATE pr_reviewer_assignment pra
+SET pr_repository = pr.repository_key
+FROM pull_requests pr
+WHERE pr.pull_request_id = pra.pr_id;
+
+ALTER TABLE pr_reviewer_assignment DROP CONSTRAINT IF EXISTS pr_reviewer_assignment_pr_id_fkey;
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_pkey;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_pkey PRIMARY KEY (repository_key, pull_request_id);
+ALTER TABLE pr_reviewer_assignment ADD CONSTRAINT pr_reviewer_assignment_pr_fkey
+    FOREIGN KEY (pr_repository, pr_id) REFERENCES pull_requests (repository_key, pull_request_id);
+
+DROP INDEX IF EXISTS pr_reviewer_assignment_current_key;
+CREATE UNIQUE INDEX IF NOT EXISTS pr_reviewer_assignment_current_key
+    ON pr_reviewer_assignment (pr_repository, pr_id, reviewer_id)
+    WHERE replaced_by IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- fails while the same PR id exists in two repositories
+DROP INDEX IF EXISTS pr_reviewer_assignment_current_key;
+ALTER TABLE pr_reviewer_assignment DROP CONSTRAINT IF EXISTS pr_reviewer_assignment_pr_fkey;
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_pkey;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_pkey PRIMARY KEY (pull_request_id);
+ALTER TABLE pr_reviewer_assignment ADD CONSTRAINT pr_reviewer_assignment_pr_id_fkey
+    FOREIGN KEY (pr_id) REFERENCES pull_requests (pull_request_id);
+CREATE UNIQUE INDEX IF NOT EXISTS pr_reviewer_assignment_current_key
+    ON pr_reviewer_assignment (pr_id, reviewer_id)
+    WHERE replaced_by IS NULL;
+ALTER TABLE pr_reviewer_assignment DROP COLUMN IF EXISTS pr_repository;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS repository_key;
+-- +goose StatementEnd
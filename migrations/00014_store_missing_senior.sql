@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- set when the team of the author required a senior reviewer and none of the
+-- reviewers assigned by the service is one
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS missing_senior BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE pull_requests pr
+SET missing_senior = TRUE
+FROM users u
+JOIN team_settings t ON t.team_name = u.team_name
+WHERE u.user_id = pr.author_id
+  AND t.require_senior
+  AND NOT EXISTS (
+    SELECT 1 FROM pr_reviewer_assignment pra
+    JOIN reviewer_settings s ON s.user_id = pra.reviewer_id
+    WHERE pra.pr_repository = pr.repository_key AND pra.pr_id = pr.pull_request_id
+      AND pra.replaced_by IS NULL AND s.level IN ('senior', 'maintainer')
+  );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS missing_senior;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations so the binary can apply them itself.
+package migrations
+
+import "embed"
+
+// FS contains all goose SQL migrations of the service.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,469 @@
+package client
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// Ping checks that the service is up.
+func (c *Client) Ping(ctx context.Context) error {
+	var body []byte
+	return c.do(ctx, http.MethodGet, "/ping", nil, nil, &body)
+}
+
+// GetTeam returns a team with its members.
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*Team, error) {
+	var team Team
+	if err := c.do(ctx, http.MethodGet, "/team/get", url.Values{"team_name": {teamName}}, nil, &team); err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// CreateTeam creates a team, creating or moving its members.
+func (c *Client) CreateTeam(ctx context.Context, team Team) (*Team, error) {
+	var resp struct {
+		Team Team `json:"team"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/team/add", nil, team, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Team, nil
+}
+
+// UpsertTeamMembers adds, updates and removes members of an existing team
+// in one transaction. Users of other teams are moved to it.
+func (c *Client) UpsertTeamMembers(ctx context.Context, req UpsertMembersRequest) (*UpsertMembersResult, error) {
+	var resp UpsertMembersResult
+	if err := c.do(ctx, http.MethodPost, "/team/members/upsert", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetTeamFallbacks returns the fallback teams of the team in the order they are tried.
+func (c *Client) GetTeamFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	var resp struct {
+		FallbackTeams []string `json:"fallback_teams"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/team/fallbacks", url.Values{"team_name": {teamName}}, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.FallbackTeams, nil
+}
+
+// SetTeamFallbacks replaces the fallback teams new PRs of the team draw missing
+// reviewers from, no teams removes them.
+func (c *Client) SetTeamFallbacks(ctx context.Context, teamName string, fallbackTeams ...string) ([]string, error) {
+	req := struct {
+		TeamName      string   `json:"team_name"`
+		FallbackTeams []string `json:"fallback_teams"`
+	}{TeamName: teamName, FallbackTeams: append([]string{}, fallbackTeams...)}
+	var resp struct {
+		FallbackTeams []string `json:"fallback_teams"`
+	}
+	if err := c.doIdempotent(ctx, http.MethodPost, "/team/setFallbacks", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.FallbackTeams, nil
+}
+
+// GetTeamRules returns the reviewer rules of the team.
+func (c *Client) GetTeamRules(ctx context.Context, teamName string) (*TeamRules, error) {
+	var resp TeamRules
+	if err := c.do(ctx, http.MethodGet, "/team/rules", url.Values{"team_name": {teamName}}, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SetTeamRequireSenior switches whether PRs of the team's authors need a senior or maintainer reviewer.
+func (c *Client) SetTeamRequireSenior(ctx context.Context, teamName string, requireSenior bool) (*TeamRules, error) {
+	req := struct {
+		TeamName      string `json:"team_name"`
+		RequireSenior bool   `json:"require_senior"`
+	}{TeamName: teamName, RequireSenior: requireSenior}
+	var resp TeamRules
+	if err := c.doIdempotent(ctx, http.MethodPost, "/team/rules/setRequireSenior", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SetTeamPairRule adds or changes the rule for reviewerID on PRs of authorID: PairNever or PairPrefer.
+func (c *Client) SetTeamPairRule(ctx context.Context, teamName, reviewerID, authorID, kind string) (*TeamRules, error) {
+	req := struct {
+		TeamName   string `json:"team_name"`
+		ReviewerID string `json:"reviewer_id"`
+		AuthorID   string `json:"author_id"`
+		Kind       string `json:"kind"`
+	}{TeamName: teamName, ReviewerID: reviewerID, AuthorID: authorID, Kind: kind}
+	var resp TeamRules
+	if err := c.doIdempotent(ctx, http.MethodPost, "/team/rules/setPair", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeleteTeamPairRule removes the rule for reviewerID on PRs of authorID.
+func (c *Client) DeleteTeamPairRule(ctx context.Context, teamName, reviewerID, authorID string) (*TeamRules, error) {
+	req := struct {
+		TeamName   string `json:"team_name"`
+		ReviewerID string `json:"reviewer_id"`
+		AuthorID   string `json:"author_id"`
+	}{TeamName: teamName, ReviewerID: reviewerID, AuthorID: authorID}
+	var resp TeamRules
+	if err := c.doIdempotent(ctx, http.MethodPost, "/team/rules/deletePair", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// DeactivateUsers deactivates users and reassigns their open reviews.
+// It returns the PRs whose reviewers changed.
+func (c *Client) DeactivateUsers(ctx context.Context, userIDs []string) ([]PullRequest, error) {
+	req := struct {
+		Users []string `json:"users"`
+	}{Users: userIDs}
+	var resp struct {
+		UpdatedPRs []PullRequest `json:"updated_prs"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/team/deactivateUsers", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.UpdatedPRs, nil
+}
+
+// PlanDeactivation returns what DeactivateUsers would do without changing anything.
+// Replacements are picked at random, so the real call may choose others.
+func (c *Client) PlanDeactivation(ctx context.Context, userIDs []string) (*DeactivationPlan, error) {
+	req := struct {
+		Users  []string `json:"users"`
+		DryRun bool     `json:"dry_run"`
+	}{Users: userIDs, DryRun: true}
+	var resp DeactivationPlan
+	if err := c.doIdempotent(ctx, http.MethodPost, "/team/deactivateUsers", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SetIsActive sets the activity flag of a user.
+func (c *Client) SetIsActive(ctx context.Context, userID string, isActive bool) (*User, error) {
+	req := struct {
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
+	}{UserID: userID, IsActive: isActive}
+	var resp struct {
+		User User `json:"user"`
+	}
+	if err := c.doIdempotent(ctx, http.MethodPost, "/users/setIsActive", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.User, nil
+}
+
+// BulkSetIsActive sets the activity flags of many users in one transaction,
+// unknown users are reported in the results. Setting a flag twice changes
+// nothing, so the call is retried like GET ones.
+func (c *Client) BulkSetIsActive(ctx context.Context, users map[string]bool) ([]BulkResult, error) {
+	type item struct {
+		UserID   string `json:"user_id"`
+		IsActive bool   `json:"is_active"`
+	}
+	req := struct {
+		Users []item `json:"users"`
+	}{Users: make([]item, 0, len(users))}
+	for _, id := range slices.Sorted(maps.Keys(users)) {
+		req.Users = append(req.Users, item{UserID: id, IsActive: users[id]})
+	}
+	var resp struct {
+		Results []BulkResult `json:"results"`
+	}
+	if err := c.doIdempotent(ctx, http.MethodPost, "/users/bulkSetIsActive", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Results, nil
+}
+
+// AddAbsence schedules an absence of the user.
+func (c *Client) AddAbsence(ctx context.Context, userID string, startsAt, endsAt time.Time) (*Absence, error) {
+	req := struct {
+		UserID   string    `json:"user_id"`
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+	}{UserID: userID, StartsAt: startsAt, EndsAt: endsAt}
+	var resp struct {
+		Absence Absence `json:"absence"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/absence/add", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Absence, nil
+}
+
+// ListAbsences lists the absences of the user, past ones included.
+func (c *Client) ListAbsences(ctx context.Context, userID string) ([]Absence, error) {
+	var resp struct {
+		Absences []Absence `json:"absences"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/users/absence/list", url.Values{"user_id": {userID}}, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Absences, nil
+}
+
+// DeleteAbsence cancels an absence.
+func (c *Client) DeleteAbsence(ctx context.Context, absenceID string) (*Absence, error) {
+	req := struct {
+		AbsenceID string `json:"absence_id"`
+	}{AbsenceID: absenceID}
+	var resp struct {
+		Absence Absence `json:"absence"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/users/absence/delete", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Absence, nil
+}
+
+// SetMaxOpenReviews limits how many open reviews the user may have, nil removes the limit.
+func (c *Client) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int32) (*ReviewLoad, error) {
+	req := struct {
+		UserID         string `json:"user_id"`
+		MaxOpenReviews *int32 `json:"max_open_reviews"`
+	}{UserID: userID, MaxOpenReviews: maxOpenReviews}
+	var resp ReviewLoad
+	if err := c.doIdempotent(ctx, http.MethodPost, "/users/setMaxOpenReviews", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// SetUserLevel sets the level of the user: LevelRegular, LevelSenior or LevelMaintainer.
+func (c *Client) SetUserLevel(ctx context.Context, userID, level string) error {
+	req := struct {
+		UserID string `json:"user_id"`
+		Level  string `json:"level"`
+	}{UserID: userID, Level: level}
+
+	return c.doIdempotent(ctx, http.MethodPost, "/users/setLevel", nil, req, nil)
+}
+
+// GetUserReviews lists the PRs where the user is a reviewer.
+// The deprecated /pullRequest/userReviews route returns the same data.
+func (c *Client) GetUserReviews(ctx context.Context, userID string) (*UserReviews, error) {
+	return c.GetUserReviewsInRepository(ctx, userID, "")
+}
+
+// GetUserReviewsInRepository is GetUserReviews limited to the PRs of the repository.
+func (c *Client) GetUserReviewsInRepository(ctx context.Context, userID, repository string) (*UserReviews, error) {
+	query := url.Values{"user_id": {userID}}
+	if repository != "" {
+		query.Set("repository", repository)
+	}
+
+	var resp UserReviews
+	if err := c.do(ctx, http.MethodGet, "/users/getReview", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListPRs returns the PRs ordered by ID, an empty repository or status does not filter.
+func (c *Client) ListPRs(ctx context.Context, repository, status string) ([]PullRequestShort, error) {
+	query := url.Values{}
+	if repository != "" {
+		query.Set("repository", repository)
+	}
+	if status != "" {
+		query.Set("status", status)
+	}
+
+	var resp struct {
+		PullRequests []PullRequestShort `json:"pull_requests"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/pullRequest/list", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.PullRequests, nil
+}
+
+// SetRepositoryTeam registers the repository as owned by the team or moves it to the team.
+func (c *Client) SetRepositoryTeam(ctx context.Context, repository, teamName string) (*Repository, error) {
+	req := Repository{RepositoryName: repository, TeamName: teamName}
+	var resp Repository
+	if err := c.doIdempotent(ctx, http.MethodPost, "/repository/setTeam", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListRepositories returns the repositories ordered by name, only the team's if teamName is set.
+func (c *Client) ListRepositories(ctx context.Context, teamName string) ([]Repository, error) {
+	query := url.Values{}
+	if teamName != "" {
+		query.Set("team_name", teamName)
+	}
+
+	var resp struct {
+		Repositories []Repository `json:"repositories"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/repository/list", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Repositories, nil
+}
+
+// CreatePR creates a PR, reviewers are assigned from the author's team.
+func (c *Client) CreatePR(ctx context.Context, req CreatePRRequest) (*PullRequest, error) {
+	var resp struct {
+		PR                PullRequest `json:"pr"`
+		OwnerReviewers    []string    `json:"owner_reviewers"`
+		FallbackReviewers []string    `json:"fallback_reviewers"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/create", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	resp.PR.OwnerReviewers = resp.OwnerReviewers
+	resp.PR.FallbackReviewers = resp.FallbackReviewers
+
+	return &resp.PR, nil
+}
+
+// PreviewAssignment returns the reviewer candidates of a new PR by the author
+// and why the other team members are left out, nothing is written.
+func (c *Client) PreviewAssignment(ctx context.Context, authorID string) (*AssignmentPreview, error) {
+	return c.PreviewAssignmentForChanges(ctx, authorID, "", nil)
+}
+
+// PreviewAssignmentForChanges is PreviewAssignment for a PR changing the files of
+// the repository, the owners of the files by its CODEOWNERS are candidates too.
+func (c *Client) PreviewAssignmentForChanges(ctx context.Context, authorID, repository string, changedFiles []string) (*AssignmentPreview, error) {
+	req := struct {
+		AuthorID     string   `json:"author_id"`
+		Repository   string   `json:"repository,omitempty"`
+		ChangedFiles []string `json:"changed_files,omitempty"`
+	}{AuthorID: authorID, Repository: repository, ChangedFiles: changedFiles}
+	var resp AssignmentPreview
+	if err := c.doIdempotent(ctx, http.MethodPost, "/pullRequest/previewAssignment", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// UploadCodeOwners replaces the CODEOWNERS file of the repository, content is in the GitHub format.
+func (c *Client) UploadCodeOwners(ctx context.Context, repository, content string) (*CodeOwners, error) {
+	req := struct {
+		Repository string `json:"repository"`
+		Content    string `json:"content"`
+	}{Repository: repository, Content: content}
+	var resp CodeOwners
+	if err := c.doIdempotent(ctx, http.MethodPost, "/codeowners/upload", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// MergePR marks a PR without a repository as merged, merging a merged PR is
+// not an error, so the call is retried like GET ones.
+func (c *Client) MergePR(ctx context.Context, prID string) (*PullRequest, error) {
+	return c.MergePRInRepository(ctx, prID, "")
+}
+
+// MergePRInRepository is MergePR for the PR of the repository, PR ids are
+// unique within their repository.
+func (c *Client) MergePRInRepository(ctx context.Context, prID, repository string) (*PullRequest, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		Repository    string `json:"repository,omitempty"`
+	}{PullRequestID: prID, Repository: repository}
+	var resp struct {
+		PR PullRequest `json:"pr"`
+	}
+	if err := c.doIdempotent(ctx, http.MethodPost, "/pullRequest/merge", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.PR, nil
+}
+
+// ReassignReviewer replaces a reviewer of a PR without a repository with
+// another member of their team.
+func (c *Client) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*ReassignResult, error) {
+	return c.ReassignReviewerInRepository(ctx, prID, "", oldUserID)
+}
+
+// ReassignReviewerInRepository is ReassignReviewer for the PR of the repository.
+func (c *Client) ReassignReviewerInRepository(ctx context.Context, prID, repository, oldUserID string) (*ReassignResult, error) {
+	req := struct {
+		PullRequestID string `json:"pull_request_id"`
+		Repository    string `json:"repository,omitempty"`
+		OldUserID     string `json:"old_user_id"`
+	}{PullRequestID: prID, Repository: repository, OldUserID: oldUserID}
+	var resp ReassignResult
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/reassign", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Stats returns assignment statistics.
+func (c *Client) Stats(ctx context.Context) (*Stats, error) {
+	return c.RepositoryStats(ctx, "")
+}
+
+// RepositoryStats returns the statistics of the repository's PRs.
+func (c *Client) RepositoryStats(ctx context.Context, repository string) (*Stats, error) {
+	query := url.Values{}
+	if repository != "" {
+		query.Set("repository", repository)
+	}
+
+	var resp Stats
+	if err := c.do(ctx, http.MethodGet, "/stats", query, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GraphQL runs a GraphQL query. Errors of the query itself are returned
+// in the response, not as an error. The schema has no mutations, so the
+// call is retried like GET ones.
+func (c *Client) GraphQL(ctx context.Context, req GraphQLRequest) (*GraphQLResponse, error) {
+	var resp GraphQLResponse
+	if err := c.doIdempotent(ctx, http.MethodPost, "/graphql", nil, req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
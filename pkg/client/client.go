@@ -0,0 +1,192 @@
+// Package client is a Go client of the reviewer assignment service HTTP API.
+//
+// It covers every route served by the service and is meant to be reused by
+// other services and tools, see cmd/revctl for an example.
+//
+// Every call takes a context that bounds all of its attempts. Service errors
+// are returned as *Error and match the sentinels with errors.Is:
+//
+//	_, err := c.ReassignReviewer(ctx, prID, userID)
+//	if errors.Is(err, client.ErrNoCandidate) { ... }
+//
+// Idempotent calls are retried on transient failures, see RetryPolicy.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultTokenHeader is the header the token is sent in, the service uses it
+// to identify the client for rate limiting.
+const DefaultTokenHeader = "X-API-Key"
+
+// Client calls the service API. It is safe for concurrent use.
+type Client struct {
+	baseURL     *url.URL
+	httpClient  *http.Client
+	token       string
+	tokenHeader string
+	userAgent   string
+	retry       RetryPolicy
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(c *http.Client) Option {
+	return func(cl *Client) {
+		cl.httpClient = c
+	}
+}
+
+// WithToken sets the API token sent with every request.
+func WithToken(token string) Option {
+	return func(cl *Client) {
+		cl.token = token
+	}
+}
+
+// WithTokenHeader changes the header the token is sent in.
+func WithTokenHeader(name string) Option {
+	return func(cl *Client) {
+		cl.tokenHeader = name
+	}
+}
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(ua string) Option {
+	return func(cl *Client) {
+		cl.userAgent = ua
+	}
+}
+
+// New creates a client of the service at baseURL, e.g. "http://localhost:8080".
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:     u,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		tokenHeader: DefaultTokenHeader,
+		userAgent:   "reviewer-client",
+		retry:       DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// do sends a request and decodes a successful response into out.
+// GET requests are idempotent and retried, see RetryPolicy.
+// Error responses are returned as *Error.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	return c.send(ctx, method == http.MethodGet, method, path, query, body, out)
+}
+
+// doIdempotent is do for POST routes that are safe to repeat.
+func (c *Client) doIdempotent(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	return c.send(ctx, true, method, path, query, body, out)
+}
+
+func (c *Client) send(ctx context.Context, idempotent bool, method, path string, query url.Values, body, out any) error {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, data, err := c.attempt(ctx, method, u.String(), payload)
+		if attempt < c.retry.MaxAttempts && retryable(idempotent, resp, err) {
+			// a Retry-After longer than allowed returns the response as is
+			if d, ok := c.retry.delay(ctx, attempt, resp); ok {
+				if err := sleep(ctx, d); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return newError(resp, data)
+		}
+
+		return decode(data, out, method, path)
+	}
+}
+
+// attempt sends a single request and reads the whole response.
+func (c *Client) attempt(ctx context.Context, method, target string, payload []byte) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.token != "" {
+		req.Header.Set(c.tokenHeader, c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+
+	return resp, data, nil
+}
+
+func decode(data []byte, out any, method, path string) error {
+	if out == nil {
+		return nil
+	}
+	if raw, ok := out.(*[]byte); ok {
+		*raw = data
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode %s %s response: %w", method, path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// route is a canned response of the fake server.
+type route struct {
+	status int
+	body   string
+}
+
+// recorded is a request received by the fake server.
+type recorded struct {
+	method string
+	path   string
+	query  string
+	token  string
+	body   map[string]any
+}
+
+func newServer(t *testing.T, routes map[string]route) (*Client, *[]recorded) {
+	t.Helper()
+
+	var requests []recorded
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recorded{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, token: r.Header.Get(DefaultTokenHeader)}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			require.NoError(t, json.Unmarshal(data, &rec.body))
+		}
+		requests = append(requests, rec)
+
+		rt, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(rt.status)
+		_, _ = w.Write([]byte(rt.body))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL+"/", WithToken("secret"))
+	require.NoError(t, err)
+
+	return c, &requests
+}
+
+func TestClient_CoversEveryRoute(t *testing.T) {
+	prJSON := `{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN","assigned_reviewers":["u2","u3"]}`
+	c, requests := newServer(t, map[string]route{
+		"GET /ping":                  {200, "pong"},
+		"GET /team/get":              {200, `{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}]}`},
+		"POST /team/add":             {201, `{"team":{"team_name":"backend","members":[{"user_id":"u1","username":"Alice","is_active":true}]}}`},
+		"POST /team/deactivateUsers": {200, `{"updated_prs":[` + prJSON + `]}`},
+		"POST /users/setIsActive":    {200, `{"user":{"user_id":"u2","username":"Bob","team_name":"backend","is_active":false}}`},
+		"GET /users/getReview":       {200, `{"user_id":"u2","pull_requests":[{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"OPEN"}]}`},
+		"POST /pullRequest/create":   {201, `{"pr":` + prJSON + `}`},
+		"POST /pullRequest/merge":    {200, `{"pr":{"pull_request_id":"pr-1","pull_request_name":"Add search","author_id":"u1","status":"MERGED","assigned_reviewers":["u2"],"mergedAt":"2025-10-24T12:34:56Z"}}`},
+		"POST /pullRequest/reassign": {200, `{"pr":` + prJSON + `,"replaced_by":"u3"}`},
+		"GET /stats":                 {200, `{"top_reviewers":[{"reviewer_id":"u2","assignment_count":3}],"pr_status_distribution":[{"status":"OPEN","count":1}],"total_active_users":2}`},
+		"POST /graphql":              {200, `{"data":{"team":null}}`},
+	})
+	ctx := context.Background()
+
+	require.NoError(t, c.Ping(ctx))
+
+	team, err := c.GetTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", team.Members[0].Username)
+
+	team, err = c.CreateTeam(ctx, Team{TeamName: "backend", Members: []TeamMember{{UserID: "u1", Username: "Alice", IsActive: true}}})
+	require.NoError(t, err)
+	assert.Equal(t, "backend", team.TeamName)
+
+	prs, err := c.DeactivateUsers(ctx, []string{"u4"})
+	require.NoError(t, err)
+	assert.Len(t, prs, 1)
+
+	user, err := c.SetIsActive(ctx, "u2", false)
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+
+	reviews, err := c.GetUserReviews(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, "pr-1", reviews.PullRequests[0].PullRequestID)
+
+	pr, err := c.CreatePR(ctx, CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "Add search", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+	pr, err = c.MergePR(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusMerged, pr.Status)
+	require.NotNil(t, pr.MergedAt)
+
+	res, err := c.ReassignReviewer(ctx, "pr-1", "u2")
+	require.NoError(t, err)
+	assert.Equal(t, "u3", res.ReplacedBy)
+
+	stats, err := c.Stats(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, stats.TotalActiveUsers)
+
+	gql, err := c.GraphQL(ctx, GraphQLRequest{Query: `{ team(name: "x") { name } }`})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"team":null}`, string(gql.Data))
+
+	require.Len(t, *requests, 11)
+	for _, r := range *requests {
+		assert.Equal(t, "secret", r.token, r.path)
+	}
+	byPath := map[string]recorded{}
+	for _, r := range *requests {
+		byPath[r.path] = r
+	}
+	assert.Equal(t, "team_name=backend", byPath["/team/get"].query)
+	assert.Equal(t, "user_id=u2", byPath["/users/getReview"].query)
+	assert.Equal(t, map[string]any{"user_id": "u2", "is_active": false}, byPath["/users/setIsActive"].body)
+	assert.Equal(t, map[string]any{"pull_request_id": "pr-1", "old_user_id": "u2"}, byPath["/pullRequest/reassign"].body)
+	assert.Equal(t, map[string]any{"users": []any{"u4"}}, byPath["/team/deactivateUsers"].body)
+}
+
+func TestClient_DecodesErrorResponses(t *testing.T) {
+	c, _ := newServer(t, map[string]route{
+		"POST /pullRequest/reassign": {409, `{"error":{"code":"PR_MERGED","message":"cannot reassign on merged PR","request_id":"host/1"}}`},
+		"POST /team/add":             {400, `{"error":{"code":"INVALID_INPUT","message":"input data is invalid","details":[{"field":"members[0].username","reason":"must not be empty"}]}}`},
+	})
+	ctx := context.Background()
+
+	_, err := c.ReassignReviewer(ctx, "pr-1", "u2")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.Equal(t, "PR_MERGED", apiErr.Code)
+	assert.Equal(t, "host/1", apiErr.RequestID)
+	assert.Equal(t, "PR_MERGED: cannot reassign on merged PR (request id host/1)", err.Error())
+
+	_, err = c.CreateTeam(ctx, Team{TeamName: "backend"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, []FieldError{{Field: "members[0].username", Reason: "must not be empty"}}, apiErr.Details)
+
+	// a route the server does not know, e.g. a disabled feature
+	_, err = c.Stats(ctx)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "HTTP_404", apiErr.Code)
+}
+
+func TestNew_RejectsInvalidBaseURL(t *testing.T) {
+	_, err := New("localhost:8080")
+	assert.Error(t, err)
+}
+
+func TestError_MatchesSentinels(t *testing.T) {
+	c, _ := newServer(t, map[string]route{
+		"POST /pullRequest/reassign": {409, `{"error":{"code":"NO_CANDIDATE","message":"no suitable candidate found"}}`},
+	})
+
+	_, err := c.ReassignReviewer(context.Background(), "pr-1", "u2")
+	assert.ErrorIs(t, err, ErrNoCandidate)
+	assert.NotErrorIs(t, err, ErrNotAssigned)
+	assert.ErrorIs(t, fmt.Errorf("wrapped: %w", err), ErrNoCandidate)
+}
+
+// flaky fails the first n requests with status, then answers 200.
+func flaky(n *atomic.Int32, status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		if n.Add(-1) >= 0 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestClient_RetriesIdempotentCalls(t *testing.T) {
+	var failures atomic.Int32
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		flaky(&failures, http.StatusServiceUnavailable, `{"pr":{"pull_request_id":"pr-1","status":"MERGED"}}`)(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// merge is idempotent
+	failures.Store(2)
+	pr, err := c.MergePR(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusMerged, pr.Status)
+	assert.EqualValues(t, 3, calls.Load())
+
+	// create is not, a 503 might have come after the PR was created
+	failures.Store(1)
+	calls.Store(0)
+	_, err = c.CreatePR(ctx, CreatePRRequest{PullRequestID: "pr-1"})
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.EqualValues(t, 1, calls.Load())
+
+	// gives up after MaxAttempts
+	failures.Store(5)
+	calls.Store(0)
+	_, err = c.MergePR(ctx, "pr-1")
+	assert.Error(t, err)
+	assert.EqualValues(t, 3, calls.Load())
+}
+
+func TestClient_RetriesRateLimitedCalls(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(1)
+	srv := httptest.NewServer(flaky(&failures, http.StatusTooManyRequests, `{"pr":{"pull_request_id":"pr-1","status":"OPEN"}}`))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}))
+	require.NoError(t, err)
+
+	// rate limited requests are rejected before handling, so even create is retried
+	_, err = c.CreatePR(context.Background(), CreatePRRequest{PullRequestID: "pr-1"})
+	assert.NoError(t, err)
+}
+
+func TestClient_HonoursRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var retryAfter atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", retryAfter.Load().(string))
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":{"code":"RATE_LIMITED","message":"too many requests"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"pr":{"pull_request_id":"pr-1","status":"OPEN"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, MaxRetryAfter: 2 * time.Second}))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// waited out in full, even beyond MaxDelay
+	retryAfter.Store("1")
+	started := time.Now()
+	_, err = c.MergePR(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(started), time.Second)
+	assert.EqualValues(t, 2, calls.Load())
+
+	// longer than the policy allows: the 429 is returned without waiting
+	retryAfter.Store("30")
+	calls.Store(0)
+	started = time.Now()
+	_, err = c.MergePR(ctx, "pr-1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.EqualValues(t, 1, calls.Load())
+
+	// longer than the context allows
+	retryAfter.Store("1")
+	calls.Store(0)
+	short, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer cancel()
+	_, err = c.MergePR(short, "pr-1")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestClient_StopsRetryingWhenContextIsDone(t *testing.T) {
+	var failures atomic.Int32
+	failures.Store(100)
+	srv := httptest.NewServer(flaky(&failures, http.StatusServiceUnavailable, `{}`))
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 100, BaseDelay: time.Second, MaxDelay: time.Second}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err = c.Stats(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), time.Second)
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Error is an error response of the service.
+type Error struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int `json:"-"`
+	// Code is the error code, e.g. NOT_FOUND or PR_MERGED.
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+	// RequestID identifies the request in the service logs.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// FieldError describes why a single input field was rejected.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Errors returned by the service, match them with errors.Is:
+//
+//	if errors.Is(err, client.ErrPRMerged) { ... }
+var (
+	ErrTeamExists   = &Error{Code: "TEAM_EXISTS"}
+	ErrPRExists     = &Error{Code: "PR_EXISTS"}
+	ErrPRMerged     = &Error{Code: "PR_MERGED"}
+	ErrNotAssigned  = &Error{Code: "NOT_ASSIGNED"}
+	ErrNoCandidate  = &Error{Code: "NO_CANDIDATE"}
+	ErrNotFound     = &Error{Code: "NOT_FOUND"}
+	ErrInvalidInput = &Error{Code: "INVALID_INPUT"}
+	ErrRateLimited  = &Error{Code: "RATE_LIMITED"}
+	ErrInternal     = &Error{Code: "INTERNAL_ERROR"}
+)
+
+// Is reports whether target is an *Error with the same code,
+// so errors.Is matches the sentinels above.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" && len(e.Details) == 0 && e.RequestID == "" {
+		return e.Code
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", e.Code, e.Message)
+	for i, d := range e.Details {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		fmt.Fprintf(&b, "%s: %s", d.Field, d.Reason)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " (request id %s)", e.RequestID)
+	}
+
+	return b.String()
+}
+
+// newError decodes an error response. Bodies that are not an ErrorResponse,
+// e.g. from a proxy, keep the status text as the message.
+func newError(resp *http.Response, body []byte) *Error {
+	var payload struct {
+		Error *Error `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Error == nil || payload.Error.Code == "" {
+		message := strings.TrimSpace(string(body))
+		if message == "" {
+			message = http.StatusText(resp.StatusCode)
+		}
+		return &Error{StatusCode: resp.StatusCode, Code: "HTTP_" + fmt.Sprint(resp.StatusCode), Message: message}
+	}
+
+	payload.Error.StatusCode = resp.StatusCode
+	return payload.Error
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed calls are retried.
+//
+// Idempotent calls (GET routes, MergePR, MergePRInRepository, SetIsActive and
+// GraphQL queries) are retried on network errors and on 502, 503 and 504 responses.
+// Every call is retried on 429, the service rejects those before handling them.
+//
+// Retry-After of a response is always waited out in full, retrying earlier
+// would only be rejected again. When it is longer than MaxRetryAfter or than
+// what is left of the context deadline, the response is returned as an *Error
+// right away instead.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt, 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is doubled after every attempt, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxRetryAfter is the longest Retry-After to wait for, 0 means any.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	// long enough for a rate limit to refill
+	MaxRetryAfter: time.Minute,
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(cl *Client) {
+		cl.retry = p
+	}
+}
+
+// retryable reports whether a failed attempt may be repeated.
+func retryable(idempotent bool, resp *http.Response, err error) bool {
+	if err != nil {
+		// the caller gave up, retrying would not help
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		return idempotent
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// delay returns how long to wait before the given retry (1 for the first one),
+// at least the Retry-After of the response. It reports false when Retry-After
+// is longer than the policy or ctx allows.
+func (p RetryPolicy) delay(ctx context.Context, retry int, resp *http.Response) (time.Duration, bool) {
+	d := p.BaseDelay << (retry - 1)
+	if d > p.MaxDelay || d <= 0 {
+		d = p.MaxDelay
+	}
+	// jitter keeps clients that failed together from retrying together
+	d = d/2 + rand.N(d/2+1)
+
+	after, ok := retryAfter(resp)
+	if !ok {
+		return d, true
+	}
+	if p.MaxRetryAfter > 0 && after > p.MaxRetryAfter {
+		return 0, false
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < after {
+		return 0, false
+	}
+
+	return max(d, after), true
+}
+
+// retryAfter parses the Retry-After header: delay seconds or an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
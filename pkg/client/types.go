@@ -0,0 +1,270 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PR statuses.
+const (
+	StatusOpen   = "OPEN"
+	StatusMerged = "MERGED"
+)
+
+// TeamMember is a user as seen from their team.
+type TeamMember struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+}
+
+// Team is a group of users with a unique name.
+type Team struct {
+	TeamName string       `json:"team_name"`
+	Members  []TeamMember `json:"members"`
+}
+
+// User is a team member with their team name.
+type User struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+}
+
+// BulkResult is the outcome for one user of /users/bulkSetIsActive:
+// "updated", "unchanged" or "not_found". User is nil for unknown users.
+type BulkResult struct {
+	UserID string `json:"user_id"`
+	Result string `json:"result"`
+	User   *User  `json:"user,omitempty"`
+}
+
+// UpsertMembersRequest is the body of /team/members/upsert.
+type UpsertMembersRequest struct {
+	TeamName string       `json:"team_name"`
+	Members  []TeamMember `json:"members,omitempty"`
+	Remove   []string     `json:"remove,omitempty"`
+}
+
+// MemberResult is the outcome for one member of /team/members/upsert: "added",
+// "moved" (from FromTeam), "updated", "unchanged", "removed", "not_found" or "in_use".
+type MemberResult struct {
+	UserID   string `json:"user_id"`
+	Result   string `json:"result"`
+	FromTeam string `json:"from_team,omitempty"`
+}
+
+// UpsertMembersResult is the result of /team/members/upsert.
+type UpsertMembersResult struct {
+	Team    Team           `json:"team"`
+	Results []MemberResult `json:"results"`
+}
+
+// Absence is a period when a user gets no new reviews, it ends right before EndsAt.
+// ReviewsReleased is set once the user's open reviews were reassigned.
+type Absence struct {
+	AbsenceID       string    `json:"absence_id"`
+	UserID          string    `json:"user_id"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	ReviewsReleased bool      `json:"reviews_released"`
+}
+
+// ReviewLoad is the review limit of a user and their current open reviews,
+// MaxOpenReviews is nil without a limit.
+type ReviewLoad struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int32 `json:"max_open_reviews"`
+	OpenReviews    int64  `json:"open_reviews"`
+}
+
+// Reviewer levels, senior and maintainer users count as seniors.
+const (
+	LevelRegular    = "regular"
+	LevelSenior     = "senior"
+	LevelMaintainer = "maintainer"
+)
+
+// Pair rule kinds.
+const (
+	PairNever  = "never"
+	PairPrefer = "prefer"
+)
+
+// PairRule keeps ReviewerID off the PRs of AuthorID (PairNever) or picks them
+// before other candidates (PairPrefer).
+type PairRule struct {
+	ReviewerID string `json:"reviewer_id"`
+	AuthorID   string `json:"author_id"`
+	Kind       string `json:"kind"`
+}
+
+// TeamRules are the reviewer rules of a team.
+type TeamRules struct {
+	TeamName      string     `json:"team_name"`
+	RequireSenior bool       `json:"require_senior"`
+	PairRules     []PairRule `json:"pair_rules"`
+}
+
+// PullRequest is a PR with its assigned reviewers. MissingSenior is set when the
+// author's team requires a senior reviewer and none of them is one.
+type PullRequest struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	Repository        string     `json:"repository,omitempty"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	MissingSenior     bool       `json:"missing_senior,omitempty"`
+	CreatedAt         *time.Time `json:"createdAt,omitempty"`
+	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	// OwnerReviewers are the reviewers owning the changed files and FallbackReviewers
+	// the reviewers taken from fallback teams, only CreatePR sets them.
+	OwnerReviewers    []string `json:"-"`
+	FallbackReviewers []string `json:"-"`
+}
+
+// PullRequestShort is a PR without its reviewers, MissingSenior as in PullRequest.
+type PullRequestShort struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	Status          string `json:"status"`
+	Repository      string `json:"repository,omitempty"`
+	MissingSenior   bool   `json:"missing_senior,omitempty"`
+}
+
+// Repository is a repository and the team owning it.
+type Repository struct {
+	RepositoryName string `json:"repository_name"`
+	TeamName       string `json:"team_name"`
+}
+
+// CreatePRRequest is the body of /pullRequest/create. Repository is optional and must be
+// registered, the owners of ChangedFiles by its CODEOWNERS file are preferred as reviewers.
+type CreatePRRequest struct {
+	PullRequestID   string   `json:"pull_request_id"`
+	PullRequestName string   `json:"pull_request_name"`
+	AuthorID        string   `json:"author_id"`
+	Repository      string   `json:"repository,omitempty"`
+	ChangedFiles    []string `json:"changed_files,omitempty"`
+}
+
+// ReassignResult is the result of /pullRequest/reassign.
+type ReassignResult struct {
+	PR         PullRequest `json:"pr"`
+	ReplacedBy string      `json:"replaced_by"`
+}
+
+// Exclusion is a user who is not a reviewer candidate and why: a team mate of
+// the author, an owner of the changed files or a member of a fallback team.
+type Exclusion struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+	Reason   string `json:"reason"`
+}
+
+// FallbackCandidate is a possible reviewer from a fallback team.
+type FallbackCandidate struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+// AssignmentPreview is the result of /pullRequest/previewAssignment.
+type AssignmentPreview struct {
+	AuthorID            string              `json:"author_id"`
+	TeamName            string              `json:"team_name"`
+	ReviewersCount      int                 `json:"reviewers_count"`
+	OwnerCandidates     []string            `json:"owner_candidates"`
+	Candidates          []string            `json:"candidates"`
+	Excluded            []Exclusion         `json:"excluded"`
+	FallbackCandidates  []FallbackCandidate `json:"fallback_candidates"`
+	RequireSenior       bool                `json:"require_senior"`
+	SeniorCandidates    []string            `json:"senior_candidates"`
+	PreferredCandidates []string            `json:"preferred_candidates"`
+}
+
+// CodeOwnersRule is a parsed rule of an uploaded CODEOWNERS file.
+type CodeOwnersRule struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+// CodeOwners is the result of /codeowners/upload, UnknownOwners match no user or team.
+type CodeOwners struct {
+	Repository    string           `json:"repository"`
+	Rules         []CodeOwnersRule `json:"rules"`
+	UnknownOwners []string         `json:"unknown_owners"`
+}
+
+// Reassignment is a review taken from a deactivated user,
+// NewReviewerID is empty when nobody could take it over.
+type Reassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	Repository    string `json:"repository,omitempty"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+}
+
+// DeactivationReport lists what happened to the reviews of a deactivated user,
+// reviewers of merged PRs never change, so those are skipped.
+type DeactivationReport struct {
+	UserID       string   `json:"user_id"`
+	Reassigned   []string `json:"reassigned"`
+	LostReviewer []string `json:"lost_reviewer"`
+	Skipped      []string `json:"skipped"`
+}
+
+// DeactivationPlan is the result of /team/deactivateUsers with dry_run.
+type DeactivationPlan struct {
+	UpdatedPRs    []PullRequest        `json:"updated_prs"`
+	Reassignments []Reassignment       `json:"reassignments"`
+	Users         []DeactivationReport `json:"users"`
+	DryRun        bool                 `json:"dry_run"`
+}
+
+// UserReviews lists the PRs where a user is a reviewer.
+type UserReviews struct {
+	UserID       string             `json:"user_id"`
+	PullRequests []PullRequestShort `json:"pull_requests"`
+}
+
+// ReviewerStat is the number of current assignments of a reviewer.
+type ReviewerStat struct {
+	ReviewerID      string `json:"reviewer_id"`
+	AssignmentCount int64  `json:"assignment_count"`
+}
+
+// PRStatusStat is the number of PRs in a status.
+type PRStatusStat struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+// Stats is the result of /stats.
+type Stats struct {
+	TopReviewers         []ReviewerStat `json:"top_reviewers"`
+	PRStatusDistribution []PRStatusStat `json:"pr_status_distribution"`
+	TotalActiveUsers     int64          `json:"total_active_users"`
+}
+
+// GraphQLRequest is the body of /graphql.
+type GraphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// GraphQLError is an error of a GraphQL query.
+type GraphQLError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// GraphQLResponse is the result of a GraphQL query, Data is left undecoded.
+type GraphQLResponse struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []GraphQLError  `json:"errors,omitempty"`
+}
@@ -3,40 +3,23 @@
 package tests
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"math/rand"
-	"net/http"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/Joskmo/avito-trainee-assignment-api/pkg/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-const baseURL = "http://localhost:8080"
-
-// Структуры для запросов и ответов (упрощенные для теста)
-type TeamMember struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
-}
-
-type CreateTeamRequest struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
-}
-
-type CreatePRRequest struct {
-	PullRequestID string `json:"pull_request_id"`
-	AuthorID      string `json:"author_id"`
-	PRName        string `json:"pull_request_name"`
-}
-
-type DeactivateRequest struct {
-	Users []string `json:"users"`
+func baseURL() string {
+	if u := os.Getenv("E2E_BASE_URL"); u != "" {
+		return u
+	}
+	return "http://localhost:8080"
 }
 
 func randomString(n int) string {
@@ -55,67 +38,79 @@ func TestE2E_FullFlow(t *testing.T) {
 	user1 := fmt.Sprintf("u1_%d", rnd.Int())
 	user2 := fmt.Sprintf("u2_%d", rnd.Int())
 	user3 := fmt.Sprintf("u3_%d", rnd.Int())
+	prID := "pr_" + randomString(8)
+
+	c, err := client.New(baseURL())
+	require.NoError(t, err)
 
-	client := &http.Client{Timeout: 5 * time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
 	// 1. Создание команды
 	t.Run("Create Team", func(t *testing.T) {
-		reqBody := CreateTeamRequest{
+		team, err := c.CreateTeam(ctx, client.Team{
 			TeamName: teamName,
-			Members: []TeamMember{
+			Members: []client.TeamMember{
 				{UserID: user1, Username: "Alice", IsActive: true},
 				{UserID: user2, Username: "Bob", IsActive: true},
 				{UserID: user3, Username: "Charlie", IsActive: true},
 			},
-		}
-		body, _ := json.Marshal(reqBody)
-		resp, err := client.Post(baseURL+"/team/add", "application/json", bytes.NewBuffer(body))
+		})
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Len(t, team.Members, 3)
+
+		// повторное создание — TEAM_EXISTS
+		_, err = c.CreateTeam(ctx, client.Team{
+			TeamName: teamName,
+			Members:  []client.TeamMember{{UserID: user1, Username: "Alice", IsActive: true}},
+		})
+		assert.ErrorIs(t, err, client.ErrTeamExists)
 	})
 
 	// 2. Создание PR
 	t.Run("Create PR", func(t *testing.T) {
-		reqBody := CreatePRRequest{
-			PullRequestID: "pr_" + randomString(8),
-			AuthorID:      user1,
-			PRName:        "E2E Feature",
-		}
-		body, _ := json.Marshal(reqBody)
-		resp, err := client.Post(baseURL+"/pullRequest/create", "application/json", bytes.NewBuffer(body))
+		pr, err := c.CreatePR(ctx, client.CreatePRRequest{
+			PullRequestID:   prID,
+			PullRequestName: "E2E Feature",
+			AuthorID:        user1,
+		})
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		assert.Equal(t, http.StatusCreated, resp.StatusCode)
-
-		var result map[string]interface{}
-		json.NewDecoder(resp.Body).Decode(&result)
-		prData := result["pr"].(map[string]interface{})
-		// prID := prData["pull_request_id"].(string)
 
 		// Проверяем, что ревьюверы назначены (должен быть кто-то из u2 или u3)
-		reviewers := prData["assigned_reviewers"].([]interface{})
-		assert.NotEmpty(t, reviewers, "Reviewers should be assigned")
+		assert.NotEmpty(t, pr.AssignedReviewers, "Reviewers should be assigned")
+		assert.NotContains(t, pr.AssignedReviewers, user1, "Author must not review own PR")
+
+		_, err = c.CreatePR(ctx, client.CreatePRRequest{PullRequestID: prID, PullRequestName: "E2E Feature", AuthorID: user1})
+		assert.ErrorIs(t, err, client.ErrPRExists)
 	})
 
 	// 3. Получение статистики
 	t.Run("Get Stats", func(t *testing.T) {
-		resp, err := client.Get(baseURL + "/stats")
+		stats, err := c.Stats(ctx)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Positive(t, stats.TotalActiveUsers)
 	})
 
 	// 4. Массовая деактивация и проверка переназначения
 	t.Run("Deactivate User", func(t *testing.T) {
 		// Деактивируем user2 и user3
-		reqBody := DeactivateRequest{
-			Users: []string{user2, user3},
+		_, err := c.DeactivateUsers(ctx, []string{user2, user3})
+		require.NoError(t, err)
+
+		team, err := c.GetTeam(ctx, teamName)
+		require.NoError(t, err)
+		for _, m := range team.Members {
+			assert.Equal(t, m.UserID == user1, m.IsActive, m.UserID)
 		}
-		body, _ := json.Marshal(reqBody)
-		resp, err := client.Post(baseURL+"/team/deactivateUsers", "application/json", bytes.NewBuffer(body))
+	})
+
+	// 5. Слияние PR и запрет переназначения после него
+	t.Run("Merge PR", func(t *testing.T) {
+		pr, err := c.MergePR(ctx, prID)
 		require.NoError(t, err)
-		defer resp.Body.Close()
-		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, client.StatusMerged, pr.Status)
+
+		_, err = c.ReassignReviewer(ctx, prID, user2)
+		assert.ErrorIs(t, err, client.ErrPRMerged)
 	})
 }